@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/metrics"
+)
+
+const usage = `usage:
+  server                       start the server
+  server migrate up            apply all migrations
+  server migrate down [N]      roll back N migrations, 1 by default
+  server migrate version       print the current migration version
+  server migrate force V       set the migration version without running migrations
+  server bootstrap-admin EMAIL make the user an admin, a new user is created with the password read from stdin
+  server classify-messages     detect kind of the saved messages again`
+
+var errUsage = errors.New(usage)
+
+// runCommand executes the CLI subcommand given in args.
+func runCommand(cfg *config.Config, log *logger.Logger, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrateCommand(cfg, log, args[1:])
+	case "bootstrap-admin":
+		return runBootstrapAdminCommand(cfg, log, args[1:])
+	case "classify-messages":
+		return runClassifyMessagesCommand(cfg, log)
+	default:
+		return fmt.Errorf("unknown command %q\n%w", args[0], errUsage)
+	}
+}
+
+func runMigrateCommand(cfg *config.Config, log *logger.Logger, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	migrator, err := store.NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := migrator.Close(); err != nil {
+			log.Error().Err(err).Msg("close migrator")
+		}
+	}()
+
+	switch args[0] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of migrations %q\n%w", args[1], errUsage)
+			}
+		}
+
+		err = migrator.Down(steps)
+	case "version":
+	case "force":
+		if len(args) < 2 { //nolint:gomnd // command and version
+			return errUsage
+		}
+
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			return fmt.Errorf("invalid version %q\n%w", args[1], errUsage)
+		}
+
+		err = migrator.Force(version)
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%w", args[0], errUsage)
+	}
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+
+	log.Info().Uint("version", version).Bool("dirty", dirty).Msg("migration version")
+
+	return nil
+}
+
+// runBootstrapAdminCommand gives the admin role to the user with the email when there are no admins yet.
+// Password of a new user is read from the first line of stdin.
+func runBootstrapAdminCommand(cfg *config.Config, log *logger.Logger, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	store, err := store.New(cfg, log, metrics.NewRegistry())
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := store.Close(); err != nil {
+			log.Error().Err(err).Msg("close store")
+		}
+	}()
+
+	email := args[0]
+
+	user, err := store.WebUser.GetWebUserByEmail(context.Background(), email)
+	if err != nil {
+		return err
+	}
+
+	var password string
+	if user == nil {
+		fmt.Fprintf(os.Stderr, "password for new user %s: ", email)
+
+		password, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && password == "" {
+			return fmt.Errorf("read password: %w", err)
+		}
+
+		password = strings.TrimRight(password, "\r\n")
+	}
+
+	err = service.NewAccessService(store, log).BootstrapAdmin(context.Background(), email, password)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("admin created")
+
+	return nil
+}
+
+// runClassifyMessagesCommand classifies the saved messages, e.g. the ones saved before the classifier was added.
+func runClassifyMessagesCommand(cfg *config.Config, log *logger.Logger) error {
+	store, err := store.New(cfg, log, metrics.NewRegistry())
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := store.Close(); err != nil {
+			log.Error().Err(err).Msg("close store")
+		}
+	}()
+
+	updated, err := service.NewClassifierService(store, log).ReclassifyMessages(context.Background())
+	if err != nil {
+		return err
+	}
+
+	log.Info().Int("updated", updated).Msg("messages classified")
+
+	return nil
+}
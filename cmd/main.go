@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	_ "github.com/lib/pq"
 
@@ -11,6 +16,8 @@ import (
 	"github.com/VladPetriv/scanner_backend/internal/store"
 	"github.com/VladPetriv/scanner_backend/pkg/config"
 	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/mailer"
+	"github.com/VladPetriv/scanner_backend/pkg/metrics"
 	"github.com/VladPetriv/scanner_backend/pkg/server"
 )
 
@@ -22,27 +29,104 @@ func main() {
 
 	log := logger.Get(cfg)
 
-	store, err := store.New(cfg, log)
+	if len(os.Args) > 1 {
+		if err := runCommand(cfg, log, os.Args[1:]); err != nil {
+			log.Fatal().Err(err).Msgf("run %q command", os.Args[1])
+		}
+
+		return
+	}
+
+	registry := metrics.NewRegistry()
+
+	store, err := store.New(cfg, log, registry)
 	if err != nil {
 		log.Fatal().Err(err).Msg("create store")
 	}
 
-	serviceManger, err := service.NewManager(store, log)
+	mailer, err := mailer.New(cfg, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("create mailer")
+	}
+
+	serviceManger, err := service.NewManager(store, log, cfg, mailer)
 	if err != nil {
 		log.Fatal().Err(err).Msg("create service manager")
 	}
 
-	queue := kafka.New(serviceManger, cfg, log)
-	go queue.SaveChannelsData()
-	go queue.SaveMessagesData()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var consumers sync.WaitGroup
+
+	queue := kafka.New(serviceManger, cfg, log, registry)
+
+	consumers.Add(3)
+	go func() {
+		defer consumers.Done()
+		queue.SaveChannelsData(ctx)
+	}()
+	go func() {
+		defer consumers.Done()
+		queue.SaveMessagesData(ctx)
+	}()
+	go func() {
+		defer consumers.Done()
+		queue.RunReplays(ctx)
+	}()
 
 	srv := new(server.Server)
 
-	httpHandler := handler.NewHandler(serviceManger, cfg.CookieSecret, log)
+	httpHandler := handler.NewHandler(serviceManger, cfg, log, registry, queue, queue)
+
+	go func() {
+		log.Info().Msgf("starting server at port: %s", cfg.Port)
+
+		if err := srv.Run(cfg.Port, httpHandler.InitRouter()); err != nil {
+			log.Error().Err(err).Msgf("start server at port: %s", cfg.Port)
+		}
+
+		// Server which failed to start must stop the whole application.
+		stop()
+	}()
+
+	<-ctx.Done()
+
+	log.Info().Msg("shutting down")
+
+	shutdown(cfg, log, srv, &consumers, store)
+}
+
+// shutdown drains HTTP requests, waits for the consumers and replays and closes the store and the log file
+// within the configured shutdown timeout.
+func shutdown(cfg *config.Config, log *logger.Logger, srv *server.Server, consumers *sync.WaitGroup, store *store.Store) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("shutdown server")
+	}
+
+	consumersStopped := make(chan struct{})
+
+	go func() {
+		consumers.Wait()
+		close(consumersStopped)
+	}()
+
+	select {
+	case <-consumersStopped:
+	case <-ctx.Done():
+		log.Error().Msg("consumers and replays didn't stop before shutdown timeout")
+	}
+
+	if err := store.Close(); err != nil {
+		log.Error().Err(err).Msg("close store")
+	}
 
-	log.Info().Msgf("starting server at port: %s", cfg.Port)
+	log.Info().Msg("application stopped")
 
-	if err = srv.Run(cfg.Port, httpHandler.InitRouter()); err != nil {
-		log.Fatal().Err(err).Msgf("start server at port: %s", cfg.Port)
+	if err := log.Close(); err != nil {
+		log.Error().Err(err).Msg("close log file")
 	}
 }
@@ -0,0 +1,12 @@
+// Package db contains database migrations embedded into the binary.
+package db
+
+import "embed"
+
+// Migrations contains SQL migrations in the golang-migrate file naming format.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// MigrationsDir is a directory of the migrations inside of the embedded file system.
+const MigrationsDir = "migrations"
@@ -0,0 +1,8 @@
+CREATE TABLE channel_history (
+  id SERIAL PRIMARY KEY,
+  channel_id INT NOT NULL,
+  title VARCHAR(255),
+  image_url TEXT NOT NULL,
+  changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_channel FOREIGN KEY(channel_id) REFERENCES channel(id) ON DELETE CASCADE
+);
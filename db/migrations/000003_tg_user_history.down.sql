@@ -0,0 +1,5 @@
+DROP TABLE tg_user_history;
+
+DROP INDEX tg_user_tg_id_key;
+
+ALTER TABLE tg_user DROP COLUMN tg_id;
@@ -0,0 +1,13 @@
+ALTER TABLE tg_user ADD COLUMN tg_id BIGINT;
+
+CREATE UNIQUE INDEX tg_user_tg_id_key ON tg_user(tg_id) WHERE tg_id IS NOT NULL;
+
+CREATE TABLE tg_user_history (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  username VARCHAR(255) NOT NULL,
+  fullname VARCHAR(255) NOT NULL,
+  image_url TEXT NOT NULL,
+  changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES tg_user(id) ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+CREATE TABLE session (
+  id VARCHAR(64) PRIMARY KEY,
+  user_id INT NOT NULL,
+  user_agent TEXT NOT NULL DEFAULT '',
+  ip_address VARCHAR(64) NOT NULL DEFAULT '',
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP NOT NULL,
+  CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES web_user(id) ON DELETE CASCADE
+);
+
+CREATE INDEX session_user_id_idx ON session(user_id);
@@ -0,0 +1,11 @@
+CREATE TABLE password_reset (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP,
+  CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES web_user(id) ON DELETE CASCADE
+);
+
+CREATE INDEX password_reset_user_id_idx ON password_reset(user_id);
@@ -0,0 +1 @@
+ALTER TABLE web_user DROP COLUMN email_verified;
@@ -0,0 +1,4 @@
+ALTER TABLE web_user ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Accounts registered before email verification was introduced keep their abilities.
+UPDATE web_user SET email_verified = TRUE;
@@ -0,0 +1,4 @@
+DROP TABLE recovery_code;
+
+ALTER TABLE web_user DROP COLUMN totp_enabled;
+ALTER TABLE web_user DROP COLUMN totp_secret;
@@ -0,0 +1,12 @@
+ALTER TABLE web_user ADD COLUMN totp_secret VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE web_user ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE recovery_code (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  code_hash VARCHAR(64) NOT NULL,
+  used_at TIMESTAMP,
+  CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES web_user(id) ON DELETE CASCADE
+);
+
+CREATE INDEX recovery_code_user_id_idx ON recovery_code(user_id);
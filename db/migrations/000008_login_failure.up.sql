@@ -0,0 +1,9 @@
+CREATE TABLE login_failure (
+  id SERIAL PRIMARY KEY,
+  email VARCHAR(255) NOT NULL,
+  ip_address VARCHAR(64) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX login_failure_email_idx ON login_failure(email, created_at);
+CREATE INDEX login_failure_ip_address_idx ON login_failure(ip_address, created_at);
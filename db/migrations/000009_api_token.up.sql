@@ -0,0 +1,13 @@
+CREATE TABLE api_token (
+  id SERIAL PRIMARY KEY,
+  user_id INT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  token_hash VARCHAR(64) NOT NULL UNIQUE,
+  scopes VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMP,
+  last_used_at TIMESTAMP,
+  CONSTRAINT fk_user FOREIGN KEY(user_id) REFERENCES web_user(id) ON DELETE CASCADE
+);
+
+CREATE INDEX api_token_user_id_idx ON api_token(user_id);
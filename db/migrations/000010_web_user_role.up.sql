@@ -0,0 +1,2 @@
+ALTER TABLE web_user ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'member'
+  CONSTRAINT web_user_role_check CHECK (role IN ('admin', 'moderator', 'member'));
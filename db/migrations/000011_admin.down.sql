@@ -0,0 +1,5 @@
+DROP TABLE ingestion_error;
+
+ALTER TABLE web_user DROP COLUMN banned_at;
+
+ALTER TABLE channel DROP COLUMN enabled;
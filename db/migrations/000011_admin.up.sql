@@ -0,0 +1,16 @@
+ALTER TABLE channel ADD COLUMN enabled BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE web_user ADD COLUMN banned_at TIMESTAMP;
+
+CREATE TABLE ingestion_error (
+  id SERIAL PRIMARY KEY,
+  topic VARCHAR(255) NOT NULL,
+  partition INT NOT NULL,
+  record_offset BIGINT NOT NULL,
+  content_type VARCHAR(64) NOT NULL DEFAULT '',
+  reason TEXT NOT NULL,
+  value BYTEA NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ingestion_error_created_at_idx ON ingestion_error(created_at);
@@ -0,0 +1,3 @@
+DROP TABLE buffered_record;
+
+ALTER TABLE channel DROP COLUMN hidden;
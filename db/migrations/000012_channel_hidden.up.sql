@@ -0,0 +1,15 @@
+ALTER TABLE channel ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE buffered_record (
+  id SERIAL PRIMARY KEY,
+  channel_id INT NOT NULL,
+  topic VARCHAR(255) NOT NULL,
+  partition INT NOT NULL,
+  record_offset BIGINT NOT NULL,
+  content_type VARCHAR(64) NOT NULL DEFAULT '',
+  value BYTEA NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_channel FOREIGN KEY(channel_id) REFERENCES channel(id) ON DELETE CASCADE
+);
+
+CREATE INDEX buffered_record_channel_id_idx ON buffered_record(channel_id, id);
@@ -0,0 +1,7 @@
+DROP TABLE moderation_action;
+
+DROP TABLE report;
+
+ALTER TABLE reply DROP COLUMN hidden;
+
+ALTER TABLE message DROP COLUMN hidden;
@@ -0,0 +1,29 @@
+ALTER TABLE message ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE reply ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE report (
+  id SERIAL PRIMARY KEY,
+  web_user_id INT NOT NULL,
+  message_id INT NOT NULL,
+  reason TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  resolved_at TIMESTAMP,
+  CONSTRAINT fk_web_user FOREIGN KEY(web_user_id) REFERENCES web_user(id) ON DELETE CASCADE,
+  CONSTRAINT fk_message FOREIGN KEY(message_id) REFERENCES message(id) ON DELETE CASCADE
+);
+
+-- A user can have only one open report of the message.
+CREATE UNIQUE INDEX report_open_idx ON report(web_user_id, message_id) WHERE resolved_at IS NULL;
+
+CREATE TABLE moderation_action (
+  id SERIAL PRIMARY KEY,
+  moderator_id INT,
+  target_type VARCHAR(16) NOT NULL CHECK (target_type IN ('message', 'reply')),
+  target_id INT NOT NULL,
+  action VARCHAR(16) NOT NULL CHECK (action IN ('hide', 'unhide', 'dismiss')),
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_moderator FOREIGN KEY(moderator_id) REFERENCES web_user(id) ON DELETE SET NULL
+);
+
+CREATE INDEX moderation_action_created_at_idx ON moderation_action(created_at);
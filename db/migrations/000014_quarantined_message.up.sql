@@ -0,0 +1,16 @@
+CREATE TABLE quarantined_message (
+  id SERIAL PRIMARY KEY,
+  channel_id INT NOT NULL,
+  title TEXT NOT NULL,
+  rule VARCHAR(64) NOT NULL,
+  reason TEXT NOT NULL,
+  topic VARCHAR(255) NOT NULL,
+  partition INT NOT NULL,
+  record_offset BIGINT NOT NULL,
+  content_type VARCHAR(64) NOT NULL DEFAULT '',
+  value BYTEA NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+  CONSTRAINT fk_channel FOREIGN KEY(channel_id) REFERENCES channel(id) ON DELETE CASCADE
+);
+
+CREATE INDEX quarantined_message_created_at_idx ON quarantined_message(created_at);
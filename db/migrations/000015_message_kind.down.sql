@@ -0,0 +1,3 @@
+DROP INDEX message_kind_idx;
+
+ALTER TABLE message DROP COLUMN kind;
@@ -0,0 +1,4 @@
+ALTER TABLE message ADD COLUMN kind VARCHAR(16) NOT NULL DEFAULT 'other'
+  CHECK (kind IN ('question', 'announcement', 'other'));
+
+CREATE INDEX message_kind_idx ON message(kind, id);
@@ -0,0 +1 @@
+ALTER TABLE web_user DROP COLUMN totp_last_counter;
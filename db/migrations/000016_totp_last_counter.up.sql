@@ -0,0 +1 @@
+ALTER TABLE web_user ADD COLUMN totp_last_counter BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,466 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+)
+
+// RecordReprocessor processes again the queue records which failed ingestion, were buffered or quarantined.
+type RecordReprocessor interface {
+	Reprocess(ctx context.Context, ingestionErrorID int) error
+	StartReplay(ctx context.Context, channelID int) error
+	ReleaseQuarantined(ctx context.Context, quarantinedMessageID int) error
+}
+
+// adminUsersPerPage is a size of the page of users and quarantined messages, it's the same as the limit of the queries.
+const adminUsersPerPage = 10
+
+type adminPageData struct {
+	DefaultPageData PageData
+	Channels        []model.Channel
+	IngestionErrors []model.IngestionError
+	Consumers       []model.ConsumerStatus
+	Message         string
+}
+
+type adminQuarantinePageData struct {
+	DefaultPageData PageData
+	Messages        []model.QuarantinedMessage
+	Page            int
+	// PrevPage and NextPage are zero on the first and the last page.
+	PrevPage int
+	NextPage int
+	Message  string
+}
+
+type adminUsersPageData struct {
+	DefaultPageData PageData
+	WebUsers        []model.WebUser
+	Users           []model.User
+	Roles           []model.Role
+	Page            int
+	// PrevPage and NextPage are zero on the first and the last page.
+	PrevPage int
+	NextPage int
+	Message  string
+}
+
+// requirePermission rejects requests of the users whose role doesn't have the permission,
+// anonymous users are sent to the login page.
+func (h Handler) requirePermission(permission model.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := webUserFromContext(r.Context())
+		if user == nil {
+			http.Redirect(w, r, "/auth/login", http.StatusFound)
+			return
+		}
+
+		err := h.service.Access.Authorize(r.Context(), user, permission)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (h Handler) loadAdminPage(w http.ResponseWriter, r *http.Request) {
+	h.executeAdminTemplate(w, r, http.StatusOK, h.newAdminPageData(r))
+}
+
+func (h Handler) disableChannel(w http.ResponseWriter, r *http.Request) {
+	h.updateChannel(w, r, func(ctx context.Context, actor *model.WebUser, channelID int) error {
+		return h.service.Admin.SetChannelEnabled(ctx, actor, channelID, false)
+	})
+}
+
+// enableChannel enables the channel and starts replay of messages which were buffered while it was disabled.
+func (h Handler) enableChannel(w http.ResponseWriter, r *http.Request) {
+	h.updateChannel(w, r, func(ctx context.Context, actor *model.WebUser, channelID int) error {
+		err := h.service.Admin.SetChannelEnabled(ctx, actor, channelID, true)
+		if err != nil {
+			return err
+		}
+
+		// Replay runs in the background, so a large buffer doesn't hold the request. Records which weren't
+		// replayed stay buffered until the channel is enabled again.
+		err = h.reprocessor.StartReplay(ctx, channelID)
+		if err != nil {
+			h.log.ForContext(ctx).Error().Err(err).Int("channel id", channelID).Msg("start replay of buffered records")
+		}
+
+		return nil
+	})
+}
+
+func (h Handler) hideChannel(w http.ResponseWriter, r *http.Request) {
+	h.updateChannel(w, r, func(ctx context.Context, actor *model.WebUser, channelID int) error {
+		return h.service.Admin.SetChannelHidden(ctx, actor, channelID, true)
+	})
+}
+
+func (h Handler) unhideChannel(w http.ResponseWriter, r *http.Request) {
+	h.updateChannel(w, r, func(ctx context.Context, actor *model.WebUser, channelID int) error {
+		return h.service.Admin.SetChannelHidden(ctx, actor, channelID, false)
+	})
+}
+
+// updateChannel applies the admin action to the channel from the path and shows the reason when it fails.
+func (h Handler) updateChannel(
+	w http.ResponseWriter, r *http.Request, action func(ctx context.Context, actor *model.WebUser, channelID int) error,
+) {
+	log := h.log.ForContext(r.Context())
+
+	channelID, err := strconv.Atoi(mux.Vars(r)["channel_id"])
+	if err != nil {
+		log.Error().Err(err).Msg("convert channel id to int")
+
+		http.Redirect(w, r, "/admin", http.StatusFound)
+		return
+	}
+
+	err = action(r.Context(), webUserFromContext(r.Context()), channelID)
+	if err == nil {
+		http.Redirect(w, r, "/admin", http.StatusFound)
+		return
+	}
+
+	data := h.newAdminPageData(r)
+
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, service.ErrChannelNotFound):
+		data.Message = "Channel not found!"
+		status = http.StatusNotFound
+	default:
+		log.Error().Err(err).Msg("update channel")
+
+		data.Message = "Failed to update channel!"
+	}
+
+	h.executeAdminTemplate(w, r, status, data)
+}
+
+func (h Handler) reprocessIngestionError(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	ingestionErrorID, err := strconv.Atoi(mux.Vars(r)["error_id"])
+	if err != nil {
+		log.Error().Err(err).Msg("convert ingestion error id to int")
+
+		http.Redirect(w, r, "/admin", http.StatusFound)
+		return
+	}
+
+	err = h.reprocessor.Reprocess(r.Context(), ingestionErrorID)
+	if err != nil {
+		log.Error().Err(err).Int("id", ingestionErrorID).Msg("reprocess record")
+
+		data := h.newAdminPageData(r)
+		data.Message = "Failed to reprocess record: " + err.Error()
+
+		h.executeAdminTemplate(w, r, http.StatusUnprocessableEntity, data)
+		return
+	}
+
+	http.Redirect(w, r, "/admin", http.StatusFound)
+}
+
+func (h Handler) dismissIngestionError(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	ingestionErrorID, err := strconv.Atoi(mux.Vars(r)["error_id"])
+	if err != nil {
+		log.Error().Err(err).Msg("convert ingestion error id to int")
+
+		http.Redirect(w, r, "/admin", http.StatusFound)
+		return
+	}
+
+	err = h.service.Admin.DismissIngestionError(r.Context(), webUserFromContext(r.Context()), ingestionErrorID)
+	if err != nil && !errors.Is(err, service.ErrIngestionErrorNotFound) {
+		log.Error().Err(err).Msg("dismiss ingestion error")
+	}
+
+	http.Redirect(w, r, "/admin", http.StatusFound)
+}
+
+// newAdminPageData loads the dashboard after the change, so the page shows the result of the action.
+func (h Handler) newAdminPageData(r *http.Request) adminPageData {
+	log := h.log.ForContext(r.Context())
+
+	data := adminPageData{
+		DefaultPageData: h.newAdminDefaultPageData(r, "admin", "Admin dashboard"),
+	}
+
+	dashboard, err := h.service.Admin.GetDashboard(r.Context(), webUserFromContext(r.Context()))
+	if err != nil {
+		log.Error().Err(err).Msg("get admin dashboard")
+
+		data.Message = "Failed to load dashboard!"
+	}
+	if dashboard != nil {
+		data.Channels = dashboard.Channels
+		data.IngestionErrors = dashboard.IngestionErrors
+	}
+
+	if h.consumers != nil {
+		data.Consumers = h.consumers.Status()
+	}
+
+	return data
+}
+
+func (h Handler) executeAdminTemplate(w http.ResponseWriter, r *http.Request, status int, data adminPageData) {
+	w.WriteHeader(status)
+
+	err := h.templates.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("load admin page")
+	}
+}
+
+func (h Handler) loadAdminUsersPage(w http.ResponseWriter, r *http.Request) {
+	h.executeAdminUsersTemplate(w, r, http.StatusOK, h.newAdminUsersPageData(r, "adminusers", "Web users"))
+}
+
+func (h Handler) loadAdminTgUsersPage(w http.ResponseWriter, r *http.Request) {
+	h.executeAdminUsersTemplate(w, r, http.StatusOK, h.newAdminUsersPageData(r, "admintgusers", "Telegram users"))
+}
+
+func (h Handler) banWebUser(w http.ResponseWriter, r *http.Request) {
+	h.updateWebUser(w, r, h.service.Admin.BanWebUser)
+}
+
+func (h Handler) unbanWebUser(w http.ResponseWriter, r *http.Request) {
+	h.updateWebUser(w, r, h.service.Admin.UnbanWebUser)
+}
+
+func (h Handler) changeWebUserRole(w http.ResponseWriter, r *http.Request) {
+	h.updateWebUser(w, r, func(ctx context.Context, actor *model.WebUser, userID int) error {
+		return h.service.Access.SetWebUserRole(ctx, actor, userID, model.Role(r.PostFormValue("role")))
+	})
+}
+
+// updateWebUser applies the admin action to the user from the path and shows the reason when it fails.
+func (h Handler) updateWebUser(
+	w http.ResponseWriter, r *http.Request, action func(ctx context.Context, actor *model.WebUser, userID int) error,
+) {
+	log := h.log.ForContext(r.Context())
+
+	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		log.Error().Err(err).Msg("convert user id to int")
+
+		http.Redirect(w, r, "/admin/users", http.StatusFound)
+		return
+	}
+
+	err = action(r.Context(), webUserFromContext(r.Context()), userID)
+	if err == nil {
+		http.Redirect(w, r, "/admin/users", http.StatusFound)
+		return
+	}
+
+	data := h.newAdminUsersPageData(r, "adminusers", "Web users")
+
+	status := http.StatusBadRequest
+
+	switch {
+	case errors.Is(err, service.ErrWebUserNotFound):
+		data.Message = "User not found!"
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrOwnBan):
+		data.Message = "You can't ban yourself!"
+	case errors.Is(err, service.ErrOwnRoleChange):
+		data.Message = "You can't change your own role!"
+	case errors.Is(err, service.ErrInvalidRole):
+		data.Message = "Role is invalid!"
+	default:
+		log.Error().Err(err).Msg("update web user")
+
+		data.Message = "Failed to update user!"
+		status = http.StatusInternalServerError
+	}
+
+	h.executeAdminUsersTemplate(w, r, status, data)
+}
+
+func (h Handler) newAdminUsersPageData(r *http.Request, pageType string, title string) adminUsersPageData {
+	log := h.log.ForContext(r.Context())
+
+	data := adminUsersPageData{
+		DefaultPageData: h.newAdminDefaultPageData(r, pageType, title),
+		Roles:           []model.Role{model.RoleMember, model.RoleModerator, model.RoleAdmin},
+		Page:            1,
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err == nil && page > 1 {
+		data.Page = page
+		data.PrevPage = page - 1
+	}
+
+	user := webUserFromContext(r.Context())
+
+	var count int
+
+	if pageType == "admintgusers" {
+		data.Users, err = h.service.Admin.GetUsersByPage(r.Context(), user, data.Page)
+		count = len(data.Users)
+	} else {
+		data.WebUsers, err = h.service.Admin.GetWebUsersByPage(r.Context(), user, data.Page)
+		count = len(data.WebUsers)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("get users by page")
+
+		data.Message = "Failed to load users!"
+	}
+
+	if count == adminUsersPerPage {
+		data.NextPage = data.Page + 1
+	}
+
+	return data
+}
+
+func (h Handler) executeAdminUsersTemplate(
+	w http.ResponseWriter, r *http.Request, status int, data adminUsersPageData,
+) {
+	w.WriteHeader(status)
+
+	err := h.templates.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("load admin users page")
+	}
+}
+
+func (h Handler) newAdminDefaultPageData(r *http.Request, pageType string, title string) PageData {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+
+	data := PageData{
+		Type:            pageType,
+		Title:           title,
+		WebUserEmail:    user.Email,
+		WebUserID:       user.ID,
+		WebUserVerified: user.EmailVerified,
+		WebUserRole:     user.Role,
+		CSRFToken:       csrfTokenFromContext(r.Context()),
+	}
+
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("get channels for navbar")
+	}
+	if navBarChannels != nil {
+		data.Channels = GetRightChannelsCountForNavBar(navBarChannels)
+		data.ChannelsLength = len(navBarChannels)
+	}
+
+	return data
+}
+
+func (h Handler) loadAdminQuarantinePage(w http.ResponseWriter, r *http.Request) {
+	h.executeAdminQuarantineTemplate(w, r, http.StatusOK, h.newAdminQuarantinePageData(r))
+}
+
+// releaseQuarantinedMessage saves the message caught by the filter.
+func (h Handler) releaseQuarantinedMessage(w http.ResponseWriter, r *http.Request) {
+	h.updateQuarantinedMessage(w, r, func(ctx context.Context, _ *model.WebUser, id int) error {
+		return h.reprocessor.ReleaseQuarantined(ctx, id)
+	})
+}
+
+func (h Handler) dismissQuarantinedMessage(w http.ResponseWriter, r *http.Request) {
+	h.updateQuarantinedMessage(w, r, h.service.Admin.DismissQuarantinedMessage)
+}
+
+// updateQuarantinedMessage applies the admin action to the quarantined message from the path
+// and shows the reason when it fails.
+func (h Handler) updateQuarantinedMessage(
+	w http.ResponseWriter, r *http.Request, action func(ctx context.Context, actor *model.WebUser, id int) error,
+) {
+	log := h.log.ForContext(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)["message_id"])
+	if err != nil {
+		log.Error().Err(err).Msg("convert quarantined message id to int")
+
+		http.Redirect(w, r, "/admin/quarantine", http.StatusFound)
+		return
+	}
+
+	err = action(r.Context(), webUserFromContext(r.Context()), id)
+	if err == nil {
+		http.Redirect(w, r, "/admin/quarantine", http.StatusFound)
+		return
+	}
+
+	data := h.newAdminQuarantinePageData(r)
+
+	status := http.StatusUnprocessableEntity
+
+	if errors.Is(err, service.ErrQuarantinedMessageNotFound) {
+		data.Message = "Message not found!"
+		status = http.StatusNotFound
+	} else {
+		log.Error().Err(err).Int("id", id).Msg("update quarantined message")
+
+		data.Message = "Failed to update message: " + err.Error()
+	}
+
+	h.executeAdminQuarantineTemplate(w, r, status, data)
+}
+
+func (h Handler) newAdminQuarantinePageData(r *http.Request) adminQuarantinePageData {
+	log := h.log.ForContext(r.Context())
+
+	data := adminQuarantinePageData{
+		DefaultPageData: h.newAdminDefaultPageData(r, "adminquarantine", "Quarantine"),
+		Page:            1,
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err == nil && page > 1 {
+		data.Page = page
+		data.PrevPage = page - 1
+	}
+
+	data.Messages, err = h.service.Admin.GetQuarantinedMessagesByPage(
+		r.Context(), webUserFromContext(r.Context()), data.Page,
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("get quarantined messages by page")
+
+		data.Message = "Failed to load messages!"
+	}
+
+	if len(data.Messages) == adminUsersPerPage {
+		data.NextPage = data.Page + 1
+	}
+
+	return data
+}
+
+func (h Handler) executeAdminQuarantineTemplate(
+	w http.ResponseWriter, r *http.Request, status int, data adminQuarantinePageData,
+) {
+	w.WriteHeader(status)
+
+	err := h.templates.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("load quarantine page")
+	}
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+)
+
+type apiTokenKey struct{}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type apiChannelResponse struct {
+	Channel       model.Channel       `json:"channel"`
+	Messages      []model.FullMessage `json:"messages"`
+	MessagesCount int                 `json:"messagesCount"`
+}
+
+type apiSavedResponse struct {
+	Messages      []model.FullMessage `json:"messages"`
+	MessagesCount int                 `json:"messagesCount"`
+}
+
+// apiAuth authenticates requests to the JSON API by the personal access token from the Authorization header.
+// Session cookie is never accepted here, so the API can't be called cross-site from the browser.
+func (h Handler) apiAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log := h.log.ForContext(r.Context())
+
+		if !isTokenAuthenticated(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			h.writeJSON(w, r, http.StatusUnauthorized, apiError{Error: "bearer token is required"})
+			return
+		}
+
+		apiToken, err := h.service.APIToken.AuthenticateAPIToken(
+			r.Context(), strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+		)
+		if err != nil {
+			if errors.Is(err, service.ErrAPITokenInvalid) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				h.writeJSON(w, r, http.StatusUnauthorized, apiError{Error: "token is invalid or expired"})
+				return
+			}
+
+			log.Error().Err(err).Msg("authenticate api token")
+
+			h.writeJSON(w, r, http.StatusInternalServerError, apiError{Error: "failed to authenticate"})
+			return
+		}
+
+		user, err := h.service.WebUser.GetWebUserByID(r.Context(), apiToken.UserID)
+		if err != nil {
+			log.Error().Err(err).Msg("get web user by id")
+
+			h.writeJSON(w, r, http.StatusInternalServerError, apiError{Error: "failed to authenticate"})
+			return
+		}
+
+		if user.Banned() {
+			h.writeJSON(w, r, http.StatusForbidden, apiError{Error: "user is banned"})
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), apiTokenKey{}, apiToken)
+		ctx = context.WithValue(ctx, webUserKey{}, user)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// apiTokenFromContext returns token which authenticated the API request.
+func apiTokenFromContext(ctx context.Context) *model.APIToken {
+	apiToken, _ := ctx.Value(apiTokenKey{}).(*model.APIToken)
+
+	return apiToken
+}
+
+// requireScope rejects requests made with the token without the scope.
+func (h Handler) requireScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		apiToken := apiTokenFromContext(r.Context())
+		if apiToken == nil || !apiToken.HasScope(scope) {
+			h.writeJSON(w, r, http.StatusForbidden, apiError{Error: fmt.Sprintf("token doesn't have %q scope", scope)})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (h Handler) apiGetChannels(w http.ResponseWriter, r *http.Request) {
+	channels, err := h.service.Channel.GetChannels(r.Context())
+	if err != nil && !errors.Is(err, service.ErrChannelsNotFound) {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("get channels")
+
+		h.writeJSON(w, r, http.StatusInternalServerError, apiError{Error: "failed to get channels"})
+		return
+	}
+
+	if channels == nil {
+		channels = []model.Channel{}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, channels)
+}
+
+func (h Handler) apiGetChannel(w http.ResponseWriter, r *http.Request) {
+	page := 1
+	if value := r.URL.Query().Get("page"); value != "" {
+		var err error
+
+		page, err = strconv.Atoi(value)
+		if err != nil || page < 1 {
+			h.writeJSON(w, r, http.StatusBadRequest, apiError{Error: "page must be a positive number"})
+			return
+		}
+	}
+
+	output, err := h.service.Channel.ProcessChannelPage(r.Context(), mux.Vars(r)["channel_name"], page)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("get data for channel")
+
+		h.writeJSON(w, r, http.StatusInternalServerError, apiError{Error: "failed to get channel"})
+		return
+	}
+
+	if output.Channel.ID == 0 {
+		h.writeJSON(w, r, http.StatusNotFound, apiError{Error: "channel not found"})
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, apiChannelResponse{
+		Channel:       output.Channel,
+		Messages:      output.Messages,
+		MessagesCount: output.MessagesCount,
+	})
+}
+
+func (h Handler) apiGetMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
+	if err != nil {
+		h.writeJSON(w, r, http.StatusBadRequest, apiError{Error: "message id must be a number"})
+		return
+	}
+
+	output, err := h.service.Message.ProcessMessagePage(r.Context(), messageID)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("get data for message")
+
+		h.writeJSON(w, r, http.StatusInternalServerError, apiError{Error: "failed to get message"})
+		return
+	}
+
+	if output.Message == nil {
+		h.writeJSON(w, r, http.StatusNotFound, apiError{Error: "message not found"})
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, output.Message)
+}
+
+func (h Handler) apiGetSavedMessages(w http.ResponseWriter, r *http.Request) {
+	user := webUserFromContext(r.Context())
+
+	output, err := h.service.Saved.ProcessSavedMessages(r.Context(), user.ID)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("get saved messages")
+
+		h.writeJSON(w, r, http.StatusInternalServerError, apiError{Error: "failed to get saved messages"})
+		return
+	}
+
+	response := apiSavedResponse{Messages: output.SavedMessages, MessagesCount: output.SavedMessagesCount}
+	if response.Messages == nil {
+		response.Messages = []model.FullMessage{}
+	}
+
+	h.writeJSON(w, r, http.StatusOK, response)
+}
+
+func (h Handler) apiCreateSavedMessage(w http.ResponseWriter, r *http.Request) {
+	user := webUserFromContext(r.Context())
+
+	if !user.EmailVerified {
+		h.writeJSON(w, r, http.StatusForbidden, apiError{Error: "email must be verified to save messages"})
+		return
+	}
+
+	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
+	if err != nil {
+		h.writeJSON(w, r, http.StatusBadRequest, apiError{Error: "message id must be a number"})
+		return
+	}
+
+	_, err = h.service.Message.GetFullMessageByMessageID(r.Context(), messageID)
+	if err != nil {
+		if errors.Is(err, service.ErrMessageNotFound) {
+			h.writeJSON(w, r, http.StatusNotFound, apiError{Error: "message not found"})
+			return
+		}
+
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("get full message by message id")
+
+		h.writeJSON(w, r, http.StatusInternalServerError, apiError{Error: "failed to save message"})
+		return
+	}
+
+	err = h.service.Saved.CreateSavedMessage(r.Context(), &model.Saved{WebUserID: user.ID, MessageID: messageID})
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("create saved message")
+
+		h.writeJSON(w, r, http.StatusInternalServerError, apiError{Error: "failed to save message"})
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, map[string]int{"messageId": messageID})
+}
@@ -10,95 +10,153 @@ import (
 )
 
 type authPageData struct {
-	Title   string
-	Message string
+	Title      string
+	Message    string
+	Notice     string
+	Token      string
+	ShowResend bool
+	CSRFToken  string
 }
 
 func (h Handler) loadRegistrationPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := authPageData{
-		Title: "Registration",
+		Title:     "Registration",
+		CSRFToken: csrfTokenFromContext(r.Context()),
 	}
 
 	h.tmpTree["register"] = template.Must(template.ParseFiles("templates/auth/register.html"))
 	err := h.tmpTree["register"].Execute(w, data)
 	if err != nil {
-		h.log.Error().Err(err).Msg("load registration page")
+		log.Error().Err(err).Msg("load registration page")
 	}
 }
 
 func (h Handler) loadLoginPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := authPageData{
-		Title: "login",
+		Title:     "login",
+		CSRFToken: csrfTokenFromContext(r.Context()),
 	}
 
 	h.tmpTree["login"] = template.Must(template.ParseFiles("templates/auth/login.html"))
 	err := h.tmpTree["login"].Execute(w, data)
 	if err != nil {
-		h.log.Error().Err(err).Msg("load login page")
+		log.Error().Err(err).Msg("load login page")
 	}
 }
 
 func (h Handler) registration(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := authPageData{
-		Title: "Registration",
+		Title:     "Registration",
+		CSRFToken: csrfTokenFromContext(r.Context()),
 	}
 
 	user := h.getUserFromForm(r)
 
-	err := h.service.Auth.Register(user)
+	err := h.service.Auth.Register(r.Context(), user)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrWebUserIsExist):
 			data.Message = fmt.Sprintf("User with email %s is exist!", user.Email)
+		case errors.Is(err, service.ErrInvalidEmail):
+			data.Message = fmt.Sprintf("Email %s is invalid!", user.Email)
+		case errors.Is(err, service.ErrPasswordTooShort):
+			data.Message = "Password is too short!"
 		default:
 			data.Message = "Failed to register new user!"
 		}
-		err = h.tmpTree["register"].Execute(w, data)
-		if err != nil {
-			h.log.Error().Err(err).Msg("execute register template")
-		}
+
+		h.executeAuthTemplate(w, r, "templates/auth/register.html", data)
+
+		return
+	}
+
+	err = h.service.Verification.SendVerification(r.Context(), user.Email)
+	if err != nil {
+		log.Error().Err(err).Msg("send verification email")
 	}
 
 	http.Redirect(w, r, "/auth/login", http.StatusFound)
 }
 
 func (h Handler) login(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := authPageData{
-		Title: "Login",
+		Title:     "Login",
+		CSRFToken: csrfTokenFromContext(r.Context()),
 	}
 
 	user := h.getUserFromForm(r)
 
-	email, err := h.service.Auth.Login(user.Email, user.Password)
+	email, err := h.service.Auth.Login(r.Context(), user.Email, user.Password, clientIP(r))
 	if err != nil {
 		switch {
-		case errors.Is(err, service.ErrWebUserNotFound):
-			data.Message = fmt.Sprintf("User with email %s not found!", user.Email)
-		case errors.Is(err, service.ErrIncorrectPassword):
-			data.Message = "User password is incorrect!"
+		case errors.Is(err, service.ErrWebUserNotFound), errors.Is(err, service.ErrIncorrectPassword):
+			data.Message = "Email or password is incorrect!"
+		case errors.Is(err, service.ErrTooManyLoginAttempts):
+			data.Message = "Too many failed login attempts, try again later!"
+
+			w.WriteHeader(http.StatusTooManyRequests)
+		case errors.Is(err, service.ErrWebUserBanned):
+			data.Message = "Your account is banned!"
+
+			w.WriteHeader(http.StatusForbidden)
 		default:
 			data.Message = "Failed to login!"
 
-			h.log.Error().Err(err).Msg("login user")
+			log.Error().Err(err).Msg("login user")
 		}
 	}
 
 	if email != "" {
-		h.addUserToSession(w, r, email)
+		redirectURL, err := h.loginUser(w, r, email)
+		if err == nil {
+			http.Redirect(w, r, redirectURL, http.StatusFound)
 
-		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
+			return
+		}
 
-		return
+		data.Message = "Failed to login!"
+
+		log.Error().Err(err).Msg("start user session")
 	}
 
-	err = h.tmpTree["login"].Execute(w, data)
+	h.executeAuthTemplate(w, r, "templates/auth/login.html", data)
+}
+
+// loginUser starts session for the user with the checked password and returns the page to redirect to.
+// Users with two-factor authentication are sent to enter the code first.
+func (h Handler) loginUser(w http.ResponseWriter, r *http.Request, email string) (string, error) {
+	user, err := h.service.WebUser.GetWebUserByEmail(r.Context(), email)
 	if err != nil {
-		h.log.Error().Err(err).Msg("execute login template")
+		return "", fmt.Errorf("get web user by email: %w", err)
+	}
+
+	if user.TOTPEnabled {
+		h.setLoginChallengeCookie(w, h.service.TwoFactor.CreateLoginChallenge(r.Context(), user.ID))
+
+		return "/auth/login/2fa", nil
 	}
+
+	err = h.startSession(w, r, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return "/home", nil
 }
 
 func (h Handler) logout(w http.ResponseWriter, r *http.Request) {
-	h.removeUserFromSession(w, r)
+	err := h.endSession(w, r)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("end user session")
+	}
 
 	http.Redirect(w, r, "/home", http.StatusMovedPermanently)
 }
@@ -27,41 +27,43 @@ type channelsPageData struct {
 }
 
 func (h Handler) loadChannelsPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := channelsPageData{
 		DefaultPageData: PageData{
 			Title:        "Telegram channels",
 			Type:         "channels",
 			WebUserEmail: "",
 			WebUserID:    0,
+			CSRFToken:    csrfTokenFromContext(r.Context()),
 		},
 	}
 
 	page, err := strconv.Atoi(r.URL.Query().Get("page"))
 	if err != nil {
-		h.log.Error().Err(err).Msg("convert page value for channels to int")
+		log.Error().Err(err).Msg("convert page value for channels to int")
 	}
 
-	navBarChannels, err := h.service.Channel.GetChannels()
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
 	if err != nil {
-		h.log.Error().Err(err).Msg("get channels for navbar")
+		log.Error().Err(err).Msg("get channels for navbar")
 	}
 	if navBarChannels != nil {
 		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
 		data.DefaultPageData.ChannelsLength = len(navBarChannels)
 	}
 
-	user, err := h.service.WebUser.GetWebUserByEmail(h.getUserFromSession(r))
-	if err != nil {
-		h.log.Error().Err(err).Msg("get web user by email")
-	}
+	user := webUserFromContext(r.Context())
 	if user != nil {
 		data.DefaultPageData.WebUserEmail = user.Email
 		data.DefaultPageData.WebUserID = user.ID
+		data.DefaultPageData.WebUserVerified = user.EmailVerified
+		data.DefaultPageData.WebUserRole = user.Role
 	}
 
-	pageData, err := h.service.Channel.ProcessChannelsPage(page)
+	pageData, err := h.service.Channel.ProcessChannelsPage(r.Context(), page)
 	if err != nil {
-		h.log.Error().Err(err).Msg("get data for channels page")
+		log.Error().Err(err).Msg("get data for channels page")
 	}
 	if pageData != nil {
 		data.Channels = pageData.Channels
@@ -70,17 +72,20 @@ func (h Handler) loadChannelsPage(w http.ResponseWriter, r *http.Request) {
 
 	err = h.templates.ExecuteTemplate(w, "base", data)
 	if err != nil {
-		h.log.Error().Err(err).Msg("load channels page")
+		log.Error().Err(err).Msg("load channels page")
 	}
 }
 
 func (h Handler) loadChannelPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := channelPageData{
 		DefaultPageData: PageData{
 			Type:         "channel",
 			Title:        "Telegram channel",
 			WebUserEmail: "",
 			WebUserID:    0,
+			CSRFToken:    csrfTokenFromContext(r.Context()),
 		},
 	}
 
@@ -88,30 +93,29 @@ func (h Handler) loadChannelPage(w http.ResponseWriter, r *http.Request) {
 
 	page, err := strconv.Atoi(r.URL.Query().Get("page"))
 	if err != nil {
-		h.log.Error().Err(err).Msg("convert page value for channel to int")
+		log.Error().Err(err).Msg("convert page value for channel to int")
 	}
 
-	navBarChannels, err := h.service.Channel.GetChannels()
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
 	if err != nil {
-		h.log.Error().Err(err).Msg("get channels for navbar")
+		log.Error().Err(err).Msg("get channels for navbar")
 	}
 	if navBarChannels != nil {
 		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
 		data.DefaultPageData.ChannelsLength = len(navBarChannels)
 	}
 
-	user, err := h.service.WebUser.GetWebUserByEmail(h.getUserFromSession(r))
-	if err != nil {
-		h.log.Error().Err(err).Msg("get web user by email")
-	}
+	user := webUserFromContext(r.Context())
 	if user != nil {
 		data.DefaultPageData.WebUserEmail = user.Email
 		data.DefaultPageData.WebUserID = user.ID
+		data.DefaultPageData.WebUserVerified = user.EmailVerified
+		data.DefaultPageData.WebUserRole = user.Role
 	}
 
-	pageData, err := h.service.Channel.ProcessChannelPage(channelName, page)
+	pageData, err := h.service.Channel.ProcessChannelPage(r.Context(), channelName, page)
 	if err != nil {
-		h.log.Error().Err(err).Msg("get data for channel page")
+		log.Error().Err(err).Msg("get data for channel page")
 	}
 	if pageData != nil {
 		data.Channel = pageData.Channel
@@ -122,6 +126,6 @@ func (h Handler) loadChannelPage(w http.ResponseWriter, r *http.Request) {
 
 	err = h.templates.ExecuteTemplate(w, "base", data)
 	if err != nil {
-		h.log.Error().Err(err).Msg("load channel page")
+		log.Error().Err(err).Msg("load channel page")
 	}
 }
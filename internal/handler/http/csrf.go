@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+const (
+	csrfCookieName = "csrf"
+	csrfFormField  = "csrf_token"
+	csrfHeader     = "X-CSRF-Token"
+
+	// csrfCookieTTL is a lifetime of the token issued for anonymous users.
+	csrfCookieTTL = 24 * time.Hour
+)
+
+type csrfTokenKey struct{}
+
+// csrf protects state-changing requests from cross-site forgery.
+// Logged in users get a token derived from the session token, so it's rotated together with the session,
+// anonymous users get a random token in the cookie which must be echoed in the form(double submit cookie).
+// Requests authenticated with the Authorization header are exempted, browsers don't attach it cross-site,
+// the session cookie is ignored for such requests.
+func (h Handler) csrf(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isTokenAuthenticated(r) {
+			ctx := context.WithValue(r.Context(), sessionKey{}, (*model.Session)(nil))
+			ctx = context.WithValue(ctx, webUserKey{}, (*model.WebUser)(nil))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		expected := h.csrfToken(w, r)
+
+		if !isSafeMethod(r.Method) && !validCSRFToken(r, expected) {
+			h.log.ForContext(r.Context()).Warn().Str("path", r.URL.Path).Msg("invalid csrf token")
+
+			http.Error(w, "invalid CSRF token", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfTokenKey{}, expected)))
+	})
+}
+
+// csrfToken returns the token expected from the current request, new token is issued for anonymous user without one.
+func (h Handler) csrfToken(w http.ResponseWriter, r *http.Request) string {
+	if sessionFromContext(r.Context()) != nil {
+		if cookie, err := r.Cookie(sessionCookieName); err == nil {
+			return token.Hash("csrf:" + cookie.Value)
+		}
+	}
+
+	if cookie, err := r.Cookie(csrfCookieName); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	csrfToken, err := token.Generate(token.DefaultLength)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("generate csrf token")
+		return ""
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    csrfToken,
+		Path:     "/",
+		MaxAge:   int(csrfCookieTTL.Seconds()),
+		Secure:   h.cookieSecure,
+		HttpOnly: true,
+		SameSite: h.cookieSameSite,
+	})
+
+	return csrfToken
+}
+
+// csrfTokenFromContext returns token which must be put into the forms of the rendered page.
+func csrfTokenFromContext(ctx context.Context) string {
+	csrfToken, _ := ctx.Value(csrfTokenKey{}).(string)
+
+	return csrfToken
+}
+
+func validCSRFToken(r *http.Request, expected string) bool {
+	if expected == "" {
+		return false
+	}
+
+	actual := r.Header.Get(csrfHeader)
+	if actual == "" {
+		actual = r.PostFormValue(csrfFormField)
+	}
+
+	return subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) == 1
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+func isTokenAuthenticated(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
@@ -1,24 +1,33 @@
 package handler
 
 import (
-	"fmt"
 	"html/template"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/gorilla/sessions"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
 	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/metrics"
 )
 
 type Handler struct {
-	store     *sessions.CookieStore
-	service   *service.Manager
-	log       *logger.Logger
-	tmpTree   map[string]*template.Template
-	templates *template.Template
+	service     *service.Manager
+	log         *logger.Logger
+	tmpTree     map[string]*template.Template
+	templates   *template.Template
+	metrics     http.Handler
+	httpMetrics *httpMetrics
+	consumers   ConsumerStatusProvider
+	reprocessor RecordReprocessor
+
+	sessionTTL     time.Duration
+	cookieSecure   bool
+	cookieSameSite http.SameSite
 }
 
 type PageData struct {
@@ -28,20 +37,38 @@ type PageData struct {
 	ChannelsLength int
 	WebUserEmail   interface{}
 	WebUserID      int
+	// WebUserVerified is false until the user confirms email, such users can't save messages.
+	WebUserVerified bool
+	// WebUserRole shows links to the pages restricted to the role.
+	WebUserRole model.Role
+	CSRFToken   string
 }
 
-func NewHandler(serviceManager *service.Manager, cookieStoreSecret string, log *logger.Logger) *Handler {
+func NewHandler(
+	serviceManager *service.Manager, cfg *config.Config, log *logger.Logger, registry *prometheus.Registry,
+	consumers ConsumerStatusProvider, reprocessor RecordReprocessor,
+) *Handler {
 	return &Handler{
-		store:   sessions.NewCookieStore([]byte("secret")),
-		service: serviceManager,
-		log:     log,
-		tmpTree: make(map[string]*template.Template),
+		service:        serviceManager,
+		log:            log,
+		metrics:        metrics.Handler(registry),
+		httpMetrics:    newHTTPMetrics(registry),
+		consumers:      consumers,
+		reprocessor:    reprocessor,
+		sessionTTL:     cfg.SessionTTL,
+		cookieSecure:   cfg.CookieSecure,
+		cookieSameSite: parseSameSite(cfg.CookieSameSite),
+		tmpTree:        make(map[string]*template.Template),
 		templates: template.Must(
 			template.ParseFiles(
 				"templates/message/messages.html", "templates/partials/navbar.html",
 				"templates/partials/header.html", "templates/message/message.html",
 				"templates/channel/channels.html", "templates/channel/channel.html",
 				"templates/user/saved.html", "templates/user/user.html",
+				"templates/user/sessions.html", "templates/user/twofactor.html",
+				"templates/user/tokens.html", "templates/admin/dashboard.html",
+				"templates/admin/users.html", "templates/admin/quarantine.html",
+				"templates/moderation/moderation.html",
 				"templates/base.html",
 			),
 		),
@@ -50,6 +77,11 @@ func NewHandler(serviceManager *service.Manager, cookieStoreSecret string, log *
 
 func (h Handler) InitRouter() *mux.Router {
 	router := mux.NewRouter()
+	router.Use(h.requestID, h.accessLog, h.session, h.csrf)
+
+	router.Handle("/metrics", h.metrics).Methods("GET")
+	router.HandleFunc("/healthz", h.healthz).Methods("GET")
+	router.HandleFunc("/readyz", h.readyz).Methods("GET")
 
 	home := router.PathPrefix("/").Subrouter()
 	home.Handle("/", http.RedirectHandler("/home", http.StatusMovedPermanently)).Methods("GET")
@@ -64,6 +96,7 @@ func (h Handler) InitRouter() *mux.Router {
 
 	message := router.PathPrefix("/message").Subrouter()
 	message.HandleFunc("/{message_id}", h.loadMessagePage).Methods("GET")
+	message.HandleFunc("/{message_id}/report", h.reportMessage).Methods("POST")
 
 	auth := router.PathPrefix("/auth").Subrouter()
 	auth.HandleFunc("/login", h.login).Methods("POST")
@@ -71,12 +104,105 @@ func (h Handler) InitRouter() *mux.Router {
 	auth.HandleFunc("/logout", h.logout).Methods("POST")
 	auth.HandleFunc("/login", h.loadLoginPage).Methods("GET")
 	auth.HandleFunc("/registration", h.loadRegistrationPage).Methods("GET")
+	auth.HandleFunc("/forgot-password", h.loadForgotPasswordPage).Methods("GET")
+	auth.HandleFunc("/forgot-password", h.forgotPassword).Methods("POST")
+	auth.HandleFunc("/reset-password", h.loadResetPasswordPage).Methods("GET")
+	auth.HandleFunc("/reset-password", h.resetPassword).Methods("POST")
+	auth.HandleFunc("/verify-email", h.loadVerifyEmailPage).Methods("GET")
+	auth.HandleFunc("/verify-email/resend", h.resendVerification).Methods("POST")
+	auth.HandleFunc("/login/2fa", h.loadLoginCodePage).Methods("GET")
+	auth.HandleFunc("/login/2fa", h.loginWithCode).Methods("POST")
+	auth.HandleFunc("/2fa", h.loadTwoFactorPage).Methods("GET")
+	auth.HandleFunc("/2fa/enroll", h.startTwoFactorEnrollment).Methods("POST")
+	auth.HandleFunc("/2fa/confirm", h.confirmTwoFactorEnrollment).Methods("POST")
+	auth.HandleFunc("/2fa/disable", h.disableTwoFactor).Methods("POST")
+	auth.HandleFunc("/tokens", h.loadTokensPage).Methods("GET")
+	auth.HandleFunc("/tokens", h.createAPIToken).Methods("POST")
+	auth.HandleFunc("/tokens/{token_id}/revoke", h.revokeAPIToken).Methods("POST")
+	auth.HandleFunc("/sessions", h.loadSessionsPage).Methods("GET")
+	auth.HandleFunc("/sessions/logout-all", h.logoutEverywhere).Methods("POST")
+	auth.HandleFunc("/sessions/{session_id}/delete", h.deleteSession).Methods("POST")
 
 	saved := router.PathPrefix("/saved").Subrouter()
 	saved.HandleFunc("/{user_id}", h.loadSavedMessagesPage).Methods("GET")
 	saved.HandleFunc("/delete/{saved_id}", h.deleteSavedMessage).Methods("POST")
 	saved.HandleFunc("/create/{user_id}/{message_id}", h.createSavedMessage).Methods("POST")
 
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.HandleFunc("", h.requirePermission(model.PermissionViewAdmin, h.loadAdminPage)).Methods("GET")
+	admin.HandleFunc("/users", h.requirePermission(model.PermissionManageUsers, h.loadAdminUsersPage)).Methods("GET")
+	admin.HandleFunc(
+		"/tg-users", h.requirePermission(model.PermissionViewAdmin, h.loadAdminTgUsersPage),
+	).Methods("GET")
+	admin.HandleFunc(
+		"/channels/{channel_id}/disable", h.requirePermission(model.PermissionManageChannels, h.disableChannel),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/channels/{channel_id}/enable", h.requirePermission(model.PermissionManageChannels, h.enableChannel),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/channels/{channel_id}/hide", h.requirePermission(model.PermissionManageChannels, h.hideChannel),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/channels/{channel_id}/unhide", h.requirePermission(model.PermissionManageChannels, h.unhideChannel),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/users/{user_id}/ban", h.requirePermission(model.PermissionManageUsers, h.banWebUser),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/users/{user_id}/unban", h.requirePermission(model.PermissionManageUsers, h.unbanWebUser),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/users/{user_id}/role", h.requirePermission(model.PermissionManageUsers, h.changeWebUserRole),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/ingestion-errors/{error_id}/reprocess",
+		h.requirePermission(model.PermissionManageIngestion, h.reprocessIngestionError),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/ingestion-errors/{error_id}/dismiss",
+		h.requirePermission(model.PermissionManageIngestion, h.dismissIngestionError),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/quarantine", h.requirePermission(model.PermissionManageIngestion, h.loadAdminQuarantinePage),
+	).Methods("GET")
+	admin.HandleFunc(
+		"/quarantine/{message_id}/release",
+		h.requirePermission(model.PermissionManageIngestion, h.releaseQuarantinedMessage),
+	).Methods("POST")
+	admin.HandleFunc(
+		"/quarantine/{message_id}/dismiss",
+		h.requirePermission(model.PermissionManageIngestion, h.dismissQuarantinedMessage),
+	).Methods("POST")
+
+	moderation := router.PathPrefix("/moderation").Subrouter()
+	moderation.HandleFunc("", h.requirePermission(model.PermissionModerate, h.loadModerationPage)).Methods("GET")
+	moderation.HandleFunc(
+		"/messages/{message_id}/hide", h.requirePermission(model.PermissionModerate, h.hideMessage),
+	).Methods("POST")
+	moderation.HandleFunc(
+		"/messages/{message_id}/unhide", h.requirePermission(model.PermissionModerate, h.unhideMessage),
+	).Methods("POST")
+	moderation.HandleFunc(
+		"/messages/{message_id}/dismiss", h.requirePermission(model.PermissionModerate, h.dismissReports),
+	).Methods("POST")
+	moderation.HandleFunc(
+		"/replies/{reply_id}/hide", h.requirePermission(model.PermissionModerate, h.hideReply),
+	).Methods("POST")
+	moderation.HandleFunc(
+		"/replies/{reply_id}/unhide", h.requirePermission(model.PermissionModerate, h.unhideReply),
+	).Methods("POST")
+
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(h.apiAuth)
+	api.HandleFunc("/channels", h.requireScope(model.APITokenScopeRead, h.apiGetChannels)).Methods("GET")
+	api.HandleFunc("/channels/{channel_name}", h.requireScope(model.APITokenScopeRead, h.apiGetChannel)).Methods("GET")
+	api.HandleFunc("/messages/{message_id}", h.requireScope(model.APITokenScopeRead, h.apiGetMessage)).Methods("GET")
+	api.HandleFunc("/saved", h.requireScope(model.APITokenScopeRead, h.apiGetSavedMessages)).Methods("GET")
+	api.HandleFunc(
+		"/saved/{message_id}", h.requireScope(model.APITokenScopeWrite, h.apiCreateSavedMessage),
+	).Methods("POST")
+
 	h.logAllRoutes(router)
 
 	return router
@@ -103,52 +229,12 @@ func (h Handler) logAllRoutes(router *mux.Router) {
 	}
 }
 
-func (h Handler) getUserFromSession(r *http.Request) string {
-	session, err := h.store.Get(r, "session")
-	if err != nil {
-		h.log.Error().Err(err).Msg("get user session")
-	}
-
-	email, ok := session.Values["userEmail"]
-	if ok {
-		return fmt.Sprintf("%v", email)
-	}
-
-	return ""
-}
-
-func (h Handler) addUserToSession(w http.ResponseWriter, r *http.Request, value interface{}) {
-	session, err := h.store.Get(r, "session")
-	if err != nil {
-		h.log.Error().Err(err).Msg("get user session")
-	}
-
-	session.Values["userEmail"] = value
-
-	err = session.Save(r, w)
-	if err != nil {
-		h.log.Error().Err(err).Msg("save user session")
-	}
-}
-
-func (h Handler) removeUserFromSession(w http.ResponseWriter, r *http.Request) {
-	session, err := h.store.Get(r, "session")
-	if err != nil {
-		h.log.Error().Err(err).Msg("get user session")
-	}
-
-	delete(session.Values, "userEmail")
-
-	err = session.Save(r, w)
-	if err != nil {
-		h.log.Error().Err(err).Msg("save user session")
-	}
-}
-
 func (h Handler) getUserFromForm(r *http.Request) *model.WebUser {
+	log := h.log.ForContext(r.Context())
+
 	err := r.ParseForm()
 	if err != nil {
-		h.log.Error().Err(err).Msg("parse form")
+		log.Error().Err(err).Msg("parse form")
 	}
 
 	user := &model.WebUser{
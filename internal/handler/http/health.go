@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+// ConsumerStatusProvider reports state of the queue consumers.
+type ConsumerStatusProvider interface {
+	Status() []model.ConsumerStatus
+}
+
+type healthReport struct {
+	Status    string                 `json:"status"`
+	Postgres  postgresHealth         `json:"postgres"`
+	Consumers []model.ConsumerStatus `json:"consumers"`
+	CheckedAt time.Time              `json:"checkedAt"`
+}
+
+type postgresHealth struct {
+	Status           string                  `json:"status"`
+	Error            string                  `json:"error,omitempty"`
+	MigrationVersion *model.MigrationVersion `json:"migration,omitempty"`
+}
+
+// healthz is a liveness probe, it responds with OK while the server is able to serve requests.
+func (h Handler) healthz(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, r, http.StatusOK, h.checkHealth(r))
+}
+
+// readyz is a readiness probe, it fails when the database or any of the consumers is unavailable.
+func (h Handler) readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.checkHealth(r)
+
+	status := http.StatusOK
+	if report.Status != statusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	h.writeJSON(w, r, status, report)
+}
+
+func (h Handler) checkHealth(r *http.Request) *healthReport {
+	report := &healthReport{
+		Status:    statusOK,
+		Postgres:  postgresHealth{Status: statusOK},
+		CheckedAt: time.Now().UTC(),
+	}
+
+	// Probes are public, so errors which may contain addresses and credentials are only logged.
+	version, err := h.service.Health.CheckDatabase(r.Context())
+	if err != nil {
+		h.log.ForContext(r.Context()).Warn().Err(err).Msg("database is unavailable")
+
+		report.Postgres.Status = statusUnavailable
+	}
+
+	report.Postgres.MigrationVersion = version
+	if version != nil && version.Dirty {
+		report.Postgres.Status = statusUnavailable
+		report.Postgres.Error = "migration is dirty"
+	}
+
+	if report.Postgres.Status != statusOK {
+		report.Status = statusUnavailable
+	}
+
+	if h.consumers != nil {
+		report.Consumers = h.consumers.Status()
+	}
+
+	for i, consumer := range report.Consumers {
+		if consumer.State != model.ConsumerStateRunning {
+			report.Status = statusUnavailable
+		}
+
+		report.Consumers[i].LastError = ""
+	}
+
+	return report
+}
+
+func (h Handler) writeJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("write json response")
+	}
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"context"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/VladPetriv/go-pagination-bootstrap"
@@ -17,64 +19,79 @@ type homePageData struct {
 	Messages        []model.FullMessage
 	MessagesLength  int
 	Pager           *pagination.Pagination
+	// Kind of the messages in the feed, all messages are shown when it's empty.
+	Kind model.MessageKind
 }
 
 func (h Handler) loadHomePage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := homePageData{
 		DefaultPageData: PageData{
 			Title:        "Telegram Overflow",
 			Type:         "messages",
 			WebUserEmail: "",
 			WebUserID:    0,
+			CSRFToken:    csrfTokenFromContext(r.Context()),
 		},
 	}
 
 	page, err := strconv.Atoi(r.URL.Query().Get("page"))
 	if err != nil {
-		h.log.Error().Err(err).Msg("convert page value for messages to int")
+		log.Error().Err(err).Msg("convert page value for messages to int")
+	}
+
+	kind := model.MessageKind(r.URL.Query().Get("kind"))
+	if !kind.Valid() {
+		kind = ""
 	}
+	data.Kind = kind
 
-	navBarChannels, err := h.service.Channel.GetChannels()
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
 	if err != nil {
-		h.log.Error().Err(err).Msg("get channels for nav bar")
+		log.Error().Err(err).Msg("get channels for nav bar")
 	}
 	if navBarChannels != nil {
 		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
 		data.DefaultPageData.ChannelsLength = len(navBarChannels)
 	}
 
-	user, err := h.service.WebUser.GetWebUserByEmail(h.getUserFromSession(r))
-	if err != nil {
-		h.log.Error().Err(err).Msg("get web user by email")
-	}
+	user := webUserFromContext(r.Context())
 	if user != nil {
 		data.DefaultPageData.WebUserEmail = user.Email
 		data.DefaultPageData.WebUserID = user.ID
+		data.DefaultPageData.WebUserVerified = user.EmailVerified
+		data.DefaultPageData.WebUserRole = user.Role
 	}
 
-	pageData, err := h.service.Message.ProcessHomePage(page)
+	pageData, err := h.service.Message.ProcessHomePage(r.Context(), page, kind)
 	if err != nil {
-		h.log.Error().Err(err).Msg("get data for home page")
+		log.Error().Err(err).Msg("get data for home page")
 	}
 	if pageData != nil {
-		pageData.Messages = updateMessagesStatuses(pageData.Messages, h.service)
+		pageData.Messages = updateMessagesStatuses(r.Context(), pageData.Messages, h.service)
 
 		data.Messages = pageData.Messages
 		data.MessagesLength = pageData.MessagesCount
-		data.Pager = pagination.New(pageData.MessagesCount, messagesPerPage, page, "/home/?page=0")
+		baseURL := "/home/?page=0"
+		if kind != "" {
+			baseURL += "&kind=" + url.QueryEscape(string(kind))
+		}
+
+		data.Pager = pagination.New(pageData.MessagesCount, messagesPerPage, page, baseURL)
 	}
 
 	err = h.templates.ExecuteTemplate(w, "base", data)
 	if err != nil {
-		h.log.Error().Err(err).Msg("load home page")
+		log.Error().Err(err).Msg("load home page")
 	}
 }
 
-func updateMessagesStatuses(messages []model.FullMessage, manager *service.Manager) []model.FullMessage {
+func updateMessagesStatuses(ctx context.Context, messages []model.FullMessage, manager *service.Manager) []model.FullMessage {
 	var result []model.FullMessage
 
 	for _, message := range messages {
-		saved, err := manager.Saved.GetSavedMessageByMessageID(message.ID)
+		saved, err := manager.Saved.GetSavedMessageByMessageID(ctx, message.ID)
 		if err == nil && saved != nil {
 			message.Status = true
 
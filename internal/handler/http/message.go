@@ -12,54 +12,79 @@ import (
 type messagePageData struct {
 	DefaultPageData PageData
 	Message         model.FullMessage
+	// CanModerate shows the buttons which hide the message and its replies.
+	CanModerate bool
+	// Notice is shown after the message is reported, Error when the report is rejected.
+	Notice string
+	Error  string
 }
 
 func (h Handler) loadMessagePage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
+	if err != nil {
+		log.Error().Err(err).Msg("convert message_id to int")
+
+		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
+		return
+	}
+
+	data := h.newMessagePageData(r, messageID)
+	if r.URL.Query().Get("reported") != "" {
+		data.Notice = "Thank you, the message is sent to moderators!"
+	}
+
+	h.executeMessageTemplate(w, r, http.StatusOK, data)
+}
+
+func (h Handler) newMessagePageData(r *http.Request, messageID int) messagePageData {
+	log := h.log.ForContext(r.Context())
+
 	data := messagePageData{
 		DefaultPageData: PageData{
 			Type:         "message",
 			Title:        "Telegram message",
 			WebUserEmail: "",
 			WebUserID:    0,
+			CSRFToken:    csrfTokenFromContext(r.Context()),
 		},
 	}
 
-	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
-	if err != nil {
-		h.log.Error().Err(err).Msg("convert message_id to int")
-
-		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
-		return
-	}
-
-	navBarChannels, err := h.service.Channel.GetChannels()
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
 	if err != nil {
-		h.log.Error().Err(err).Msg("get channels for navbar")
+		log.Error().Err(err).Msg("get channels for navbar")
 	}
 	if navBarChannels != nil {
 		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
 		data.DefaultPageData.ChannelsLength = len(navBarChannels)
 	}
 
-	user, err := h.service.WebUser.GetWebUserByEmail(h.getUserFromSession(r))
-	if err != nil {
-		h.log.Error().Err(err).Msg("get web user by email")
-	}
+	user := webUserFromContext(r.Context())
 	if user != nil {
 		data.DefaultPageData.WebUserEmail = user.Email
 		data.DefaultPageData.WebUserID = user.ID
+		data.DefaultPageData.WebUserVerified = user.EmailVerified
+		data.DefaultPageData.WebUserRole = user.Role
+		data.CanModerate = user.Role.Can(model.PermissionModerate)
 	}
 
-	pageData, err := h.service.Message.ProcessMessagePage(messageID)
+	pageData, err := h.service.Message.ProcessMessagePage(r.Context(), messageID)
 	if err != nil {
-		h.log.Error().Err(err).Msg("get data for message page")
+		log.Error().Err(err).Msg("get data for message page")
 	}
-	if pageData != nil {
+	if pageData != nil && pageData.Message != nil {
 		data.Message = *pageData.Message
 	}
 
-	err = h.templates.ExecuteTemplate(w, "base", data)
+	return data
+}
+
+func (h Handler) executeMessageTemplate(w http.ResponseWriter, r *http.Request, status int, data messagePageData) {
+	w.WriteHeader(status)
+
+	err := h.templates.ExecuteTemplate(w, "base", data)
 	if err != nil {
-		h.log.Error().Err(err).Msg("load message page")
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("load message page")
 	}
 }
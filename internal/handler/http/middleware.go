@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/metrics"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// requestIDLength is a length of generated request id in bytes.
+const requestIDLength = 16
+
+var requestIDRegexp = regexp.MustCompile(`^[A-Za-z0-9\-_.]{1,64}$`)
+
+type httpMetrics struct {
+	duration *prometheus.HistogramVec
+}
+
+func newHTTPMetrics(registerer prometheus.Registerer) *httpMetrics {
+	m := &httpMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time spent on processing of HTTP requests by route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+
+	if registerer != nil {
+		registerer.MustRegister(m.duration)
+	}
+
+	return m
+}
+
+// statusRecorder remembers the status code written by the handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// requestID assigns request id to the request context and to the response headers.
+// Valid request id sent by the client is reused so requests can be traced across services.
+func (h Handler) requestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if !requestIDRegexp.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, requestID)
+
+		next.ServeHTTP(w, r.WithContext(logger.ContextWithRequestID(r.Context(), requestID)))
+	})
+}
+
+// accessLog writes access log record and observes request duration per route template.
+func (h Handler) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startedAt := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		latency := time.Since(startedAt)
+		route := routeTemplate(r)
+
+		h.httpMetrics.duration.
+			WithLabelValues(r.Method, route, strconv.Itoa(recorder.status)).
+			Observe(latency.Seconds())
+
+		h.log.ForContext(r.Context()).Info().
+			Str("method", r.Method).
+			Str("route", route).
+			Str("path", r.URL.Path).
+			Int("status", recorder.status).
+			Dur("latency", latency).
+			Msg("handle request")
+	})
+}
+
+func routeTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unknown"
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return "unknown"
+	}
+
+	return template
+}
+
+func newRequestID() string {
+	data := make([]byte, requestIDLength)
+
+	if _, err := rand.Read(data); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+
+	return hex.EncodeToString(data)
+}
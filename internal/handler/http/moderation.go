@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+)
+
+// reportsPerPage is a size of the page of the moderation queue, it's the same as the limit of the query.
+const reportsPerPage = 10
+
+type moderationPageData struct {
+	DefaultPageData PageData
+	Reports         []model.ReportedMessage
+	Actions         []model.ModerationAction
+	Page            int
+	// PrevPage and NextPage are zero on the first and the last page.
+	PrevPage int
+	NextPage int
+	Message  string
+}
+
+// reportMessage sends the message to the moderation queue with the reason given by the user.
+func (h Handler) reportMessage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
+	if err != nil {
+		log.Error().Err(err).Msg("convert message id to int")
+
+		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
+		return
+	}
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	if !user.EmailVerified {
+		http.Redirect(w, r, "/auth/verify-email", http.StatusFound)
+		return
+	}
+
+	err = h.service.Moderation.ReportMessage(r.Context(), user, messageID, r.PostFormValue("reason"))
+	if err == nil {
+		http.Redirect(w, r, fmt.Sprintf("/message/%d?reported=1", messageID), http.StatusFound)
+		return
+	}
+
+	data := h.newMessagePageData(r, messageID)
+
+	status := http.StatusBadRequest
+
+	switch {
+	case errors.Is(err, service.ErrInvalidReportReason):
+		data.Error = "Reason is required and can't be longer than 500 characters!"
+	case errors.Is(err, service.ErrMessageNotFound):
+		data.Error = "Message not found!"
+		status = http.StatusNotFound
+	default:
+		log.Error().Err(err).Msg("report message")
+
+		data.Error = "Failed to report message!"
+		status = http.StatusInternalServerError
+	}
+
+	h.executeMessageTemplate(w, r, status, data)
+}
+
+func (h Handler) loadModerationPage(w http.ResponseWriter, r *http.Request) {
+	h.executeModerationTemplate(w, r, http.StatusOK, h.newModerationPageData(r))
+}
+
+func (h Handler) hideMessage(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "message_id", func(ctx context.Context, actor *model.WebUser, messageID int) error {
+		return h.service.Moderation.SetMessageHidden(ctx, actor, messageID, true)
+	})
+}
+
+func (h Handler) unhideMessage(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "message_id", func(ctx context.Context, actor *model.WebUser, messageID int) error {
+		return h.service.Moderation.SetMessageHidden(ctx, actor, messageID, false)
+	})
+}
+
+func (h Handler) dismissReports(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "message_id", h.service.Moderation.DismissReports)
+}
+
+func (h Handler) hideReply(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "reply_id", func(ctx context.Context, actor *model.WebUser, replyID int) error {
+		return h.service.Moderation.SetReplyHidden(ctx, actor, replyID, true)
+	})
+}
+
+func (h Handler) unhideReply(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "reply_id", func(ctx context.Context, actor *model.WebUser, replyID int) error {
+		return h.service.Moderation.SetReplyHidden(ctx, actor, replyID, false)
+	})
+}
+
+// moderate applies the moderation action to the message or reply from the path and shows the reason when it fails.
+func (h Handler) moderate(
+	w http.ResponseWriter, r *http.Request, idVar string,
+	action func(ctx context.Context, actor *model.WebUser, id int) error,
+) {
+	log := h.log.ForContext(r.Context())
+
+	id, err := strconv.Atoi(mux.Vars(r)[idVar])
+	if err != nil {
+		log.Error().Err(err).Msgf("convert %s to int", idVar)
+
+		http.Redirect(w, r, "/moderation", http.StatusFound)
+		return
+	}
+
+	err = action(r.Context(), webUserFromContext(r.Context()), id)
+	if err == nil {
+		http.Redirect(w, r, "/moderation", http.StatusFound)
+		return
+	}
+
+	data := h.newModerationPageData(r)
+
+	status := http.StatusNotFound
+
+	switch {
+	case errors.Is(err, service.ErrMessageNotFound):
+		data.Message = "Message not found!"
+	case errors.Is(err, service.ErrReplyNotFound):
+		data.Message = "Reply not found!"
+	case errors.Is(err, service.ErrReportsNotFound):
+		data.Message = "Message has no open reports!"
+	default:
+		log.Error().Err(err).Msg("moderate content")
+
+		data.Message = "Failed to moderate content!"
+		status = http.StatusInternalServerError
+	}
+
+	h.executeModerationTemplate(w, r, status, data)
+}
+
+func (h Handler) newModerationPageData(r *http.Request) moderationPageData {
+	log := h.log.ForContext(r.Context())
+
+	data := moderationPageData{
+		DefaultPageData: h.newAdminDefaultPageData(r, "moderation", "Moderation"),
+		Page:            1,
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err == nil && page > 1 {
+		data.Page = page
+		data.PrevPage = page - 1
+	}
+
+	user := webUserFromContext(r.Context())
+
+	data.Reports, err = h.service.Moderation.GetReportsByPage(r.Context(), user, data.Page)
+	if err != nil {
+		log.Error().Err(err).Msg("get reports by page")
+
+		data.Message = "Failed to load reports!"
+	}
+
+	if len(data.Reports) == reportsPerPage {
+		data.NextPage = data.Page + 1
+	}
+
+	data.Actions, err = h.service.Moderation.GetModerationActions(r.Context(), user)
+	if err != nil {
+		log.Error().Err(err).Msg("get moderation actions")
+
+		data.Message = "Failed to load moderation actions!"
+	}
+
+	return data
+}
+
+func (h Handler) executeModerationTemplate(
+	w http.ResponseWriter, r *http.Request, status int, data moderationPageData,
+) {
+	w.WriteHeader(status)
+
+	err := h.templates.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("load moderation page")
+	}
+}
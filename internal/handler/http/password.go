@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/VladPetriv/scanner_backend/internal/service"
+)
+
+func (h Handler) loadForgotPasswordPage(w http.ResponseWriter, r *http.Request) {
+	data := authPageData{
+		Title:     "Forgot password",
+		CSRFToken: csrfTokenFromContext(r.Context()),
+	}
+
+	h.executeAuthTemplate(w, r, "templates/auth/forgot.html", data)
+}
+
+func (h Handler) forgotPassword(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	data := authPageData{
+		Title:     "Forgot password",
+		CSRFToken: csrfTokenFromContext(r.Context()),
+	}
+
+	user := h.getUserFromForm(r)
+
+	err := h.service.Password.RequestPasswordReset(r.Context(), user.Email)
+	if err != nil {
+		log.Error().Err(err).Msg("request password reset")
+
+		data.Message = "Failed to send password reset link!"
+	} else {
+		data.Notice = "If an account with this email exists, we have sent a link to reset the password."
+	}
+
+	h.executeAuthTemplate(w, r, "templates/auth/forgot.html", data)
+}
+
+func (h Handler) loadResetPasswordPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	data := authPageData{
+		Title:     "Reset password",
+		CSRFToken: csrfTokenFromContext(r.Context()),
+	}
+
+	resetToken := r.URL.Query().Get("token")
+
+	err := h.service.Password.CheckPasswordResetToken(r.Context(), resetToken)
+	switch {
+	case err == nil:
+		data.Token = resetToken
+	case errors.Is(err, service.ErrPasswordResetTokenInvalid):
+		data.Message = "Password reset link is invalid or expired!"
+	default:
+		log.Error().Err(err).Msg("check password reset token")
+
+		data.Message = "Failed to check password reset link!"
+	}
+
+	h.executeAuthTemplate(w, r, "templates/auth/reset.html", data)
+}
+
+func (h Handler) resetPassword(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	data := authPageData{
+		Title:     "Reset password",
+		CSRFToken: csrfTokenFromContext(r.Context()),
+	}
+
+	resetToken := r.PostFormValue("token")
+
+	err := h.service.Password.ResetPassword(r.Context(), resetToken, r.PostFormValue("password"))
+	if err == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrPasswordTooShort):
+		data.Token = resetToken
+		data.Message = "Password is too short!"
+	case errors.Is(err, service.ErrPasswordResetTokenInvalid):
+		data.Message = "Password reset link is invalid or expired!"
+	default:
+		log.Error().Err(err).Msg("reset password")
+
+		data.Message = "Failed to reset password!"
+	}
+
+	h.executeAuthTemplate(w, r, "templates/auth/reset.html", data)
+}
+
+// executeAuthTemplate renders standalone page of the auth flow.
+func (h Handler) executeAuthTemplate(w http.ResponseWriter, r *http.Request, filename string, data authPageData) {
+	tmpl, err := template.ParseFiles(filename)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msgf("parse %s template", filename)
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	err = tmpl.Execute(w, data)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msgf("execute %s template", filename)
+	}
+}
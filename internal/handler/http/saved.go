@@ -17,44 +17,46 @@ type savedPageData struct {
 }
 
 func (h Handler) loadSavedMessagesPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := savedPageData{
 		DefaultPageData: PageData{
 			Type:         "saved",
 			Title:        "Saved user messages",
 			WebUserEmail: "",
 			WebUserID:    0,
+			CSRFToken:    csrfTokenFromContext(r.Context()),
 		},
 	}
 
 	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
 	if err != nil {
-		h.log.Error().Err(err).Msg("convert user id to int")
+		log.Error().Err(err).Msg("convert user id to int")
 
 		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
 		return
 	}
 
-	navBarChannels, err := h.service.Channel.GetChannels()
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
 	if err != nil {
-		h.log.Error().Err(err).Msg("get channels for navbar")
+		log.Error().Err(err).Msg("get channels for navbar")
 	}
 	if navBarChannels != nil {
 		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
 		data.DefaultPageData.ChannelsLength = len(navBarChannels)
 	}
 
-	user, err := h.service.WebUser.GetWebUserByEmail(h.getUserFromSession(r))
-	if err != nil {
-		h.log.Error().Err(err).Msg("get web user by email")
-	}
+	user := webUserFromContext(r.Context())
 	if user != nil {
 		data.DefaultPageData.WebUserEmail = user.Email
 		data.DefaultPageData.WebUserID = user.ID
+		data.DefaultPageData.WebUserVerified = user.EmailVerified
+		data.DefaultPageData.WebUserRole = user.Role
 	}
 
-	pageData, err := h.service.Saved.ProcessSavedMessages(userID)
+	pageData, err := h.service.Saved.ProcessSavedMessages(r.Context(), userID)
 	if err != nil {
-		h.log.Error().Err(err).Msg("get data for saved page")
+		log.Error().Err(err).Msg("get data for saved page")
 	}
 	if pageData != nil {
 		data.Messages = pageData.SavedMessages
@@ -63,14 +65,16 @@ func (h Handler) loadSavedMessagesPage(w http.ResponseWriter, r *http.Request) {
 
 	err = h.templates.ExecuteTemplate(w, "base", data)
 	if err != nil {
-		h.log.Error().Err(err).Msg("load saved messages page")
+		log.Error().Err(err).Msg("load saved messages page")
 	}
 }
 
 func (h Handler) createSavedMessage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
 	if err != nil {
-		h.log.Error().Err(err).Msg("convert user id to int")
+		log.Error().Err(err).Msg("convert user id to int")
 
 		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
 		return
@@ -78,23 +82,41 @@ func (h Handler) createSavedMessage(w http.ResponseWriter, r *http.Request) {
 
 	messageID, err := strconv.Atoi(mux.Vars(r)["message_id"])
 	if err != nil {
-		h.log.Error().Err(err).Msg("convert message id to int")
+		log.Error().Err(err).Msg("convert message id to int")
 
 		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
 		return
 	}
 
-	user, err := h.service.WebUser.GetWebUserByEmail(h.getUserFromSession(r))
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	if !user.EmailVerified {
+		http.Redirect(w, r, "/auth/verify-email", http.StatusFound)
+		return
+	}
+
+	if user.ID != userID {
+		log.Info().Int("user id", userID).Msg("saving message for another user is forbidden")
+
+		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
+		return
+	}
+
+	_, err = h.service.Message.GetFullMessageByMessageID(r.Context(), messageID)
 	if err != nil {
-		h.log.Error().Err(err).Msg("get web user by email")
+		log.Error().Err(err).Msg("get full message by message id")
 
 		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
 		return
 	}
 
-	err = h.service.Saved.CreateSavedMessage(&model.Saved{WebUserID: userID, MessageID: messageID})
+	err = h.service.Saved.CreateSavedMessage(r.Context(), &model.Saved{WebUserID: userID, MessageID: messageID})
 	if err != nil {
-		h.log.Error().Err(err).Msg("create saved message")
+		log.Error().Err(err).Msg("create saved message")
 
 		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
 		return
@@ -104,25 +126,25 @@ func (h Handler) createSavedMessage(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h Handler) deleteSavedMessage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	messageID, err := strconv.Atoi(mux.Vars(r)["saved_id"])
 	if err != nil {
-		h.log.Error().Err(err).Msg("convert saved message id to int")
+		log.Error().Err(err).Msg("convert saved message id to int")
 
 		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
 		return
 	}
 
-	user, err := h.service.WebUser.GetWebUserByEmail(h.getUserFromSession(r))
-	if err != nil {
-		h.log.Error().Err(err).Msg("get web user by email")
-
-		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
 		return
 	}
 
-	err = h.service.Saved.DeleteSavedMessage(messageID)
+	err = h.service.Saved.DeleteSavedMessage(r.Context(), messageID)
 	if err != nil {
-		h.log.Error().Err(err).Msg("delete saved message")
+		log.Error().Err(err).Msg("delete saved message")
 	}
 
 	http.Redirect(w, r, fmt.Sprintf("/saved/%d", user.ID), http.StatusMovedPermanently)
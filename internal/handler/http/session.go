@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+)
+
+const sessionCookieName = "session"
+
+type sessionsPageData struct {
+	DefaultPageData  PageData
+	Sessions         []model.Session
+	CurrentSessionID string
+}
+
+type sessionKey struct{}
+
+type webUserKey struct{}
+
+// session loads session by the token from the cookie and puts it together with the web user into the request context.
+// Requests without a valid session are served as anonymous ones.
+func (h Handler) session(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookieName)
+		if err != nil || cookie.Value == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		log := h.log.ForContext(r.Context())
+
+		session, err := h.service.Session.GetSession(r.Context(), cookie.Value)
+		if err != nil {
+			if errors.Is(err, service.ErrSessionNotFound) {
+				h.clearSessionCookie(w)
+			} else {
+				log.Error().Err(err).Msg("get session")
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		user, err := h.service.WebUser.GetWebUserByID(r.Context(), session.UserID)
+		if err != nil {
+			log.Error().Err(err).Msg("get web user by id")
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if user.Banned() {
+			h.clearSessionCookie(w)
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), sessionKey{}, session)
+		ctx = context.WithValue(ctx, webUserKey{}, user)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// sessionFromContext returns session of the current request, nil means that user is not logged in.
+func sessionFromContext(ctx context.Context) *model.Session {
+	session, _ := ctx.Value(sessionKey{}).(*model.Session)
+
+	return session
+}
+
+// webUserFromContext returns web user of the current request, nil means that user is not logged in.
+func webUserFromContext(ctx context.Context) *model.WebUser {
+	user, _ := ctx.Value(webUserKey{}).(*model.WebUser)
+
+	return user
+}
+
+// startSession creates session for the user and sends its token in the cookie.
+func (h Handler) startSession(w http.ResponseWriter, r *http.Request, userID int) error {
+	sessionToken, err := h.service.Session.CreateSession(r.Context(), userID, r.UserAgent(), clientIP(r))
+	if err != nil {
+		return fmt.Errorf("create session: %w", err)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    sessionToken,
+		Path:     "/",
+		Expires:  time.Now().Add(h.sessionTTL),
+		MaxAge:   int(h.sessionTTL.Seconds()),
+		Secure:   h.cookieSecure,
+		HttpOnly: true,
+		SameSite: h.cookieSameSite,
+	})
+
+	return nil
+}
+
+// endSession deletes session of the current request and removes the cookie.
+func (h Handler) endSession(w http.ResponseWriter, r *http.Request) error {
+	defer h.clearSessionCookie(w)
+
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil || cookie.Value == "" {
+		return nil
+	}
+
+	if err := h.service.Session.DeleteSession(r.Context(), cookie.Value); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+
+	return nil
+}
+
+func (h Handler) clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    "",
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		Secure:   h.cookieSecure,
+		HttpOnly: true,
+		SameSite: h.cookieSameSite,
+	})
+}
+
+// clientIP returns address of the connected client, proxy headers are not trusted.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func parseSameSite(value string) http.SameSite {
+	switch value {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+func (h Handler) loadSessionsPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	data := sessionsPageData{
+		DefaultPageData: PageData{
+			Type:            "sessions",
+			Title:           "Active sessions",
+			WebUserEmail:    user.Email,
+			WebUserID:       user.ID,
+			WebUserVerified: user.EmailVerified,
+			WebUserRole:     user.Role,
+			CSRFToken:       csrfTokenFromContext(r.Context()),
+		},
+		CurrentSessionID: sessionFromContext(r.Context()).ID,
+	}
+
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("get channels for navbar")
+	}
+	if navBarChannels != nil {
+		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
+		data.DefaultPageData.ChannelsLength = len(navBarChannels)
+	}
+
+	sessions, err := h.service.Session.GetUserSessions(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, service.ErrSessionsNotFound) {
+		log.Error().Err(err).Msg("get user sessions")
+	}
+	data.Sessions = sessions
+
+	err = h.templates.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		log.Error().Err(err).Msg("load sessions page")
+	}
+}
+
+// deleteSession logs out the user on one of the devices.
+func (h Handler) deleteSession(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	sessionID := mux.Vars(r)["session_id"]
+
+	err := h.service.Session.DeleteUserSession(r.Context(), user.ID, sessionID)
+	if err != nil {
+		log.Error().Err(err).Msg("delete user session")
+	}
+
+	if err == nil && sessionFromContext(r.Context()).ID == sessionID {
+		h.clearSessionCookie(w)
+
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	http.Redirect(w, r, "/auth/sessions", http.StatusFound)
+}
+
+// logoutEverywhere deletes all sessions of the user including the current one.
+func (h Handler) logoutEverywhere(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	err := h.service.Session.DeleteUserSessions(r.Context(), user.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("delete user sessions")
+
+		http.Redirect(w, r, "/auth/sessions", http.StatusFound)
+		return
+	}
+
+	h.clearSessionCookie(w)
+
+	http.Redirect(w, r, "/auth/login", http.StatusFound)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+)
+
+type tokensPageData struct {
+	DefaultPageData PageData
+	Tokens          []model.APIToken
+	// NewToken is shown only once right after it's created.
+	NewToken string
+	Message  string
+}
+
+func (h Handler) loadTokensPage(w http.ResponseWriter, r *http.Request) {
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	h.executeTokensTemplate(w, r, h.newTokensPageData(r))
+}
+
+func (h Handler) createAPIToken(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		log.Error().Err(err).Msg("parse form")
+	}
+
+	input := &service.CreateAPITokenInput{
+		Name:   r.PostForm.Get("name"),
+		Scopes: r.PostForm["scopes"],
+	}
+
+	if value := r.PostForm.Get("ttl"); value != "" {
+		input.TTL, err = time.ParseDuration(value)
+		if err != nil || input.TTL < 0 {
+			data := h.newTokensPageData(r)
+			data.Message = "Expiration is invalid!"
+
+			h.executeTokensTemplate(w, r, data)
+			return
+		}
+	}
+
+	apiToken, err := h.service.APIToken.CreateAPIToken(r.Context(), user.ID, input)
+
+	data := h.newTokensPageData(r)
+
+	switch {
+	case err == nil:
+		data.NewToken = apiToken
+	case errors.Is(err, service.ErrInvalidAPITokenName):
+		data.Message = "Token name must be from 1 to 100 characters!"
+	case errors.Is(err, service.ErrInvalidAPITokenScope):
+		data.Message = "Choose at least one scope!"
+	default:
+		log.Error().Err(err).Msg("create api token")
+
+		data.Message = "Failed to create token!"
+	}
+
+	h.executeTokensTemplate(w, r, data)
+}
+
+func (h Handler) revokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	tokenID, err := strconv.Atoi(mux.Vars(r)["token_id"])
+	if err != nil {
+		log.Error().Err(err).Msg("convert token id to int")
+
+		http.Redirect(w, r, "/auth/tokens", http.StatusFound)
+		return
+	}
+
+	err = h.service.APIToken.RevokeAPIToken(r.Context(), user.ID, tokenID)
+	if err != nil {
+		log.Error().Err(err).Msg("revoke api token")
+	}
+
+	http.Redirect(w, r, "/auth/tokens", http.StatusFound)
+}
+
+// newTokensPageData loads tokens after the change, so the page shows the created token in the list.
+func (h Handler) newTokensPageData(r *http.Request) tokensPageData {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+
+	data := tokensPageData{
+		DefaultPageData: PageData{
+			Type:            "tokens",
+			Title:           "API tokens",
+			WebUserEmail:    user.Email,
+			WebUserID:       user.ID,
+			WebUserVerified: user.EmailVerified,
+			WebUserRole:     user.Role,
+			CSRFToken:       csrfTokenFromContext(r.Context()),
+		},
+	}
+
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("get channels for navbar")
+	}
+	if navBarChannels != nil {
+		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
+		data.DefaultPageData.ChannelsLength = len(navBarChannels)
+	}
+
+	tokens, err := h.service.APIToken.GetUserAPITokens(r.Context(), user.ID)
+	if err != nil && !errors.Is(err, service.ErrAPITokensNotFound) {
+		log.Error().Err(err).Msg("get user api tokens")
+	}
+	data.Tokens = tokens
+
+	return data
+}
+
+func (h Handler) executeTokensTemplate(w http.ResponseWriter, r *http.Request, data tokensPageData) {
+	err := h.templates.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("load api tokens page")
+	}
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/service"
+)
+
+const (
+	loginChallengeCookieName = "login_2fa"
+
+	// loginChallengeCookieTTL matches lifetime of the signed challenge stored in the cookie.
+	loginChallengeCookieTTL = 5 * time.Minute
+)
+
+type twoFactorPageData struct {
+	DefaultPageData PageData
+	Enabled         bool
+	Enrollment      *service.TwoFactorEnrollment
+	// ProvisioningURI has otpauth scheme which html/template would otherwise replace.
+	ProvisioningURI template.URL
+	RecoveryCodes   []string
+	Message         string
+	Notice          string
+}
+
+func (h Handler) setLoginChallengeCookie(w http.ResponseWriter, challenge string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginChallengeCookieName,
+		Value:    challenge,
+		Path:     "/auth/login",
+		Expires:  time.Now().Add(loginChallengeCookieTTL),
+		MaxAge:   int(loginChallengeCookieTTL.Seconds()),
+		Secure:   h.cookieSecure,
+		HttpOnly: true,
+		SameSite: h.cookieSameSite,
+	})
+}
+
+func (h Handler) clearLoginChallengeCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginChallengeCookieName,
+		Value:    "",
+		Path:     "/auth/login",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		Secure:   h.cookieSecure,
+		HttpOnly: true,
+		SameSite: h.cookieSameSite,
+	})
+}
+
+// loadLoginCodePage asks for the code from the authenticator app after the password is checked.
+func (h Handler) loadLoginCodePage(w http.ResponseWriter, r *http.Request) {
+	if _, err := r.Cookie(loginChallengeCookieName); err != nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	data := authPageData{
+		Title:     "Two-factor authentication",
+		CSRFToken: csrfTokenFromContext(r.Context()),
+	}
+
+	h.executeAuthTemplate(w, r, "templates/auth/twofactor.html", data)
+}
+
+// loginWithCode finishes login of the user with two-factor authentication.
+func (h Handler) loginWithCode(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	cookie, err := r.Cookie(loginChallengeCookieName)
+	if err != nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	data := authPageData{
+		Title:     "Two-factor authentication",
+		CSRFToken: csrfTokenFromContext(r.Context()),
+	}
+
+	userID, err := h.service.TwoFactor.VerifyLoginChallenge(
+		r.Context(), cookie.Value, r.PostFormValue("code"), clientIP(r),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLoginChallengeInvalid):
+			h.clearLoginChallengeCookie(w)
+
+			http.Redirect(w, r, "/auth/login", http.StatusFound)
+			return
+		case errors.Is(err, service.ErrInvalidTwoFactorCode):
+			data.Message = "Code is incorrect!"
+		case errors.Is(err, service.ErrTooManyLoginAttempts):
+			data.Message = "Too many failed login attempts, try again later!"
+
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			log.Error().Err(err).Msg("verify login challenge")
+
+			data.Message = "Failed to login!"
+		}
+
+		h.executeAuthTemplate(w, r, "templates/auth/twofactor.html", data)
+		return
+	}
+
+	err = h.startSession(w, r, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("start user session")
+
+		data.Message = "Failed to login!"
+
+		h.executeAuthTemplate(w, r, "templates/auth/twofactor.html", data)
+		return
+	}
+
+	h.clearLoginChallengeCookie(w)
+
+	http.Redirect(w, r, "/home", http.StatusFound)
+}
+
+func (h Handler) loadTwoFactorPage(w http.ResponseWriter, r *http.Request) {
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	h.executeTwoFactorTemplate(w, r, h.newTwoFactorPageData(r))
+}
+
+// startTwoFactorEnrollment shows the secret which should be added to the authenticator app.
+func (h Handler) startTwoFactorEnrollment(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	data := h.newTwoFactorPageData(r)
+
+	enrollment, err := h.service.TwoFactor.StartEnrollment(r.Context(), user.ID)
+	switch {
+	case err == nil:
+		data.Enrollment = enrollment
+		data.ProvisioningURI = template.URL(enrollment.ProvisioningURI) //nolint:gosec // built by the service from escaped values
+	case errors.Is(err, service.ErrTwoFactorEnabled):
+		data.Message = "Two-factor authentication is already enabled!"
+	default:
+		log.Error().Err(err).Msg("start two-factor enrollment")
+
+		data.Message = "Failed to start two-factor authentication setup!"
+	}
+
+	h.executeTwoFactorTemplate(w, r, data)
+}
+
+// confirmTwoFactorEnrollment enables two-factor authentication and shows recovery codes once.
+func (h Handler) confirmTwoFactorEnrollment(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	data := h.newTwoFactorPageData(r)
+
+	codes, err := h.service.TwoFactor.ConfirmEnrollment(r.Context(), user.ID, r.PostFormValue("code"))
+	switch {
+	case err == nil:
+		data.Enabled = true
+		data.RecoveryCodes = codes
+		data.Notice = "Two-factor authentication is enabled."
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		data.Message = "Code is incorrect, scan the key again and enter a new code!"
+	case errors.Is(err, service.ErrTwoFactorEnabled):
+		data.Message = "Two-factor authentication is already enabled!"
+	case errors.Is(err, service.ErrTwoFactorNotEnrolled):
+		data.Message = "Start two-factor authentication setup first!"
+	default:
+		log.Error().Err(err).Msg("confirm two-factor enrollment")
+
+		data.Message = "Failed to enable two-factor authentication!"
+	}
+
+	h.executeTwoFactorTemplate(w, r, data)
+}
+
+func (h Handler) disableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	data := h.newTwoFactorPageData(r)
+
+	err := h.service.TwoFactor.Disable(r.Context(), user.ID, r.PostFormValue("password"))
+	switch {
+	case err == nil:
+		data.Enabled = false
+		data.Notice = "Two-factor authentication is disabled."
+	case errors.Is(err, service.ErrIncorrectPassword):
+		data.Message = "User password is incorrect!"
+	default:
+		log.Error().Err(err).Msg("disable two-factor authentication")
+
+		data.Message = "Failed to disable two-factor authentication!"
+	}
+
+	h.executeTwoFactorTemplate(w, r, data)
+}
+
+func (h Handler) newTwoFactorPageData(r *http.Request) twoFactorPageData {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+
+	data := twoFactorPageData{
+		DefaultPageData: PageData{
+			Type:            "twofactor",
+			Title:           "Two-factor authentication",
+			WebUserEmail:    user.Email,
+			WebUserID:       user.ID,
+			WebUserVerified: user.EmailVerified,
+			WebUserRole:     user.Role,
+			CSRFToken:       csrfTokenFromContext(r.Context()),
+		},
+		Enabled: user.TOTPEnabled,
+	}
+
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("get channels for navbar")
+	}
+	if navBarChannels != nil {
+		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
+		data.DefaultPageData.ChannelsLength = len(navBarChannels)
+	}
+
+	return data
+}
+
+func (h Handler) executeTwoFactorTemplate(w http.ResponseWriter, r *http.Request, data twoFactorPageData) {
+	err := h.templates.ExecuteTemplate(w, "base", data)
+	if err != nil {
+		h.log.ForContext(r.Context()).Error().Err(err).Msg("load two-factor authentication page")
+	}
+}
@@ -12,58 +12,62 @@ import (
 type UserPageData struct {
 	DefaultPageData PageData
 	User            model.User
+	History         []model.UserHistory
 	Messages        []model.FullMessage
 	MessagesLength  int
 }
 
 func (h Handler) loadUserPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
 	data := UserPageData{
 		DefaultPageData: PageData{
 			Type:         "user",
 			Title:        "Telegram User",
 			WebUserEmail: "",
 			WebUserID:    0,
+			CSRFToken:    csrfTokenFromContext(r.Context()),
 		},
 	}
 
 	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
 	if err != nil {
-		h.log.Error().Err(err).Msg("covert user id to int")
+		log.Error().Err(err).Msg("covert user id to int")
 
 		http.Redirect(w, r, "/home", http.StatusMovedPermanently)
 		return
 	}
 
-	navBarChannels, err := h.service.Channel.GetChannels()
+	navBarChannels, err := h.service.Channel.GetChannels(r.Context())
 	if err != nil {
-		h.log.Error().Err(err).Msg("get channels for navbar")
+		log.Error().Err(err).Msg("get channels for navbar")
 	}
 	if navBarChannels != nil {
 		data.DefaultPageData.Channels = GetRightChannelsCountForNavBar(navBarChannels)
 		data.DefaultPageData.ChannelsLength = len(navBarChannels)
 	}
 
-	user, err := h.service.WebUser.GetWebUserByEmail(h.getUserFromSession(r))
-	if err != nil {
-		h.log.Error().Err(err).Msg("get web user by email")
-	}
+	user := webUserFromContext(r.Context())
 	if user != nil {
 		data.DefaultPageData.WebUserEmail = user.Email
 		data.DefaultPageData.WebUserID = user.ID
+		data.DefaultPageData.WebUserVerified = user.EmailVerified
+		data.DefaultPageData.WebUserRole = user.Role
 	}
 
-	pageData, err := h.service.User.ProcessUserPage(userID)
+	pageData, err := h.service.User.ProcessUserPage(r.Context(), userID)
 	if err != nil {
-		h.log.Error().Err(err).Msg("get data for usar page")
+		log.Error().Err(err).Msg("get data for usar page")
 	}
 	if pageData != nil {
 		data.User = *pageData.TgUser
+		data.History = pageData.History
 		data.Messages = pageData.Messages
 		data.MessagesLength = len(pageData.Messages)
 	}
 
 	err = h.templates.ExecuteTemplate(w, "base", data)
 	if err != nil {
-		h.log.Error().Err(err).Msg("load user page")
+		log.Error().Err(err).Msg("load user page")
 	}
 }
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/VladPetriv/scanner_backend/internal/service"
+)
+
+// loadVerifyEmailPage confirms email by the link from the verification email,
+// without the token it offers logged in user to resend the link.
+func (h Handler) loadVerifyEmailPage(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	data := authPageData{
+		Title:     "Verify email",
+		CSRFToken: csrfTokenFromContext(r.Context()),
+	}
+
+	user := webUserFromContext(r.Context())
+
+	verificationToken := r.URL.Query().Get("token")
+	if verificationToken == "" {
+		switch {
+		case user == nil:
+			http.Redirect(w, r, "/auth/login", http.StatusFound)
+			return
+		case user.EmailVerified:
+			data.Notice = "Your email is already verified."
+		default:
+			data.Notice = "Follow the link from the email we have sent you to confirm the email."
+			data.ShowResend = true
+		}
+
+		h.executeAuthTemplate(w, r, "templates/auth/verify.html", data)
+		return
+	}
+
+	err := h.service.Verification.VerifyEmail(r.Context(), verificationToken)
+	switch {
+	case err == nil:
+		data.Notice = "Your email is verified."
+	case errors.Is(err, service.ErrVerificationTokenInvalid):
+		data.Message = "Verification link is invalid or expired!"
+		data.ShowResend = user != nil && !user.EmailVerified
+	default:
+		log.Error().Err(err).Msg("verify email")
+
+		data.Message = "Failed to verify email!"
+	}
+
+	h.executeAuthTemplate(w, r, "templates/auth/verify.html", data)
+}
+
+func (h Handler) resendVerification(w http.ResponseWriter, r *http.Request) {
+	log := h.log.ForContext(r.Context())
+
+	user := webUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, "/auth/login", http.StatusFound)
+		return
+	}
+
+	data := authPageData{
+		Title:     "Verify email",
+		CSRFToken: csrfTokenFromContext(r.Context()),
+	}
+
+	err := h.service.Verification.SendVerification(r.Context(), user.Email)
+	switch {
+	case err == nil:
+		data.Notice = "We have sent a new verification link to " + user.Email + "."
+	case errors.Is(err, service.ErrEmailAlreadyVerified):
+		data.Notice = "Your email is already verified."
+	default:
+		log.Error().Err(err).Msg("resend verification email")
+
+		data.Message = "Failed to send verification link!"
+		data.ShowResend = true
+	}
+
+	h.executeAuthTemplate(w, r, "templates/auth/verify.html", data)
+}
@@ -0,0 +1,108 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/Shopify/sarama"
+)
+
+// partitionConsumer reads a topic partition and commits offsets of the processed records for the consumer group.
+type partitionConsumer struct {
+	sarama.PartitionConsumer
+
+	client        sarama.Client
+	offsetManager sarama.OffsetManager
+	offsets       sarama.PartitionOffsetManager
+}
+
+func connectAsConsumer(addr string, group string, topic string) (*partitionConsumer, error) {
+	config := sarama.NewConfig()
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+
+	var (
+		c   partitionConsumer
+		err error
+	)
+
+	c.client, err = sarama.NewClient([]string{addr}, config)
+	if err != nil {
+		return nil, fmt.Errorf("create client: %w", err)
+	}
+
+	c.offsetManager, err = sarama.NewOffsetManagerFromClient(group, c.client)
+	if err != nil {
+		c.Close() //nolint:errcheck // creation error is more relevant
+
+		return nil, fmt.Errorf("create offset manager: %w", err)
+	}
+
+	c.offsets, err = c.offsetManager.ManagePartition(topic, 0)
+	if err != nil {
+		c.Close() //nolint:errcheck // creation error is more relevant
+
+		return nil, fmt.Errorf("manage partition offsets: %w", err)
+	}
+
+	worker, err := sarama.NewConsumerFromClient(c.client)
+	if err != nil {
+		c.Close() //nolint:errcheck // creation error is more relevant
+
+		return nil, fmt.Errorf("create consumer: %w", err)
+	}
+
+	// NextOffset returns the initial offset when the group has not committed anything yet.
+	offset, _ := c.offsets.NextOffset()
+
+	c.PartitionConsumer, err = worker.ConsumePartition(topic, 0, offset)
+	if err != nil {
+		c.Close() //nolint:errcheck // creation error is more relevant
+
+		return nil, fmt.Errorf("create partition consumer: %w", err)
+	}
+
+	return &c, nil
+}
+
+// markProcessed marks the record as processed, the offset is committed in background and on close.
+func (c *partitionConsumer) markProcessed(message *sarama.ConsumerMessage) {
+	c.offsets.MarkOffset(message.Offset+1, "")
+}
+
+// Close stops fetching of new records and commits offsets of the processed ones.
+// All resources are released even if some of them fail to close, the first error is returned.
+func (c *partitionConsumer) Close() error {
+	var result error
+
+	keep := func(err error) {
+		if result == nil {
+			result = err
+		}
+	}
+
+	if c.PartitionConsumer != nil {
+		if err := c.PartitionConsumer.Close(); err != nil {
+			keep(fmt.Errorf("close partition consumer: %w", err))
+		}
+	}
+
+	if c.offsets != nil {
+		c.offsetManager.Commit()
+
+		if err := c.offsets.Close(); err != nil {
+			keep(fmt.Errorf("close partition offset manager: %w", err))
+		}
+	}
+
+	if c.offsetManager != nil {
+		if err := c.offsetManager.Close(); err != nil {
+			keep(fmt.Errorf("close offset manager: %w", err))
+		}
+	}
+
+	if err := c.client.Close(); err != nil {
+		keep(fmt.Errorf("close client: %w", err))
+	}
+
+	return result
+}
@@ -1,11 +1,16 @@
 package kafka
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Shopify/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/VladPetriv/scanner_backend/internal/model"
 	"github.com/VladPetriv/scanner_backend/internal/service"
 	"github.com/VladPetriv/scanner_backend/pkg/config"
@@ -16,156 +21,616 @@ type kafka struct {
 	SrvManager *service.Manager
 	Cfg        *config.Config
 	Log        *logger.Logger
+	ErrSink    ErrorSink
+
+	metrics        *queueMetrics
+	statuses       *consumerStatuses
+	replays        *replays
+	channelsFormat Format
+	messagesFormat Format
 }
 
-func New(srvManager *service.Manager, cfg *config.Config, log *logger.Logger) Queue {
+// contentTypeHeader is a record header which overrides the configured topic format.
+const contentTypeHeader = "content-type"
+
+const (
+	channelsTopic = "groups"
+	messagesTopic = "messages"
+)
+
+// reconnectDelay is a time between attempts to restore connection of the consumer.
+const reconnectDelay = 5 * time.Second
+
+var (
+	errConsumerClosed = errors.New("consumer closed")
+	// ErrUnknownTopic is returned on reprocessing of the record from the topic which isn't consumed.
+	ErrUnknownTopic = errors.New("unknown topic")
+	// ErrReprocessFailed is returned when the record failed ingestion again.
+	ErrReprocessFailed = errors.New("reprocess failed")
+	// ErrReplaysStopped is returned on start of the replay before RunReplays is called or after shutdown.
+	ErrReplaysStopped = errors.New("replays stopped")
+)
+
+// processFunc processes the record and returns outcome of the processing,
+// the error describes why the record was rejected or failed.
+type processFunc func(ctx context.Context, message *sarama.ConsumerMessage) (string, error)
+
+func New(srvManager *service.Manager, cfg *config.Config, log *logger.Logger, registerer prometheus.Registerer) Queue {
+	errSink := newLogSink(log)
+
+	if cfg.KafkaErrTopic != "" {
+		sink, err := newTopicSink(cfg.KafkaAddr, cfg.KafkaErrTopic)
+		if err != nil {
+			log.Error().Err(err).Msg("create error sink, rejected records will be logged only")
+		} else {
+			errSink = sink
+		}
+	}
+
+	channelsFormat, err := ParseFormat(cfg.ChannelsFormat)
+	if err != nil {
+		log.Error().Err(err).Msg("parse channels format, json will be used")
+
+		channelsFormat = FormatJSON
+	}
+
+	messagesFormat, err := ParseFormat(cfg.MessagesFormat)
+	if err != nil {
+		log.Error().Err(err).Msg("parse messages format, json will be used")
+
+		messagesFormat = FormatJSON
+	}
+
 	return kafka{
-		SrvManager: srvManager,
-		Cfg:        cfg,
-		Log:        log,
+		SrvManager:     srvManager,
+		Cfg:            cfg,
+		Log:            log,
+		ErrSink:        errSink,
+		metrics:        newQueueMetrics(registerer),
+		statuses:       newConsumerStatuses(channelsTopic, messagesTopic),
+		replays:        newReplays(),
+		channelsFormat: channelsFormat,
+		messagesFormat: messagesFormat,
+	}
+}
+
+func (k kafka) SaveChannelsData(ctx context.Context) {
+	k.consume(ctx, channelsTopic, k.processChannelData)
+}
+
+func (k kafka) SaveMessagesData(ctx context.Context) {
+	k.consume(ctx, messagesTopic, k.processMessageData)
+}
+
+func (k kafka) Status() []model.ConsumerStatus {
+	return k.statuses.list()
+}
+
+// Reprocess processes again the record which failed ingestion, the record is forgotten once it's saved.
+func (k kafka) Reprocess(ctx context.Context, ingestionErrorID int) error {
+	ingestionError, err := k.SrvManager.Ingestion.GetIngestionError(ctx, ingestionErrorID)
+	if err != nil {
+		return err
+	}
+
+	var process processFunc
+
+	switch ingestionError.Topic {
+	case channelsTopic:
+		process = k.processChannelData
+	case messagesTopic:
+		process = k.processMessageData
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownTopic, ingestionError.Topic)
+	}
+
+	message := newConsumerMessage(
+		ingestionError.Topic, ingestionError.Partition, ingestionError.Offset,
+		ingestionError.ContentType, ingestionError.Value,
+	)
+
+	outcome, reason := process(ctx, message)
+	if reason != nil {
+		return fmt.Errorf("%w: %s", ErrReprocessFailed, reason.Error())
 	}
+
+	k.Log.Info().Int("id", ingestionErrorID).Str("outcome", outcome).Msg("record reprocessed")
+
+	return k.SrvManager.Ingestion.DeleteIngestionError(ctx, ingestionErrorID)
 }
 
-func (k kafka) SaveChannelsData() {
-	consumer, err := connectAsConsumer(k.Cfg.KafkaAddr, "groups")
+// RunReplays runs replays of buffered records started by StartReplay, it blocks until the context is cancelled
+// and the running replays are stopped.
+func (k kafka) RunReplays(ctx context.Context) {
+	k.replays.run(ctx)
+}
+
+// StartReplay starts replay of the records buffered while the channel was disabled, the replay outlives the request.
+// Only one replay of the channel runs at a time, the call is a no-op if it's already running.
+func (k kafka) StartReplay(ctx context.Context, channelID int) error {
+	rootCtx, started, err := k.replays.start(channelID)
 	if err != nil {
-		k.Log.Error().Err(err).Msg("connect to queue as consumer")
+		return err
+	}
+
+	if !started {
+		k.Log.ForContext(ctx).Info().Int("channel id", channelID).Msg("replay is already running")
+
+		return nil
 	}
 
+	// Only the request id is kept for the logs.
+	replayCtx := logger.ContextWithRequestID(rootCtx, logger.RequestIDFromContext(ctx))
+
 	go func() {
-		for {
-			select {
-			case err := <-consumer.Errors():
-				k.Log.Error().Err(err).Msg("get data from queue")
+		defer k.replays.finish(channelID)
 
-				return
-			case messages := <-consumer.Messages():
-				var channel model.DBChannel
+		replayed, err := k.replayBuffered(replayCtx, channelID)
+		if err != nil {
+			k.Log.ForContext(replayCtx).Error().Err(err).Int("channel id", channelID).Int("count", replayed).
+				Msg("replay buffered records")
+		}
+	}()
 
-				err := json.Unmarshal(messages.Value, &channel)
-				if err != nil {
-					k.Log.Error().Err(err).Msg("unmarshal channel data")
+	return nil
+}
 
-					continue
-				}
+// replayBuffered processes the buffered records of the channel after it's enabled and returns number of them.
+// Records are claimed one by one, so a record is never replayed twice. Records which fail again are kept
+// as ingestion errors, replay stops if the channel is disabled again or on shutdown.
+func (k kafka) replayBuffered(ctx context.Context, channelID int) (int, error) {
+	var replayed int
 
-				err = k.SrvManager.Channel.CreateChannel(&channel)
-				if err != nil {
-					if errors.Is(err, service.ErrChannelExists) {
-						k.Log.Warn().Err(err).Msgf("channel with name %s already exists", channel.Name)
+	// Shutdown must not interrupt saving of the claimed record, so it's processed with its own context.
+	processCtx := logger.ContextWithRequestID(context.Background(), logger.RequestIDFromContext(ctx))
 
-						continue
-					}
+	for ctx.Err() == nil {
+		record, err := k.SrvManager.Ingestion.ClaimBufferedRecord(processCtx, channelID)
+		if err != nil {
+			if errors.Is(err, service.ErrBufferedRecordsNotFound) {
+				k.Log.ForContext(ctx).Info().Int("channel id", channelID).Int("count", replayed).
+					Msg("buffered records replayed")
 
-					k.Log.Error().Err(err).Msg("create channel")
-				}
+				return replayed, nil
 			}
+
+			return replayed, err
 		}
+
+		message := newConsumerMessage(record.Topic, record.Partition, record.Offset, record.ContentType, record.Value)
+
+		outcome, reason := k.processMessageData(processCtx, message)
+		if reason != nil {
+			k.recordIngestionError(processCtx, message, reason)
+		}
+
+		if outcome == outcomeBuffered {
+			k.Log.ForContext(ctx).Info().Int("channel id", channelID).Msg("channel is disabled again, replay stopped")
+
+			return replayed, nil
+		}
+
+		replayed++
+	}
+
+	k.Log.ForContext(ctx).Info().Int("channel id", channelID).Int("count", replayed).
+		Msg("replay stopped on shutdown, the rest of records stays buffered")
+
+	return replayed, nil
+}
+
+// ReleaseQuarantined saves the message which was caught by the filter, the filter isn't applied to it again.
+func (k kafka) ReleaseQuarantined(ctx context.Context, quarantinedMessageID int) error {
+	quarantinedMessage, err := k.SrvManager.Ingestion.GetQuarantinedMessage(ctx, quarantinedMessageID)
+	if err != nil {
+		return err
+	}
+
+	message := newConsumerMessage(
+		quarantinedMessage.Topic, quarantinedMessage.Partition, quarantinedMessage.Offset,
+		quarantinedMessage.ContentType, quarantinedMessage.Value,
+	)
+
+	outcome, reason := k.saveMessageData(ctx, message, false)
+	if reason != nil {
+		return fmt.Errorf("%w: %s", ErrReprocessFailed, reason.Error())
+	}
+
+	k.Log.Info().Int("id", quarantinedMessageID).Str("outcome", outcome).Msg("quarantined message released")
+
+	return k.SrvManager.Ingestion.DeleteQuarantinedMessage(ctx, quarantinedMessageID)
+}
+
+// consume processes records of the topic until the context is cancelled, lost connection is restored after a delay.
+func (k kafka) consume(ctx context.Context, topic string, process processFunc) {
+	defer func() {
+		k.statuses.setState(topic, model.ConsumerStateStopped, nil)
+		k.Log.Info().Str("topic", topic).Msg("consumer stopped")
 	}()
+
+	for {
+		err := k.consumeSession(ctx, topic, process)
+		if ctx.Err() != nil {
+			return
+		}
+
+		k.Log.Error().Err(err).Str("topic", topic).Msg("consume records")
+		k.statuses.setState(topic, model.ConsumerStateDisconnected, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
 }
 
-func (k kafka) SaveMessagesData() { //nolint:gocognit
-	consumer, err := connectAsConsumer(k.Cfg.KafkaAddr, "messages")
+// consumeSession processes records until the context is cancelled or the consumer is closed.
+// Offsets of the processed records are committed when the session ends.
+func (k kafka) consumeSession(ctx context.Context, topic string, process processFunc) error {
+	consumer, err := connectAsConsumer(k.Cfg.KafkaAddr, k.Cfg.KafkaGroup, topic)
 	if err != nil {
-		k.Log.Error().Err(err).Msg("connect to queue as consumer")
+		return fmt.Errorf("connect to queue as consumer: %w", err)
 	}
 
-	go func() {
-		for {
-			select {
-			case err := <-consumer.Errors():
-				k.Log.Error().Err(err).Msg("get data from queue")
-
-				return
-			case data := <-consumer.Messages():
-				var telegramMessage model.TgMessage
-
-				err = json.Unmarshal(data.Value, &telegramMessage)
-				if err != nil {
-					k.Log.Error().Err(err).Msg("unmarshal message data")
-
-					continue
-				}
-
-				channel, err := k.SrvManager.Channel.GetChannelByName(telegramMessage.PeerID.Username)
-				if err != nil {
-					if !errors.Is(err, service.ErrChannelNotFound) {
-						k.Log.Error().Err(err).Msg("get channel by name")
-					}
-
-					continue
-				}
-
-				userID, err := k.SrvManager.User.CreateUser(&model.User{
-					Username: telegramMessage.FromID.Username,
-					FullName: telegramMessage.FromID.Fullname,
-					ImageURL: telegramMessage.FromID.ImageURL,
-				})
-				if err != nil {
-					k.Log.Error().Err(err).Msg("create user")
-
-					continue
-				}
-
-				messageID, err := k.SrvManager.Message.CreateMessage(&model.DBMessage{
-					ChannelID:  channel.ID,
-					UserID:     userID,
-					Title:      telegramMessage.Message,
-					MessageURL: telegramMessage.MessageURL,
-					ImageURL:   telegramMessage.ImageURL,
-				})
-				if err != nil {
-					if !errors.Is(err, service.ErrMessageExists) {
-						k.Log.Error().Err(err).Msg("create message")
-					}
-
-					continue
-				}
-
-				k.processReplyData(messageID, &telegramMessage)
-			}
+	defer func() {
+		if err := consumer.Close(); err != nil {
+			k.Log.Error().Err(err).Str("topic", topic).Msg("close consumer")
 		}
 	}()
+
+	k.statuses.setState(topic, model.ConsumerStateRunning, nil)
+
+	// Shutdown must not interrupt saving of the current record, so it's processed with its own context.
+	processCtx := context.Background()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-consumer.Errors():
+			if !ok {
+				return errConsumerClosed
+			}
+
+			k.Log.Error().Err(err).Str("topic", topic).Msg("get data from queue")
+		case message, ok := <-consumer.Messages():
+			if !ok {
+				return errConsumerClosed
+			}
+
+			startedAt := time.Now()
+
+			outcome, reason := process(processCtx, message)
+			if reason != nil {
+				k.recordIngestionError(processCtx, message, reason)
+			}
+
+			consumer.markProcessed(message)
+			k.metrics.observe(consumer, message, outcome, startedAt)
+
+			if outcome == outcomeProcessed {
+				k.statuses.setIngested(topic, time.Now())
+			}
+		}
+	}
 }
 
-func (k kafka) processReplyData(messageID int, telegramMessage *model.TgMessage) {
+// processChannelData saves channel from the record and returns outcome of the processing.
+func (k kafka) processChannelData(ctx context.Context, message *sarama.ConsumerMessage) (string, error) {
+	format, err := recordFormat(message, k.channelsFormat)
+	if err != nil {
+		k.reject(message, k.channelsFormat, err)
+
+		return outcomeRejected, err
+	}
+
+	channel, _, err := DecodeChannel(message.Value, format)
+	if err != nil {
+		k.reject(message, k.channelsFormat, err)
+
+		return outcomeRejected, err
+	}
+
+	err = k.SrvManager.Channel.CreateChannel(ctx, channel)
+	if err == nil {
+		return outcomeProcessed, nil
+	}
+
+	if !errors.Is(err, service.ErrChannelExists) {
+		k.Log.Error().Err(err).Msg("create channel")
+
+		return outcomeFailed, err
+	}
+
+	err = k.SrvManager.Channel.UpdateChannel(ctx, channel)
+	if err != nil {
+		k.Log.Error().Err(err).Msgf("update channel with name %s", channel.Name)
+
+		return outcomeFailed, err
+	}
+
+	return outcomeProcessed, nil
+}
+
+// processMessageData saves message with its replies from the record and returns outcome of the processing.
+// Messages of unknown channels are skipped, messages of disabled channels are skipped or buffered.
+func (k kafka) processMessageData(ctx context.Context, data *sarama.ConsumerMessage) (string, error) {
+	return k.saveMessageData(ctx, data, true)
+}
+
+// saveMessageData saves message from the record, messages caught by the filter are skipped or quarantined.
+func (k kafka) saveMessageData(ctx context.Context, data *sarama.ConsumerMessage, filter bool) (string, error) {
+	format, err := recordFormat(data, k.messagesFormat)
+	if err != nil {
+		k.reject(data, k.messagesFormat, err)
+
+		return outcomeRejected, err
+	}
+
+	telegramMessage, _, err := DecodeMessage(data.Value, format)
+	if err != nil {
+		k.reject(data, k.messagesFormat, err)
+
+		return outcomeRejected, err
+	}
+
+	channel, err := k.SrvManager.Channel.GetChannelByName(ctx, telegramMessage.PeerID.Username)
+	if err != nil {
+		if errors.Is(err, service.ErrChannelNotFound) {
+			return outcomeSkipped, nil
+		}
+
+		k.Log.Error().Err(err).Msg("get channel by name")
+
+		return outcomeFailed, err
+	}
+
+	if !channel.Enabled {
+		return k.skipDisabledChannelRecord(ctx, channel.ID, data)
+	}
+
+	message := &model.DBMessage{
+		ChannelID:  channel.ID,
+		Title:      telegramMessage.Message,
+		MessageURL: telegramMessage.MessageURL,
+		ImageURL:   telegramMessage.ImageURL,
+		Kind:       k.SrvManager.Classifier.ClassifyMessage(telegramMessage.Message),
+	}
+
+	if filter {
+		// Users of the filtered messages aren't created, so the message is checked first.
+		verdict := k.SrvManager.Filter.FilterMessage(channel.Name, message)
+		if verdict != nil {
+			return k.skipFilteredMessage(ctx, message, verdict, data)
+		}
+	}
+
+	message.UserID, err = k.SrvManager.User.CreateUser(ctx, tgUserToModel(telegramMessage.FromID))
+	if err != nil {
+		k.Log.Error().Err(err).Msg("create user")
+
+		return outcomeFailed, err
+	}
+
+	messageID, err := k.SrvManager.Message.CreateMessage(ctx, message)
+	if err != nil {
+		if errors.Is(err, service.ErrMessageExists) {
+			return outcomeDuplicate, nil
+		}
+
+		k.Log.Error().Err(err).Msg("create message")
+
+		return outcomeFailed, err
+	}
+
+	k.processReplyData(ctx, messageID, telegramMessage)
+
+	return outcomeProcessed, nil
+}
+
+// skipDisabledChannelRecord buffers the record of the disabled channel when buffering is enabled,
+// so it can be replayed after the channel is enabled again.
+func (k kafka) skipDisabledChannelRecord(
+	ctx context.Context, channelID int, data *sarama.ConsumerMessage,
+) (string, error) {
+	if !k.Cfg.KafkaBufferDisabled {
+		return outcomeSkipped, nil
+	}
+
+	contentType, _ := recordContentType(data)
+
+	err := k.SrvManager.Ingestion.BufferRecord(ctx, &model.BufferedRecord{
+		ChannelID:   channelID,
+		Topic:       data.Topic,
+		Partition:   data.Partition,
+		Offset:      data.Offset,
+		ContentType: contentType,
+		Value:       data.Value,
+	})
+	if err != nil {
+		k.Log.Error().Err(err).Msg("buffer record")
+
+		return outcomeFailed, err
+	}
+
+	return outcomeBuffered, nil
+}
+
+// skipFilteredMessage counts the message caught by the filter and keeps it for review when quarantine is enabled.
+func (k kafka) skipFilteredMessage(
+	ctx context.Context, message *model.DBMessage, verdict *service.FilterVerdict, data *sarama.ConsumerMessage,
+) (string, error) {
+	k.metrics.observeFiltered(verdict.Rule)
+
+	if !k.Cfg.FilterQuarantine {
+		return outcomeFiltered, nil
+	}
+
+	contentType, _ := recordContentType(data)
+
+	err := k.SrvManager.Ingestion.QuarantineMessage(ctx, &model.QuarantinedMessage{
+		ChannelID:   message.ChannelID,
+		Title:       message.Title,
+		Rule:        verdict.Rule,
+		Reason:      verdict.Reason,
+		Topic:       data.Topic,
+		Partition:   data.Partition,
+		Offset:      data.Offset,
+		ContentType: contentType,
+		Value:       data.Value,
+	})
+	if err != nil {
+		k.Log.Error().Err(err).Msg("quarantine message")
+
+		return outcomeFailed, err
+	}
+
+	return outcomeFiltered, nil
+}
+
+func (k kafka) processReplyData(ctx context.Context, messageID int, telegramMessage *model.TgMessage) {
+	if len(telegramMessage.Replies.Messages) == 0 {
+		return
+	}
+
+	users := make([]model.User, 0, len(telegramMessage.Replies.Messages))
 	for _, reply := range telegramMessage.Replies.Messages {
-		userID, err := k.SrvManager.User.CreateUser(&model.User{
-			Username: reply.FromID.Username,
-			FullName: reply.FromID.Fullname,
-			ImageURL: reply.FromID.ImageURL,
-		})
-		if err != nil {
-			k.Log.Error().Err(err).Msg("create user for reply")
+		users = append(users, *tgUserToModel(reply.FromID))
+	}
+
+	userIDs, err := k.SrvManager.User.CreateUsers(ctx, users)
+	if err != nil {
+		k.Log.Error().Err(err).Msg("create users for replies")
+		k.metrics.observeReplies(outcomeFailed, len(telegramMessage.Replies.Messages))
+
+		return
+	}
+
+	replies := make([]model.DBReply, 0, len(telegramMessage.Replies.Messages))
+	for _, reply := range telegramMessage.Replies.Messages {
+		userID, ok := userIDs[reply.FromID.Username]
+		if !ok {
+			k.Log.Warn().Str("username", reply.FromID.Username).Msg("user for reply not created")
+			k.metrics.observeReplies(outcomeSkipped, 1)
 
 			continue
 		}
 
-		err = k.SrvManager.Reply.CreateReply(&model.DBReply{
+		replies = append(replies, model.DBReply{
 			MessageID: messageID,
 			UserID:    userID,
 			Title:     reply.Message,
 			ImageURL:  reply.ImageURL,
 		})
-		if err != nil {
-			k.Log.Error().Err(err).Msg("create reply")
-		}
 	}
+
+	err = k.SrvManager.Reply.CreateReplies(ctx, replies)
+	if err != nil {
+		k.Log.Error().Err(err).Msg("create replies")
+		k.metrics.observeReplies(outcomeFailed, len(replies))
+
+		return
+	}
+
+	k.metrics.observeReplies(outcomeProcessed, len(replies))
 }
 
-func connectAsConsumer(addr string, topic string) (sarama.PartitionConsumer, error) {
-	config := sarama.NewConfig()
-	config.Consumer.Return.Errors = true
+func tgUserToModel(user model.TgUser) *model.User {
+	result := &model.User{
+		Username: user.Username,
+		FullName: user.Fullname,
+		ImageURL: user.ImageURL,
+	}
+
+	if user.ID != 0 {
+		tgID := user.ID
+		result.TgID = &tgID
+	}
 
-	worker, err := sarama.NewConsumer([]string{addr}, config)
+	return result
+}
+
+// reject sends the record to the error sink, the content type is taken from the header or the topic format.
+func (k kafka) reject(message *sarama.ConsumerMessage, topicFormat Format, reason error) {
+	k.Log.Warn().Err(reason).Str("topic", message.Topic).Int64("offset", message.Offset).Msg("reject record")
+
+	contentType, ok := recordContentType(message)
+	if !ok {
+		contentType = string(topicFormat)
+	}
+
+	err := k.ErrSink.Send(&RejectedRecord{
+		Topic:       message.Topic,
+		Partition:   message.Partition,
+		Offset:      message.Offset,
+		Reason:      reason.Error(),
+		ContentType: contentType,
+		Value:       message.Value,
+		RejectedAt:  time.Now(),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("create consumer: %w", err)
+		k.Log.Error().Err(err).Msg("send rejected record to error sink")
 	}
+}
+
+// recordIngestionError keeps the record which was rejected or failed, so an admin can reprocess it.
+func (k kafka) recordIngestionError(ctx context.Context, message *sarama.ConsumerMessage, reason error) {
+	contentType, _ := recordContentType(message)
 
-	consumer, err := worker.ConsumePartition(topic, 0, sarama.OffsetOldest)
+	err := k.SrvManager.Ingestion.RecordIngestionError(ctx, &model.IngestionError{
+		Topic:       message.Topic,
+		Partition:   message.Partition,
+		Offset:      message.Offset,
+		ContentType: contentType,
+		Reason:      reason.Error(),
+		Value:       message.Value,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("create partition consumer: %w", err)
+		k.Log.Error().Err(err).Msg("record ingestion error")
+	}
+}
+
+// newConsumerMessage restores the stored record, the content type header is set only if it was present.
+func newConsumerMessage(
+	topic string, partition int32, offset int64, contentType string, value []byte,
+) *sarama.ConsumerMessage {
+	message := &sarama.ConsumerMessage{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+		Value:     value,
 	}
+	if contentType != "" {
+		message.Headers = []*sarama.RecordHeader{
+			{Key: []byte(contentTypeHeader), Value: []byte(contentType)},
+		}
+	}
+
+	return message
+}
+
+// recordFormat returns the format from the content type header or the topic format when header is not set.
+func recordFormat(message *sarama.ConsumerMessage, topicFormat Format) (Format, error) {
+	contentType, ok := recordContentType(message)
+	if !ok {
+		return topicFormat, nil
+	}
+
+	return ParseFormat(contentType)
+}
+
+// recordContentType returns value of the content type header and whether the header is set.
+func recordContentType(message *sarama.ConsumerMessage) (string, bool) {
+	for _, header := range message.Headers {
+		if header != nil && strings.EqualFold(string(header.Key), contentTypeHeader) {
+			return string(header.Value), true
+		}
+	}
+
+	return "", false
+}
 
-	return consumer, nil
+func partitionLabel(partition int32) string {
+	return strconv.Itoa(int(partition))
 }
@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/VladPetriv/scanner_backend/pkg/metrics"
+)
+
+// Outcomes of the record processing.
+const (
+	outcomeProcessed = "processed"
+	outcomeSkipped   = "skipped"
+	outcomeBuffered  = "buffered"
+	outcomeDuplicate = "duplicate"
+	outcomeFiltered  = "filtered"
+	outcomeRejected  = "rejected"
+	outcomeFailed    = "failed"
+)
+
+type queueMetrics struct {
+	records        *prometheus.CounterVec
+	replies        *prometheus.CounterVec
+	filtered       *prometheus.CounterVec
+	processingTime *prometheus.HistogramVec
+	lag            *prometheus.GaugeVec
+}
+
+func newQueueMetrics(registerer prometheus.Registerer) *queueMetrics {
+	m := &queueMetrics{
+		records: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "ingest",
+			Name:      "records_total",
+			Help:      "Number of consumed queue records by topic and outcome.",
+		}, []string{"topic", "outcome"}),
+		replies: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "ingest",
+			Name:      "replies_total",
+			Help:      "Number of ingested message replies by outcome.",
+		}, []string{"outcome"}),
+		filtered: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "ingest",
+			Name:      "filtered_messages_total",
+			Help:      "Number of messages caught by the filter by rule.",
+		}, []string{"rule"}),
+		processingTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "ingest",
+			Name:      "processing_duration_seconds",
+			Help:      "Time spent on processing of a single queue record.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"topic"}),
+		lag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "ingest",
+			Name:      "consumer_lag",
+			Help:      "Number of records in the partition which are not consumed yet.",
+		}, []string{"topic", "partition"}),
+	}
+
+	if registerer != nil {
+		registerer.MustRegister(m.records, m.replies, m.filtered, m.processingTime, m.lag)
+	}
+
+	return m
+}
+
+// observe records outcome, processing time and consumer lag of the record.
+func (m *queueMetrics) observe(consumer sarama.PartitionConsumer, message *sarama.ConsumerMessage, outcome string, startedAt time.Time) {
+	m.records.WithLabelValues(message.Topic, outcome).Inc()
+	m.processingTime.WithLabelValues(message.Topic).Observe(time.Since(startedAt).Seconds())
+
+	lag := consumer.HighWaterMarkOffset() - message.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+
+	m.lag.WithLabelValues(message.Topic, partitionLabel(message.Partition)).Set(float64(lag))
+}
+
+func (m *queueMetrics) observeReplies(outcome string, count int) {
+	m.replies.WithLabelValues(outcome).Add(float64(count))
+}
+
+func (m *queueMetrics) observeFiltered(rule string) {
+	m.filtered.WithLabelValues(rule).Inc()
+}
@@ -1,6 +1,18 @@
 package kafka
 
+import (
+	"context"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+// Queue consumes scanner data, save methods and RunReplays block until the context is cancelled.
 type Queue interface {
-	SaveChannelsData()
-	SaveMessagesData()
+	SaveChannelsData(ctx context.Context)
+	SaveMessagesData(ctx context.Context)
+	Status() []model.ConsumerStatus
+	Reprocess(ctx context.Context, ingestionErrorID int) error
+	RunReplays(ctx context.Context)
+	StartReplay(ctx context.Context, channelID int) error
+	ReleaseQuarantined(ctx context.Context, quarantinedMessageID int) error
 }
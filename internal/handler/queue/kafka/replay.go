@@ -0,0 +1,62 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+)
+
+// replays keeps the running replays of buffered records, it's shared between copies of the queue.
+// Replays run under the context of RunReplays, so they are stopped and waited for on shutdown.
+type replays struct {
+	mu      sync.Mutex
+	ctx     context.Context //nolint:containedctx // root context of the replays, it's set by RunReplays
+	running map[int]struct{}
+	wg      sync.WaitGroup
+}
+
+func newReplays() *replays {
+	return &replays{running: make(map[int]struct{})}
+}
+
+// run sets the root context of the replays, blocks until it's cancelled and waits for the running replays.
+func (r *replays) run(ctx context.Context) {
+	r.mu.Lock()
+	r.ctx = ctx
+	r.mu.Unlock()
+
+	<-ctx.Done()
+
+	// Replays are started with the lock held, so none of them is added after the wait has begun.
+	r.mu.Lock()
+	r.mu.Unlock() //nolint:staticcheck // empty critical section waits for the start in progress
+
+	r.wg.Wait()
+}
+
+// start marks the replay of the channel as running and returns the root context for it,
+// it returns false when the replay of the channel is already running.
+func (r *replays) start(channelID int) (context.Context, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.ctx == nil || r.ctx.Err() != nil {
+		return nil, false, ErrReplaysStopped
+	}
+
+	if _, ok := r.running[channelID]; ok {
+		return nil, false, nil
+	}
+
+	r.running[channelID] = struct{}{}
+	r.wg.Add(1)
+
+	return r.ctx, true, nil
+}
+
+func (r *replays) finish(channelID int) {
+	r.mu.Lock()
+	delete(r.running, channelID)
+	r.mu.Unlock()
+
+	r.wg.Done()
+}
@@ -0,0 +1,32 @@
+package kafka_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/handler/queue/kafka"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+func Test_StartReplay(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{LogLevel: "info"}
+	queue := kafka.New(&service.Manager{}, cfg, logger.Get(cfg), prometheus.NewRegistry())
+
+	err := queue.StartReplay(context.Background(), 1)
+	assert.ErrorIs(t, err, kafka.ErrReplaysStopped, "replay started before RunReplays")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	queue.RunReplays(ctx)
+
+	err = queue.StartReplay(context.Background(), 1)
+	assert.ErrorIs(t, err, kafka.ErrReplaysStopped, "replay started after shutdown")
+}
@@ -0,0 +1,352 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/pkg/pb"
+)
+
+// Format is a wire format of the queue payloads.
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatProtobuf Format = "protobuf"
+)
+
+// ParseFormat converts a config value or a content type header into a wire format,
+// parameters of the content type like charset or messageType are ignored.
+func ParseFormat(value string) (Format, error) {
+	if strings.TrimSpace(value) == "" {
+		return FormatJSON, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q: %s", ErrUnsupportedFormat, value, err.Error())
+	}
+
+	switch mediaType {
+	case "json", "application/json":
+		return FormatJSON, nil
+	case "protobuf", "proto", "application/protobuf", "application/x-protobuf":
+		return FormatProtobuf, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, value)
+	}
+}
+
+const (
+	// CurrentSchemaVersion is the latest payload version produced by the scanner.
+	CurrentSchemaVersion = 1
+
+	// legacySchemaVersion is assigned to payloads that are sent without an envelope.
+	legacySchemaVersion = 1
+)
+
+const (
+	EventTypeChannel = "channel"
+	EventTypeMessage = "message"
+)
+
+var (
+	ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+	ErrUnexpectedEventType      = errors.New("unexpected event type")
+	ErrInvalidPayload           = errors.New("invalid payload")
+	ErrUnsupportedFormat        = errors.New("unsupported format")
+)
+
+// Envelope wraps every versioned payload sent by the scanner.
+type Envelope struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	EventType     string          `json:"eventType"`
+	ProducedAt    time.Time       `json:"producedAt"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+type channelValidator func(channel *model.DBChannel) error
+
+type messageValidator func(message *model.TgMessage) error
+
+var channelValidators = map[int]channelValidator{ //nolint:gochecknoglobals // registry of validation rules per schema version
+	1: validateChannelV1,
+}
+
+var messageValidators = map[int]messageValidator{ //nolint:gochecknoglobals // registry of validation rules per schema version
+	1: validateMessageV1,
+}
+
+var usernameRegexp = regexp.MustCompile(`^[A-Za-z0-9_]{1,64}$`)
+
+// DecodeChannel decodes channel data in the given wire format.
+// JSON data may be either a versioned envelope or the legacy unversioned payload.
+func DecodeChannel(data []byte, format Format) (*model.DBChannel, *Envelope, error) {
+	var (
+		channel  *model.DBChannel
+		envelope *Envelope
+		err      error
+	)
+
+	switch format {
+	case FormatJSON:
+		channel, envelope, err = decodeJSONChannel(data)
+	case FormatProtobuf:
+		channel, envelope, err = decodeProtoChannel(data)
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, envelope, err
+	}
+
+	validate, ok := channelValidators[envelope.SchemaVersion]
+	if !ok {
+		return nil, envelope, fmt.Errorf("%w: %d", ErrUnsupportedSchemaVersion, envelope.SchemaVersion)
+	}
+
+	if err := validate(channel); err != nil {
+		return nil, envelope, err
+	}
+
+	return channel, envelope, nil
+}
+
+// DecodeMessage decodes message data in the given wire format.
+// JSON data may be either a versioned envelope or the legacy unversioned payload.
+func DecodeMessage(data []byte, format Format) (*model.TgMessage, *Envelope, error) {
+	var (
+		message  *model.TgMessage
+		envelope *Envelope
+		err      error
+	)
+
+	switch format {
+	case FormatJSON:
+		message, envelope, err = decodeJSONMessage(data)
+	case FormatProtobuf:
+		message, envelope, err = decodeProtoMessage(data)
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, envelope, err
+	}
+
+	validate, ok := messageValidators[envelope.SchemaVersion]
+	if !ok {
+		return nil, envelope, fmt.Errorf("%w: %d", ErrUnsupportedSchemaVersion, envelope.SchemaVersion)
+	}
+
+	if err := validate(message); err != nil {
+		return nil, envelope, err
+	}
+
+	return message, envelope, nil
+}
+
+func decodeJSONChannel(data []byte) (*model.DBChannel, *Envelope, error) {
+	envelope, err := unwrap(data, EventTypeChannel)
+	if err != nil {
+		return nil, envelope, err
+	}
+
+	var channel model.DBChannel
+
+	if err := json.Unmarshal(envelope.Payload, &channel); err != nil {
+		return nil, envelope, fmt.Errorf("%w: unmarshal channel: %s", ErrInvalidPayload, err.Error())
+	}
+
+	return &channel, envelope, nil
+}
+
+func decodeJSONMessage(data []byte) (*model.TgMessage, *Envelope, error) {
+	envelope, err := unwrap(data, EventTypeMessage)
+	if err != nil {
+		return nil, envelope, err
+	}
+
+	var message model.TgMessage
+
+	if err := json.Unmarshal(envelope.Payload, &message); err != nil {
+		return nil, envelope, fmt.Errorf("%w: unmarshal message: %s", ErrInvalidPayload, err.Error())
+	}
+
+	return &message, envelope, nil
+}
+
+func decodeProtoChannel(data []byte) (*model.DBChannel, *Envelope, error) {
+	var event pb.ChannelEvent
+
+	if err := proto.Unmarshal(data, &event); err != nil {
+		return nil, nil, fmt.Errorf("%w: unmarshal channel event: %s", ErrInvalidPayload, err.Error())
+	}
+
+	envelope := protoEnvelope(event.GetSchemaVersion(), EventTypeChannel, event.GetProducedAt())
+
+	if event.GetChannel() == nil {
+		return nil, envelope, fmt.Errorf("%w: empty channel", ErrInvalidPayload)
+	}
+
+	return &model.DBChannel{
+		Name:     event.GetChannel().GetUsername(),
+		Title:    event.GetChannel().GetTitle(),
+		ImageURL: event.GetChannel().GetImageUrl(),
+	}, envelope, nil
+}
+
+func decodeProtoMessage(data []byte) (*model.TgMessage, *Envelope, error) {
+	var event pb.MessageEvent
+
+	if err := proto.Unmarshal(data, &event); err != nil {
+		return nil, nil, fmt.Errorf("%w: unmarshal message event: %s", ErrInvalidPayload, err.Error())
+	}
+
+	envelope := protoEnvelope(event.GetSchemaVersion(), EventTypeMessage, event.GetProducedAt())
+
+	msg := event.GetMessage()
+	if msg == nil {
+		return nil, envelope, fmt.Errorf("%w: empty message", ErrInvalidPayload)
+	}
+
+	message := &model.TgMessage{
+		Message:    msg.GetText(),
+		MessageURL: msg.GetMessageUrl(),
+		ImageURL:   msg.GetImageUrl(),
+		FromID:     protoUserToModel(msg.GetFrom()),
+		PeerID:     model.TgPeer{Username: msg.GetPeerUsername()},
+		Replies: model.TgReplies{
+			Count:    int(msg.GetRepliesCount()),
+			Messages: make([]model.TgReply, 0, len(msg.GetReplies())),
+		},
+	}
+
+	for _, reply := range msg.GetReplies() {
+		message.Replies.Messages = append(message.Replies.Messages, model.TgReply{
+			FromID:   protoUserToModel(reply.GetFrom()),
+			Message:  reply.GetText(),
+			ImageURL: reply.GetImageUrl(),
+		})
+	}
+
+	return message, envelope, nil
+}
+
+func protoEnvelope(version uint32, eventType string, producedAt *timestamppb.Timestamp) *Envelope {
+	envelope := &Envelope{
+		SchemaVersion: int(version),
+		EventType:     eventType,
+	}
+
+	if envelope.SchemaVersion == 0 {
+		envelope.SchemaVersion = legacySchemaVersion
+	}
+
+	if producedAt != nil {
+		envelope.ProducedAt = producedAt.AsTime()
+	}
+
+	return envelope
+}
+
+func protoUserToModel(user *pb.User) model.TgUser {
+	return model.TgUser{
+		ID:       user.GetId(),
+		Username: user.GetUsername(),
+		Fullname: user.GetFullname(),
+		ImageURL: user.GetImageUrl(),
+	}
+}
+
+func unwrap(data []byte, eventType string) (*Envelope, error) {
+	var envelope Envelope
+
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: unmarshal envelope: %s", ErrInvalidPayload, err.Error())
+	}
+
+	// Payloads without schema version are produced by old scanner versions.
+	if envelope.SchemaVersion == 0 {
+		return &Envelope{
+			SchemaVersion: legacySchemaVersion,
+			EventType:     eventType,
+			Payload:       bytes.TrimSpace(data),
+		}, nil
+	}
+
+	if envelope.EventType != eventType {
+		return &envelope, fmt.Errorf("%w: got %q, want %q", ErrUnexpectedEventType, envelope.EventType, eventType)
+	}
+
+	if len(envelope.Payload) == 0 {
+		return &envelope, fmt.Errorf("%w: empty payload", ErrInvalidPayload)
+	}
+
+	return &envelope, nil
+}
+
+func validateChannelV1(channel *model.DBChannel) error {
+	if !usernameRegexp.MatchString(channel.Name) {
+		return fmt.Errorf("%w: channel username %q is not valid", ErrInvalidPayload, channel.Name)
+	}
+
+	if channel.Title == "" {
+		return fmt.Errorf("%w: channel title is empty", ErrInvalidPayload)
+	}
+
+	if channel.ImageURL != "" && !isValidURL(channel.ImageURL) {
+		return fmt.Errorf("%w: channel image url %q is not valid", ErrInvalidPayload, channel.ImageURL)
+	}
+
+	return nil
+}
+
+func validateMessageV1(message *model.TgMessage) error {
+	if !usernameRegexp.MatchString(message.PeerID.Username) {
+		return fmt.Errorf("%w: peer username %q is not valid", ErrInvalidPayload, message.PeerID.Username)
+	}
+
+	if message.FromID.Username == "" {
+		return fmt.Errorf("%w: sender username is empty", ErrInvalidPayload)
+	}
+
+	if !isValidURL(message.MessageURL) {
+		return fmt.Errorf("%w: message url %q is not valid", ErrInvalidPayload, message.MessageURL)
+	}
+
+	if message.ImageURL != "" && !isValidURL(message.ImageURL) {
+		return fmt.Errorf("%w: message image url %q is not valid", ErrInvalidPayload, message.ImageURL)
+	}
+
+	for index, reply := range message.Replies.Messages {
+		if reply.FromID.Username == "" {
+			return fmt.Errorf("%w: sender username of reply %d is empty", ErrInvalidPayload, index)
+		}
+
+		if reply.ImageURL != "" && !isValidURL(reply.ImageURL) {
+			return fmt.Errorf("%w: image url of reply %d is not valid", ErrInvalidPayload, index)
+		}
+	}
+
+	return nil
+}
+
+func isValidURL(value string) bool {
+	u, err := url.ParseRequestURI(value)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,200 @@
+package kafka_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/VladPetriv/scanner_backend/internal/handler/queue/kafka"
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/pkg/pb"
+)
+
+func Test_DecodeChannel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		input         string
+		want          *model.DBChannel
+		expectedError error
+	}{
+		{
+			name:  "DecodeChannel successful with legacy payload",
+			input: `{"Username":"test","Title":"test channel","ImageURL":"https://test.com/test.jpg"}`,
+			want:  &model.DBChannel{Name: "test", Title: "test channel", ImageURL: "https://test.com/test.jpg"},
+		},
+		{
+			name: "DecodeChannel successful with versioned payload",
+			input: `{"schemaVersion":1,"eventType":"channel","producedAt":"2022-10-10T10:00:00Z",
+				"payload":{"Username":"test","Title":"test channel","ImageURL":""}}`,
+			want: &model.DBChannel{Name: "test", Title: "test channel"},
+		},
+		{
+			name:          "DecodeChannel failed with empty username",
+			input:         `{"Username":"","Title":"test channel"}`,
+			expectedError: kafka.ErrInvalidPayload,
+		},
+		{
+			name:          "DecodeChannel failed with invalid image url",
+			input:         `{"Username":"test","Title":"test channel","ImageURL":"not a url"}`,
+			expectedError: kafka.ErrInvalidPayload,
+		},
+		{
+			name:          "DecodeChannel failed with unsupported schema version",
+			input:         `{"schemaVersion":99,"eventType":"channel","payload":{"Username":"test","Title":"test"}}`,
+			expectedError: kafka.ErrUnsupportedSchemaVersion,
+		},
+		{
+			name:          "DecodeChannel failed with unexpected event type",
+			input:         `{"schemaVersion":1,"eventType":"message","payload":{"Username":"test","Title":"test"}}`,
+			expectedError: kafka.ErrUnexpectedEventType,
+		},
+		{
+			name:          "DecodeChannel failed with broken json",
+			input:         `{"Username":`,
+			expectedError: kafka.ErrInvalidPayload,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _, err := kafka.DecodeChannel([]byte(tt.input), kafka.FormatJSON)
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_DecodeMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		input         string
+		wantTitle     string
+		expectedError error
+	}{
+		{
+			name: "DecodeMessage successful with legacy payload",
+			input: `{"Message":"test","MessageURL":"https://t.me/test/1","FromID":{"Username":"user"},
+				"PeerID":{"Username":"test"},"Replies":{"Count":1,"Messages":[{"FromID":{"Username":"user2"},"Message":"reply"}]}}`,
+			wantTitle: "test",
+		},
+		{
+			name: "DecodeMessage successful with versioned payload",
+			input: `{"schemaVersion":1,"eventType":"message","payload":{"Message":"test",
+				"MessageURL":"https://t.me/test/1","FromID":{"Username":"user"},"PeerID":{"Username":"test"}}}`,
+			wantTitle: "test",
+		},
+		{
+			name:          "DecodeMessage failed with empty peer id",
+			input:         `{"Message":"test","MessageURL":"https://t.me/test/1","FromID":{"Username":"user"}}`,
+			expectedError: kafka.ErrInvalidPayload,
+		},
+		{
+			name: "DecodeMessage failed with invalid message url",
+			input: `{"Message":"test","MessageURL":"garbage","FromID":{"Username":"user"},
+				"PeerID":{"Username":"test"}}`,
+			expectedError: kafka.ErrInvalidPayload,
+		},
+		{
+			name: "DecodeMessage failed with reply without sender",
+			input: `{"Message":"test","MessageURL":"https://t.me/test/1","FromID":{"Username":"user"},
+				"PeerID":{"Username":"test"},"Replies":{"Count":1,"Messages":[{"Message":"reply"}]}}`,
+			expectedError: kafka.ErrInvalidPayload,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _, err := kafka.DecodeMessage([]byte(tt.input), kafka.FormatJSON)
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTitle, got.Message)
+			}
+		})
+	}
+}
+
+func Test_DecodeProtobuf(t *testing.T) {
+	t.Parallel()
+
+	producedAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	channelData, err := proto.Marshal(&pb.ChannelEvent{
+		SchemaVersion: 1,
+		ProducedAt:    timestamppb.New(producedAt),
+		Channel:       &pb.Channel{Username: "test", Title: "test channel", ImageUrl: "https://test.com/test.jpg"},
+	})
+	assert.NoError(t, err)
+
+	channel, envelope, err := kafka.DecodeChannel(channelData, kafka.FormatProtobuf)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.DBChannel{Name: "test", Title: "test channel", ImageURL: "https://test.com/test.jpg"}, channel)
+	assert.Equal(t, producedAt, envelope.ProducedAt)
+
+	messageData, err := proto.Marshal(&pb.MessageEvent{
+		Message: &pb.Message{
+			Text:         "test",
+			MessageUrl:   "https://t.me/test/1",
+			From:         &pb.User{Username: "user", Fullname: "test user"},
+			PeerUsername: "test",
+			RepliesCount: 1,
+			Replies:      []*pb.Reply{{From: &pb.User{Username: "user2"}, Text: "reply"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	message, envelope, err := kafka.DecodeMessage(messageData, kafka.FormatProtobuf)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, envelope.SchemaVersion)
+	assert.Equal(t, "test", message.PeerID.Username)
+	assert.Equal(t, "test user", message.FromID.Fullname)
+	assert.Equal(t, []model.TgReply{{FromID: model.TgUser{Username: "user2"}, Message: "reply"}}, message.Replies.Messages)
+
+	_, _, err = kafka.DecodeMessage([]byte("garbage"), kafka.FormatProtobuf)
+	assert.True(t, errors.Is(err, kafka.ErrInvalidPayload), err)
+}
+
+func Test_ParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input         string
+		want          kafka.Format
+		expectedError error
+	}{
+		{input: "", want: kafka.FormatJSON},
+		{input: "application/json", want: kafka.FormatJSON},
+		{input: "protobuf", want: kafka.FormatProtobuf},
+		{input: "application/x-protobuf", want: kafka.FormatProtobuf},
+		{input: "Application/JSON; charset=utf-8", want: kafka.FormatJSON},
+		{input: "application/x-protobuf; messageType=scanner.Message", want: kafka.FormatProtobuf},
+		{input: "xml", expectedError: kafka.ErrUnsupportedFormat},
+		{input: "application/json; charset", expectedError: kafka.ErrUnsupportedFormat},
+	}
+	for _, tt := range tests {
+		got, err := kafka.ParseFormat(tt.input)
+		if tt.expectedError != nil {
+			assert.True(t, errors.Is(err, tt.expectedError), err)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		}
+	}
+}
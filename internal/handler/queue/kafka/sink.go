@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+// RejectedRecord describes a queue record which failed decoding or validation.
+// Value is kept as raw bytes, so binary payloads are base64 encoded in JSON and can be recovered.
+type RejectedRecord struct {
+	Topic       string    `json:"topic"`
+	Partition   int32     `json:"partition"`
+	Offset      int64     `json:"offset"`
+	Reason      string    `json:"reason"`
+	ContentType string    `json:"contentType"`
+	Value       []byte    `json:"value"`
+	RejectedAt  time.Time `json:"rejectedAt"`
+}
+
+// ErrorSink receives records rejected by the consumers.
+type ErrorSink interface {
+	Send(record *RejectedRecord) error
+}
+
+type logSink struct {
+	log *logger.Logger
+}
+
+func newLogSink(log *logger.Logger) ErrorSink {
+	return logSink{log: log}
+}
+
+func (s logSink) Send(record *RejectedRecord) error {
+	s.log.Warn().
+		Str("topic", record.Topic).
+		Int32("partition", record.Partition).
+		Int64("offset", record.Offset).
+		Str("reason", record.Reason).
+		Str("content type", record.ContentType).
+		Msg("record rejected")
+
+	return nil
+}
+
+type topicSink struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+func newTopicSink(addr string, topic string) (ErrorSink, error) {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer([]string{addr}, config)
+	if err != nil {
+		return nil, fmt.Errorf("create producer: %w", err)
+	}
+
+	return topicSink{producer: producer, topic: topic}, nil
+}
+
+func (s topicSink) Send(record *RejectedRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal rejected record: %w", err)
+	}
+
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: s.topic,
+		Key:   sarama.StringEncoder(record.Topic),
+		Value: sarama.ByteEncoder(data),
+	})
+	if err != nil {
+		return fmt.Errorf("send rejected record: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+// consumerStatuses keeps state of every consumer, it's shared between copies of the queue.
+type consumerStatuses struct {
+	mu       sync.RWMutex
+	statuses map[string]*model.ConsumerStatus
+}
+
+func newConsumerStatuses(topics ...string) *consumerStatuses {
+	s := &consumerStatuses{statuses: make(map[string]*model.ConsumerStatus, len(topics))}
+
+	for _, topic := range topics {
+		s.statuses[topic] = &model.ConsumerStatus{Topic: topic, State: model.ConsumerStateStarting}
+	}
+
+	return s
+}
+
+func (s *consumerStatuses) setState(topic string, state string, reason error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.get(topic)
+	status.State = state
+
+	if reason != nil {
+		status.LastError = reason.Error()
+	}
+}
+
+func (s *consumerStatuses) setIngested(topic string, ingestedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.get(topic).LastIngestedAt = &ingestedAt
+}
+
+// get returns status of the topic, it must be called with the lock held.
+func (s *consumerStatuses) get(topic string) *model.ConsumerStatus {
+	status, ok := s.statuses[topic]
+	if !ok {
+		status = &model.ConsumerStatus{Topic: topic, State: model.ConsumerStateStarting}
+		s.statuses[topic] = status
+	}
+
+	return status
+}
+
+// list returns copies of the statuses ordered by topic.
+func (s *consumerStatuses) list() []model.ConsumerStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]model.ConsumerStatus, 0, len(s.statuses))
+
+	for _, status := range s.statuses {
+		result = append(result, *status)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Topic < result[j].Topic
+	})
+
+	return result
+}
@@ -12,7 +12,11 @@ type Channel struct {
 	Name     string `json:"name" db:"name"`
 	Title    string `json:"title" db:"title"`
 	ImageURL string `json:"imageUrl" db:"image_url"`
-	Stats    Stat
+	// Enabled is false when new messages of the channel must not be ingested.
+	Enabled bool `json:"enabled" db:"enabled"`
+	// Hidden channels and their messages are excluded from lists and feeds, but still ingested while enabled.
+	Hidden bool `json:"hidden" db:"hidden"`
+	Stats  Stat
 }
 
 type Stat struct {
@@ -0,0 +1,29 @@
+package model
+
+// Rules of the message filter, they are used as a reason of filtering and as a metric label.
+const (
+	FilterRuleMinLength     = "min_length"
+	FilterRuleBlockedPhrase = "blocked_phrase"
+	FilterRuleBlockedLink   = "blocked_link"
+	FilterRulePattern       = "pattern"
+)
+
+// FilterRules configures the filter of ingested messages, rules of the channel extend the global ones.
+type FilterRules struct {
+	FilterRuleSet
+	// Channels are keyed by the channel name.
+	Channels map[string]FilterRuleSet `json:"channels"`
+}
+
+type FilterRuleSet struct {
+	// Disabled turns the filter off, it's used to exclude a channel.
+	Disabled bool `json:"disabled"`
+	// MinLength is a minimal length of the text of the message without an image,
+	// channel keeps the global one when it's unset.
+	MinLength      *int     `json:"minLength"`
+	BlockedPhrases []string `json:"blockedPhrases"`
+	// BlockedLinks are domains, links to their subdomains are blocked too.
+	BlockedLinks []string `json:"blockedLinks"`
+	// Patterns are regular expressions matched against the text of the message.
+	Patterns []string `json:"patterns"`
+}
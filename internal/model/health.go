@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type MigrationVersion struct {
+	Version uint `json:"version" db:"version"`
+	Dirty   bool `json:"dirty" db:"dirty"`
+}
+
+const (
+	ConsumerStateStarting     = "starting"
+	ConsumerStateRunning      = "running"
+	ConsumerStateDisconnected = "disconnected"
+	ConsumerStateStopped      = "stopped"
+)
+
+type ConsumerStatus struct {
+	Topic          string     `json:"topic"`
+	State          string     `json:"state"`
+	LastError      string     `json:"lastError,omitempty"`
+	LastIngestedAt *time.Time `json:"lastIngestedAt,omitempty"`
+}
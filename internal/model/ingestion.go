@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// IngestionError is a queue record which was rejected or failed to be saved, it's kept to be reprocessed by an admin.
+type IngestionError struct {
+	ID          int       `json:"id" db:"id"`
+	Topic       string    `json:"topic" db:"topic"`
+	Partition   int32     `json:"partition" db:"partition"`
+	Offset      int64     `json:"offset" db:"record_offset"`
+	ContentType string    `json:"contentType" db:"content_type"`
+	Reason      string    `json:"reason" db:"reason"`
+	Value       []byte    `json:"-" db:"value"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+// BufferedRecord is a message record of the disabled channel, it's replayed when the channel is enabled again.
+type BufferedRecord struct {
+	ID          int       `json:"id" db:"id"`
+	ChannelID   int       `json:"channelId" db:"channel_id"`
+	Topic       string    `json:"topic" db:"topic"`
+	Partition   int32     `json:"partition" db:"partition"`
+	Offset      int64     `json:"offset" db:"record_offset"`
+	ContentType string    `json:"contentType" db:"content_type"`
+	Value       []byte    `json:"-" db:"value"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+}
+
+// QuarantinedMessage is a message record caught by the filter, it's kept until an admin releases or dismisses it.
+type QuarantinedMessage struct {
+	ID          int       `json:"id" db:"id"`
+	ChannelID   int       `json:"channelId" db:"channel_id"`
+	Title       string    `json:"title" db:"title"`
+	Rule        string    `json:"rule" db:"rule"`
+	Reason      string    `json:"reason" db:"reason"`
+	Topic       string    `json:"topic" db:"topic"`
+	Partition   int32     `json:"partition" db:"partition"`
+	Offset      int64     `json:"offset" db:"record_offset"`
+	ContentType string    `json:"contentType" db:"content_type"`
+	Value       []byte    `json:"-" db:"value"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	// ChannelName is loaded for the review page only.
+	ChannelName string `json:"channelName" db:"channel_name"`
+}
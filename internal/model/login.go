@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// LoginFailure is a failed attempt to log in, it's recorded for both existing and unknown emails.
+// Time is set by the service in UTC, so it's compared with the same clock which counts lockouts.
+type LoginFailure struct {
+	ID        int       `json:"id" db:"id"`
+	Email     string    `json:"email" db:"email"`
+	IPAddress string    `json:"ipAddress" db:"ip_address"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// LoginFailureStats describes failed attempts to log in by the same email or from the same address.
+type LoginFailureStats struct {
+	Count  int       `db:"count"`
+	LastAt time.Time `db:"last_at"`
+}
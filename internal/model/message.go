@@ -1,54 +1,83 @@
 package model
 
 type TgMessage struct {
-	Message    string `json:"Message"`
-	MessageURL string `json:"MessageURL"`
-	ImageURL   string `json:"ImageURL"`
-	FromID     struct {
-		Username string `json:"Username"`
-		ImageURL string `json:"ImageURL"`
-		Fullname string `json:"Fullname"`
-	} `json:"FromID"`
-	PeerID struct {
-		Username string `json:"Username"`
-	} `json:"PeerID"`
-	Replies struct {
-		Count    int `json:"Count"`
-		Messages []struct {
-			FromID struct {
-				Username string `json:"Username"`
-				Fullname string `json:"Fullname"`
-				ImageURL string `json:"ImageURL"`
-			} `json:"FromID"`
-			Message  string `json:"Message"`
-			ImageURL string `json:"ImageURL"`
-		} `json:"Messages"`
-	} `json:"Replies"`
+	Message    string    `json:"Message"`
+	MessageURL string    `json:"MessageURL"`
+	ImageURL   string    `json:"ImageURL"`
+	FromID     TgUser    `json:"FromID"`
+	PeerID     TgPeer    `json:"PeerID"`
+	Replies    TgReplies `json:"Replies"`
+}
+
+type TgUser struct {
+	ID       int64  `json:"ID"`
+	Username string `json:"Username"`
+	ImageURL string `json:"ImageURL"`
+	Fullname string `json:"Fullname"`
+}
+
+type TgPeer struct {
+	Username string `json:"Username"`
+}
+
+type TgReplies struct {
+	Count    int       `json:"Count"`
+	Messages []TgReply `json:"Messages"`
+}
+
+type TgReply struct {
+	FromID   TgUser `json:"FromID"`
+	Message  string `json:"Message"`
+	ImageURL string `json:"ImageURL"`
+}
+
+// MessageKind is a class of the message which is detected on ingestion.
+type MessageKind string
+
+const (
+	MessageKindQuestion     MessageKind = "question"
+	MessageKindAnnouncement MessageKind = "announcement"
+	MessageKindOther        MessageKind = "other"
+)
+
+// Valid reports whether the kind is one of the known kinds.
+func (k MessageKind) Valid() bool {
+	switch k {
+	case MessageKindQuestion, MessageKindAnnouncement, MessageKindOther:
+		return true
+	default:
+		return false
+	}
 }
 
 type DBMessage struct {
-	ID         int    `db:"id"`
-	ChannelID  int    `db:"channel_id"`
-	UserID     int    `db:"user_id"`
-	Title      string `db:"title"`
-	MessageURL string `db:"message_url"`
-	ImageURL   string `db:"image_url"`
+	ID         int         `db:"id"`
+	ChannelID  int         `db:"channel_id"`
+	UserID     int         `db:"user_id"`
+	Title      string      `db:"title"`
+	MessageURL string      `db:"message_url"`
+	ImageURL   string      `db:"image_url"`
+	Hidden     bool        `db:"hidden"`
+	Kind       MessageKind `db:"kind"`
 }
 
 type FullMessage struct {
-	ID              int    `db:"id"`
-	MessageURL      string `db:"message_url"`
-	Title           string `db:"title"`
-	ImageURL        string `db:"image_url"`
-	ChannelID       int    `db:"channel_id"`
-	ChannelName     string `db:"channel_name"`
-	ChannelTitle    string `db:"channel_title"`
-	ChannelImageURL string `db:"channel_image_url"`
-	UserID          int    `db:"user_id"`
-	FullName        string `db:"fullname"`
-	UserImageURL    string `db:"user_image_url"`
-	RepliesCount    int    `db:"count"`
-	Replies         []FullReply
-	SavedID         int
-	Status          bool
+	ID              int         `json:"id" db:"id"`
+	MessageURL      string      `json:"messageUrl" db:"message_url"`
+	Title           string      `json:"title" db:"title"`
+	ImageURL        string      `json:"imageUrl" db:"image_url"`
+	ChannelID       int         `json:"channelId" db:"channel_id"`
+	ChannelName     string      `json:"channelName" db:"channel_name"`
+	ChannelTitle    string      `json:"channelTitle" db:"channel_title"`
+	ChannelImageURL string      `json:"channelImageUrl" db:"channel_image_url"`
+	UserID          int         `json:"userId" db:"user_id"`
+	FullName        string      `json:"fullname" db:"fullname"`
+	UserImageURL    string      `json:"userImageUrl" db:"user_image_url"`
+	RepliesCount    int         `json:"repliesCount" db:"count"`
+	Replies         []FullReply `json:"replies,omitempty"`
+	SavedID         int         `json:"savedId,omitempty"`
+	Status          bool        `json:"-"`
+	// Hidden messages are removed by moderators, they're excluded from feeds and pages.
+	Hidden bool        `json:"-" db:"hidden"`
+	Kind   MessageKind `json:"kind" db:"kind"`
 }
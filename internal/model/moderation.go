@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// ModerationTarget is a kind of the content which moderators hide.
+type ModerationTarget string
+
+const (
+	ModerationTargetMessage ModerationTarget = "message"
+	ModerationTargetReply   ModerationTarget = "reply"
+)
+
+// ModerationActionType is an action of the moderator kept in the audit trail.
+type ModerationActionType string
+
+const (
+	ModerationActionHide   ModerationActionType = "hide"
+	ModerationActionUnhide ModerationActionType = "unhide"
+	// ModerationActionDismiss resolves reports of the message without hiding it.
+	ModerationActionDismiss ModerationActionType = "dismiss"
+)
+
+// Report is a complaint of the web user about the message, it's open until a moderator resolves it.
+type Report struct {
+	ID         int        `json:"id" db:"id"`
+	WebUserID  int        `json:"webUserId" db:"web_user_id"`
+	MessageID  int        `json:"messageId" db:"message_id"`
+	Reason     string     `json:"reason" db:"reason"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	ResolvedAt *time.Time `json:"resolvedAt" db:"resolved_at"`
+}
+
+// ReportedMessage is an open report with the reported message, it's shown in the moderation queue.
+type ReportedMessage struct {
+	Report
+	MessageTitle  string `json:"messageTitle" db:"message_title"`
+	MessageHidden bool   `json:"messageHidden" db:"message_hidden"`
+	ChannelName   string `json:"channelName" db:"channel_name"`
+	ReporterEmail string `json:"reporterEmail" db:"reporter_email"`
+}
+
+// ModerationAction is a record of the audit trail, moderator is unset when the moderator account is deleted.
+type ModerationAction struct {
+	ID          int                  `json:"id" db:"id"`
+	ModeratorID *int                 `json:"moderatorId" db:"moderator_id"`
+	TargetType  ModerationTarget     `json:"targetType" db:"target_type"`
+	TargetID    int                  `json:"targetId" db:"target_id"`
+	Action      ModerationActionType `json:"action" db:"action"`
+	CreatedAt   time.Time            `json:"createdAt" db:"created_at"`
+	// ModeratorEmail is loaded for the audit trail only.
+	ModeratorEmail string `json:"moderatorEmail" db:"moderator_email"`
+}
@@ -6,6 +6,7 @@ type DBReply struct {
 	UserID    int    `db:"user_id"`
 	Title     string `db:"title"`
 	ImageURL  string `db:"image_url"`
+	Hidden    bool   `db:"hidden"`
 }
 
 type FullReply struct {
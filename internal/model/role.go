@@ -0,0 +1,54 @@
+package model
+
+// Role of the web user defines what the user is permitted to do, new users are members.
+type Role string
+
+const (
+	RoleAdmin     Role = "admin"
+	RoleModerator Role = "moderator"
+	RoleMember    Role = "member"
+)
+
+// Permission is an action restricted to some of the roles.
+type Permission string
+
+const (
+	// PermissionModerate allows to review reports and hide messages.
+	PermissionModerate Permission = "moderate"
+	// PermissionManageChannels allows to disable and hide channels.
+	PermissionManageChannels Permission = "manage_channels"
+	// PermissionManageUsers allows to change roles and ban users.
+	PermissionManageUsers Permission = "manage_users"
+	// PermissionViewAdmin allows to open the admin dashboard.
+	PermissionViewAdmin Permission = "view_admin"
+	// PermissionManageIngestion allows to reprocess and dismiss records which failed ingestion.
+	PermissionManageIngestion Permission = "manage_ingestion"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermissionModerate, PermissionManageChannels, PermissionManageUsers, PermissionViewAdmin,
+		PermissionManageIngestion,
+	},
+	RoleModerator: {PermissionModerate},
+}
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleModerator, RoleMember:
+		return true
+	default:
+		return false
+	}
+}
+
+// Can reports whether the role has the permission.
+func (r Role) Can(permission Permission) bool {
+	for _, rolePermission := range rolePermissions[r] {
+		if rolePermission == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Session is a web user session, its id is a hash of the token stored in the cookie.
+type Session struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    int       `json:"userId" db:"user_id"`
+	UserAgent string    `json:"userAgent" db:"user_agent"`
+	IPAddress string    `json:"ipAddress" db:"ip_address"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	ExpiresAt time.Time `json:"expiresAt" db:"expires_at"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Scopes of the personal API tokens.
+const (
+	// APITokenScopeRead allows to read channels, messages and saved messages.
+	APITokenScopeRead = "read"
+	// APITokenScopeWrite allows to save messages.
+	APITokenScopeWrite = "write"
+)
+
+// APIToken is a personal access token of the web user for scripts, only hash of the token is stored.
+// Scopes are separated by spaces, token without expiration time is valid until it's revoked.
+type APIToken struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"userId" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	Scopes     string     `json:"scopes" db:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
+	ExpiresAt  *time.Time `json:"expiresAt" db:"expires_at"`
+	LastUsedAt *time.Time `json:"lastUsedAt" db:"last_used_at"`
+}
+
+func (t APIToken) HasScope(scope string) bool {
+	for _, tokenScope := range strings.Fields(t.Scopes) {
+		if tokenScope == scope {
+			return true
+		}
+	}
+
+	return false
+}
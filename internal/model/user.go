@@ -1,14 +1,39 @@
 package model
 
+import "time"
+
 type User struct {
 	ID       int    `json:"id" db:"id"`
+	TgID     *int64 `json:"tgId,omitempty" db:"tg_id"`
 	Username string `json:"username" db:"username"`
 	FullName string `json:"fullname" db:"fullname"`
 	ImageURL string `json:"imageURL" db:"image_url"`
 }
 
+type UserHistory struct {
+	ID        int       `json:"id" db:"id"`
+	UserID    int       `json:"userId" db:"user_id"`
+	Username  string    `json:"username" db:"username"`
+	FullName  string    `json:"fullname" db:"fullname"`
+	ImageURL  string    `json:"imageURL" db:"image_url"`
+	ChangedAt time.Time `json:"changedAt" db:"changed_at"`
+}
+
 type WebUser struct {
-	ID       int    `json:"id" db:"id"`
-	Email    string `json:"email" db:"email"`
-	Password string `json:"password" db:"password"`
+	ID            int    `json:"id" db:"id"`
+	Email         string `json:"email" db:"email"`
+	Password      string `json:"password" db:"password"`
+	EmailVerified bool   `json:"emailVerified" db:"email_verified"`
+	// TOTPSecret is set on enrollment, two-factor authentication is required only after it's confirmed.
+	TOTPSecret  string `json:"-" db:"totp_secret"`
+	TOTPEnabled bool   `json:"totpEnabled" db:"totp_enabled"`
+	// TOTPLastCounter is the time step of the last accepted code, codes of it and earlier steps are rejected.
+	TOTPLastCounter int64 `json:"-" db:"totp_last_counter"`
+	Role            Role  `json:"role" db:"role"`
+	// BannedAt is set when the user is banned by an admin, banned users can't log in.
+	BannedAt *time.Time `json:"bannedAt,omitempty" db:"banned_at"`
+}
+
+func (u WebUser) Banned() bool {
+	return u.BannedAt != nil
 }
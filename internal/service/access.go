@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/password"
+)
+
+type accessService struct {
+	store  *store.Store
+	logger *logger.Logger
+}
+
+var _ AccessService = (*accessService)(nil)
+
+func NewAccessService(store *store.Store, logger *logger.Logger) *accessService {
+	return &accessService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Authorize returns ErrPermissionDenied when the user is anonymous or the role of the user doesn't have the permission.
+func (s accessService) Authorize(ctx context.Context, user *model.WebUser, permission model.Permission) error {
+	logger := s.logger.ForContext(ctx)
+
+	if user == nil || !user.Role.Can(permission) {
+		event := logger.Info().Str("permission", string(permission))
+		if user != nil {
+			event = event.Int("user id", user.ID).Str("role", string(user.Role))
+		}
+
+		event.Msg("permission denied")
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// SetWebUserRole changes role of another user, users can't change their own role,
+// so the last admin can't lock everybody out of the administration.
+func (s accessService) SetWebUserRole(ctx context.Context, actor *model.WebUser, userID int, role model.Role) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.Authorize(ctx, actor, model.PermissionManageUsers)
+	if err != nil {
+		return err
+	}
+
+	if !role.Valid() {
+		logger.Info().Str("role", string(role)).Msg("invalid role")
+		return ErrInvalidRole
+	}
+
+	if actor.ID == userID {
+		logger.Info().Int("user id", userID).Msg("own role can't be changed")
+		return ErrOwnRoleChange
+	}
+
+	user, err := s.store.WebUser.GetWebUserByID(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("get web user by id")
+		return fmt.Errorf("get web user by id from db: %w", err)
+	}
+	if user == nil {
+		logger.Info().Int("user id", userID).Msg("web user by id not found")
+		return ErrWebUserNotFound
+	}
+
+	err = s.store.WebUser.UpdateWebUserRole(ctx, userID, role)
+	if err != nil {
+		logger.Error().Err(err).Msg("update web user role")
+		return fmt.Errorf("update web user role in db: %w", err)
+	}
+
+	logger.Info().Int("actor id", actor.ID).Int("user id", userID).Str("role", string(role)).
+		Msg("web user role successfully changed")
+	return nil
+}
+
+// BootstrapAdmin makes the first admin, existing user is promoted and new one is created with the password.
+// It works only while there are no admins, later roles are changed by admins.
+func (s accessService) BootstrapAdmin(ctx context.Context, email string, userPassword string) error {
+	logger := s.logger.ForContext(ctx)
+
+	adminsCount, err := s.store.WebUser.CountWebUsersByRole(ctx, model.RoleAdmin)
+	if err != nil {
+		logger.Error().Err(err).Msg("count admins")
+		return fmt.Errorf("count web users by role in db: %w", err)
+	}
+	if adminsCount > 0 {
+		logger.Info().Int("admins count", adminsCount).Msg("admin already exists")
+		return ErrAdminExists
+	}
+
+	user, err := s.getOrCreateWebUser(ctx, email, userPassword)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.WebUser.UpdateWebUserRole(ctx, user.ID, model.RoleAdmin)
+	if err != nil {
+		logger.Error().Err(err).Msg("update web user role")
+		return fmt.Errorf("update web user role in db: %w", err)
+	}
+
+	// Email of the admin is trusted since the admin is created by the operator of the server.
+	_, err = s.store.WebUser.VerifyWebUserEmail(ctx, user.ID, user.Email)
+	if err != nil {
+		logger.Error().Err(err).Msg("verify web user email")
+		return fmt.Errorf("verify web user email in db: %w", err)
+	}
+
+	logger.Info().Int("user id", user.ID).Msg("admin successfully bootstrapped")
+	return nil
+}
+
+func (s accessService) getOrCreateWebUser(
+	ctx context.Context, email string, userPassword string,
+) (*model.WebUser, error) {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.store.WebUser.GetWebUserByEmail(ctx, email)
+	if err != nil {
+		logger.Error().Err(err).Msg("get web user by email")
+		return nil, fmt.Errorf("get web user by email from db: %w", err)
+	}
+	if user != nil {
+		return user, nil
+	}
+
+	if !validEmail(email) {
+		logger.Info().Str("email", email).Msg("invalid email")
+		return nil, ErrInvalidEmail
+	}
+
+	if len(userPassword) < minPasswordLength {
+		logger.Info().Msg("password is too short")
+		return nil, ErrPasswordTooShort
+	}
+
+	hashedPassword, err := password.HashPassword(userPassword)
+	if err != nil {
+		logger.Error().Err(err).Msg("hash user password")
+		return nil, fmt.Errorf("hash user password: %w", err)
+	}
+
+	err = s.store.WebUser.CreateWebUser(ctx, &model.WebUser{Email: email, Password: hashedPassword})
+	if err != nil {
+		logger.Error().Err(err).Msg("create web user")
+		return nil, fmt.Errorf("create web user in db: %w", err)
+	}
+
+	user, err = s.store.WebUser.GetWebUserByEmail(ctx, email)
+	if err != nil {
+		logger.Error().Err(err).Msg("get web user by email")
+		return nil, fmt.Errorf("get web user by email from db: %w", err)
+	}
+	if user == nil {
+		return nil, ErrWebUserNotFound
+	}
+
+	return user, nil
+}
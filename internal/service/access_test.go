@@ -0,0 +1,250 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccessService_Authorize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		user          *model.WebUser
+		permission    model.Permission
+		expectedError error
+	}{
+		{
+			name:       "Authorize successful for admin",
+			user:       &model.WebUser{ID: 1, Role: model.RoleAdmin},
+			permission: model.PermissionManageUsers,
+		},
+		{
+			name:       "Authorize successful for moderator",
+			user:       &model.WebUser{ID: 1, Role: model.RoleModerator},
+			permission: model.PermissionModerate,
+		},
+		{
+			name:          "Authorize failed for moderator without permission",
+			user:          &model.WebUser{ID: 1, Role: model.RoleModerator},
+			permission:    model.PermissionManageChannels,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name:          "Authorize failed for member",
+			user:          &model.WebUser{ID: 1, Role: model.RoleMember},
+			permission:    model.PermissionModerate,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name:          "Authorize failed for anonymous user",
+			permission:    model.PermissionViewAdmin,
+			expectedError: service.ErrPermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			accessService := service.NewAccessService(&store.Store{}, logger)
+
+			err := accessService.Authorize(context.Background(), tt.user, tt.permission)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestAccessService_SetWebUserRole(t *testing.T) {
+	t.Parallel()
+
+	admin := &model.WebUser{ID: 1, Role: model.RoleAdmin}
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo)
+		actor         *model.WebUser
+		userID        int
+		role          model.Role
+		expectedError error
+	}{
+		{
+			name: "SetWebUserRole successful",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 2).Return(&model.WebUser{ID: 2}, nil)
+				webUserRepo.On("UpdateWebUserRole", mock.Anything, 2, model.RoleModerator).Return(nil)
+			},
+			actor:  admin,
+			userID: 2,
+			role:   model.RoleModerator,
+		},
+		{
+			name:          "SetWebUserRole failed with not permitted actor",
+			mock:          func(webUserRepo *mocks.WebUserRepo) {},
+			actor:         &model.WebUser{ID: 1, Role: model.RoleModerator},
+			userID:        2,
+			role:          model.RoleModerator,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name:          "SetWebUserRole failed with invalid role",
+			mock:          func(webUserRepo *mocks.WebUserRepo) {},
+			actor:         admin,
+			userID:        2,
+			role:          "owner",
+			expectedError: service.ErrInvalidRole,
+		},
+		{
+			name:          "SetWebUserRole failed with own role",
+			mock:          func(webUserRepo *mocks.WebUserRepo) {},
+			actor:         admin,
+			userID:        1,
+			role:          model.RoleMember,
+			expectedError: service.ErrOwnRoleChange,
+		},
+		{
+			name: "SetWebUserRole failed with not found user",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 2).Return(nil, nil)
+			},
+			actor:         admin,
+			userID:        2,
+			role:          model.RoleModerator,
+			expectedError: service.ErrWebUserNotFound,
+		},
+		{
+			name: "SetWebUserRole failed with some store error when update role",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 2).Return(&model.WebUser{ID: 2}, nil)
+				webUserRepo.On("UpdateWebUserRole", mock.Anything, 2, model.RoleModerator).
+					Return(fmt.Errorf("some store error"))
+			},
+			actor:         admin,
+			userID:        2,
+			role:          model.RoleModerator,
+			expectedError: fmt.Errorf("update web user role in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			accessService := service.NewAccessService(&store.Store{WebUser: webUserRepo}, logger)
+			tt.mock(webUserRepo)
+
+			err := accessService.SetWebUserRole(context.Background(), tt.actor, tt.userID, tt.role)
+			assert.Equal(t, tt.expectedError, err)
+
+			webUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessService_BootstrapAdmin(t *testing.T) {
+	t.Parallel()
+
+	user := &model.WebUser{ID: 1, Email: "test@test.com"}
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo)
+		email         string
+		password      string
+		expectedError error
+	}{
+		{
+			name: "BootstrapAdmin successful with existed user",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("CountWebUsersByRole", mock.Anything, model.RoleAdmin).Return(0, nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(user, nil)
+				webUserRepo.On("UpdateWebUserRole", mock.Anything, 1, model.RoleAdmin).Return(nil)
+				webUserRepo.On("VerifyWebUserEmail", mock.Anything, 1, "test@test.com").Return(true, nil)
+			},
+			email: "test@test.com",
+		},
+		{
+			name: "BootstrapAdmin successful with new user",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("CountWebUsersByRole", mock.Anything, model.RoleAdmin).Return(0, nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, nil).Once()
+				webUserRepo.On("CreateWebUser", mock.Anything, mock.MatchedBy(func(u *model.WebUser) bool {
+					return u.Email == "test@test.com" && u.Password != "password"
+				})).Return(nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(user, nil).Once()
+				webUserRepo.On("UpdateWebUserRole", mock.Anything, 1, model.RoleAdmin).Return(nil)
+				webUserRepo.On("VerifyWebUserEmail", mock.Anything, 1, "test@test.com").Return(true, nil)
+			},
+			email:    "test@test.com",
+			password: "password",
+		},
+		{
+			name: "BootstrapAdmin failed with existed admin",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("CountWebUsersByRole", mock.Anything, model.RoleAdmin).Return(1, nil)
+			},
+			email:         "test@test.com",
+			expectedError: service.ErrAdminExists,
+		},
+		{
+			name: "BootstrapAdmin failed with short password of new user",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("CountWebUsersByRole", mock.Anything, model.RoleAdmin).Return(0, nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, nil)
+			},
+			email:         "test@test.com",
+			password:      "test",
+			expectedError: service.ErrPasswordTooShort,
+		},
+		{
+			name: "BootstrapAdmin failed with invalid email of new user",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("CountWebUsersByRole", mock.Anything, model.RoleAdmin).Return(0, nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test").Return(nil, nil)
+			},
+			email:         "test",
+			password:      "password",
+			expectedError: service.ErrInvalidEmail,
+		},
+		{
+			name: "BootstrapAdmin failed with some store error when count admins",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("CountWebUsersByRole", mock.Anything, model.RoleAdmin).
+					Return(0, fmt.Errorf("some store error"))
+			},
+			email:         "test@test.com",
+			expectedError: fmt.Errorf("count web users by role in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			accessService := service.NewAccessService(&store.Store{WebUser: webUserRepo}, logger)
+			tt.mock(webUserRepo)
+
+			err := accessService.BootstrapAdmin(context.Background(), tt.email, tt.password)
+			assert.Equal(t, tt.expectedError, err)
+
+			webUserRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/convert"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+// dashboardIngestionErrors is a number of the latest ingestion errors shown on the dashboard.
+const dashboardIngestionErrors = 20
+
+type adminService struct {
+	store     *store.Store
+	logger    *logger.Logger
+	access    AccessService
+	channel   ChannelService
+	ingestion IngestionService
+}
+
+var _ AdminService = (*adminService)(nil)
+
+func NewAdminService(
+	store *store.Store, logger *logger.Logger, access AccessService, channel ChannelService, ingestion IngestionService,
+) *adminService {
+	return &adminService{
+		store:     store,
+		logger:    logger,
+		access:    access,
+		channel:   channel,
+		ingestion: ingestion,
+	}
+}
+
+// GetDashboard returns all channels, including hidden ones, with their statistics and the latest ingestion errors.
+func (s adminService) GetDashboard(ctx context.Context, actor *model.WebUser) (*AdminDashboard, error) {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionViewAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &AdminDashboard{}
+
+	// Hidden channels are excluded from the channel service, but they still should be managed here.
+	channels, err := s.store.Channel.GetAllChannels(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("get all channels")
+		return nil, fmt.Errorf("get all channels from db: %w", err)
+	}
+
+	for index, channel := range channels {
+		stat, err := s.channel.GetChannelStats(ctx, channel.ID)
+		if err != nil {
+			logger.Error().Err(err).Msg("get channel stats")
+
+			continue
+		}
+
+		channels[index].Stats = *stat
+	}
+
+	dashboard.Channels = channels
+
+	ingestionErrors, err := s.ingestion.GetIngestionErrors(ctx, dashboardIngestionErrors)
+	if err != nil && !errors.Is(err, ErrIngestionErrorsNotFound) {
+		logger.Error().Err(err).Msg("get ingestion errors")
+		return nil, fmt.Errorf("[GetDashboard]: %w", err)
+	}
+
+	dashboard.IngestionErrors = ingestionErrors
+
+	return dashboard, nil
+}
+
+func (s adminService) GetWebUsersByPage(ctx context.Context, actor *model.WebUser, page int) ([]model.WebUser, error) {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionManageUsers)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.store.WebUser.GetWebUsersByPage(ctx, convert.PageToOffset(page))
+	if err != nil {
+		logger.Error().Err(err).Msg("get web users by page")
+		return nil, fmt.Errorf("get web users by page from db: %w", err)
+	}
+
+	return users, nil
+}
+
+func (s adminService) GetUsersByPage(ctx context.Context, actor *model.WebUser, page int) ([]model.User, error) {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionViewAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.store.User.GetUsersByPage(ctx, convert.PageToOffset(page))
+	if err != nil {
+		logger.Error().Err(err).Msg("get users by page")
+		return nil, fmt.Errorf("get users by page from db: %w", err)
+	}
+
+	return users, nil
+}
+
+// SetChannelEnabled enables or disables ingestion of new messages of the channel.
+func (s adminService) SetChannelEnabled(ctx context.Context, actor *model.WebUser, channelID int, enabled bool) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionManageChannels)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.store.Channel.UpdateChannelEnabled(ctx, channelID, enabled)
+	if err != nil {
+		logger.Error().Err(err).Msg("update channel enabled")
+		return fmt.Errorf("update channel enabled in db: %w", err)
+	}
+
+	if !updated {
+		logger.Info().Int("channel id", channelID).Msg("channel not found")
+		return ErrChannelNotFound
+	}
+
+	logger.Info().Int("actor id", actor.ID).Int("channel id", channelID).Bool("enabled", enabled).
+		Msg("channel successfully updated")
+	return nil
+}
+
+// SetChannelHidden hides or shows the channel and its messages, ingestion of the channel isn't changed.
+func (s adminService) SetChannelHidden(ctx context.Context, actor *model.WebUser, channelID int, hidden bool) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionManageChannels)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.store.Channel.UpdateChannelHidden(ctx, channelID, hidden)
+	if err != nil {
+		logger.Error().Err(err).Msg("update channel hidden")
+		return fmt.Errorf("update channel hidden in db: %w", err)
+	}
+
+	if !updated {
+		logger.Info().Int("channel id", channelID).Msg("channel not found")
+		return ErrChannelNotFound
+	}
+
+	logger.Info().Int("actor id", actor.ID).Int("channel id", channelID).Bool("hidden", hidden).
+		Msg("channel successfully updated")
+	return nil
+}
+
+// BanWebUser bans the user, ends all sessions and revokes API tokens of the user, so the ban takes effect immediately.
+func (s adminService) BanWebUser(ctx context.Context, actor *model.WebUser, userID int) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionManageUsers)
+	if err != nil {
+		return err
+	}
+
+	if actor.ID == userID {
+		logger.Info().Int("user id", userID).Msg("own account can't be banned")
+		return ErrOwnBan
+	}
+
+	bannedAt := time.Now().UTC()
+
+	err = s.updateBannedAt(ctx, userID, &bannedAt)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Session.DeleteSessionsByUserID(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete sessions by user id")
+		return fmt.Errorf("delete sessions by user id from db: %w", err)
+	}
+
+	err = s.store.APIToken.DeleteAPITokensByUserID(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete api tokens by user id")
+		return fmt.Errorf("delete api tokens by user id from db: %w", err)
+	}
+
+	logger.Info().Int("actor id", actor.ID).Int("user id", userID).Msg("web user successfully banned")
+	return nil
+}
+
+func (s adminService) UnbanWebUser(ctx context.Context, actor *model.WebUser, userID int) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionManageUsers)
+	if err != nil {
+		return err
+	}
+
+	err = s.updateBannedAt(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+
+	logger.Info().Int("actor id", actor.ID).Int("user id", userID).Msg("web user successfully unbanned")
+	return nil
+}
+
+func (s adminService) updateBannedAt(ctx context.Context, userID int, bannedAt *time.Time) error {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.store.WebUser.GetWebUserByID(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("get web user by id")
+		return fmt.Errorf("get web user by id from db: %w", err)
+	}
+	if user == nil {
+		logger.Info().Int("user id", userID).Msg("web user by id not found")
+		return ErrWebUserNotFound
+	}
+
+	err = s.store.WebUser.UpdateWebUserBannedAt(ctx, userID, bannedAt)
+	if err != nil {
+		logger.Error().Err(err).Msg("update web user banned at")
+		return fmt.Errorf("update web user banned at in db: %w", err)
+	}
+
+	return nil
+}
+
+// DismissIngestionError deletes the record which failed ingestion without reprocessing it.
+func (s adminService) DismissIngestionError(ctx context.Context, actor *model.WebUser, id int) error {
+	err := s.access.Authorize(ctx, actor, model.PermissionManageIngestion)
+	if err != nil {
+		return err
+	}
+
+	return s.ingestion.DeleteIngestionError(ctx, id)
+}
+
+func (s adminService) GetQuarantinedMessagesByPage(
+	ctx context.Context, actor *model.WebUser, page int,
+) ([]model.QuarantinedMessage, error) {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionManageIngestion)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.store.Quarantine.GetQuarantinedMessagesByPage(ctx, convert.PageToOffset(page))
+	if err != nil {
+		logger.Error().Err(err).Msg("get quarantined messages by page")
+		return nil, fmt.Errorf("get quarantined messages by page from db: %w", err)
+	}
+
+	return messages, nil
+}
+
+// DismissQuarantinedMessage deletes the message caught by the filter without saving it.
+func (s adminService) DismissQuarantinedMessage(ctx context.Context, actor *model.WebUser, id int) error {
+	err := s.access.Authorize(ctx, actor, model.PermissionManageIngestion)
+	if err != nil {
+		return err
+	}
+
+	return s.ingestion.DeleteQuarantinedMessage(ctx, id)
+}
@@ -0,0 +1,541 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	adminUser  = &model.WebUser{ID: 1, Role: model.RoleAdmin}
+	memberUser = &model.WebUser{ID: 2, Role: model.RoleMember}
+)
+
+type adminRepos struct {
+	channel        *mocks.ChannelRepo
+	webUser        *mocks.WebUserRepo
+	session        *mocks.SessionRepo
+	apiToken       *mocks.APITokenRepo
+	ingestionError *mocks.IngestionErrorRepo
+	quarantine     *mocks.QuarantineRepo
+}
+
+func newAdminService(t *testing.T) (service.AdminService, *adminRepos) {
+	t.Helper()
+
+	repos := &adminRepos{
+		channel:        &mocks.ChannelRepo{},
+		webUser:        &mocks.WebUserRepo{},
+		session:        &mocks.SessionRepo{},
+		apiToken:       &mocks.APITokenRepo{},
+		ingestionError: &mocks.IngestionErrorRepo{},
+		quarantine:     &mocks.QuarantineRepo{},
+	}
+
+	store := &store.Store{
+		Channel:        repos.channel,
+		WebUser:        repos.webUser,
+		Session:        repos.session,
+		APIToken:       repos.apiToken,
+		IngestionError: repos.ingestionError,
+		Quarantine:     repos.quarantine,
+	}
+
+	logger := logger.Get(&config.Config{LogLevel: "info"})
+	replyService := service.NewReplyService(store, logger)
+	messageService := service.NewMessageService(store, logger, replyService)
+	channelService := service.NewChannelService(store, logger, messageService)
+	accessService := service.NewAccessService(store, logger)
+	ingestionService := service.NewIngestionService(store, logger)
+
+	return service.NewAdminService(store, logger, accessService, channelService, ingestionService), repos
+}
+
+func (r *adminRepos) assertExpectations(t *testing.T) {
+	t.Helper()
+
+	r.channel.AssertExpectations(t)
+	r.webUser.AssertExpectations(t)
+	r.session.AssertExpectations(t)
+	r.apiToken.AssertExpectations(t)
+	r.ingestionError.AssertExpectations(t)
+	r.quarantine.AssertExpectations(t)
+}
+
+func TestAdminService_GetDashboard(t *testing.T) {
+	t.Parallel()
+
+	channels := []model.Channel{{ID: 1, Name: "test", Enabled: true, Hidden: true}}
+	ingestionErrors := []model.IngestionError{{ID: 1, Topic: "messages", Reason: "invalid"}}
+
+	tests := []struct {
+		name          string
+		mock          func(repos *adminRepos)
+		actor         *model.WebUser
+		want          *service.AdminDashboard
+		expectedError error
+	}{
+		{
+			name: "GetDashboard successful",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("GetAllChannels", mock.Anything).Return(channels, nil)
+				repos.channel.On("GetChannelStats", mock.Anything, 1).
+					Return(&model.Stat{MessagesCount: 2, RepliesCount: 3}, nil)
+				repos.ingestionError.On("GetIngestionErrors", mock.Anything, 20).Return(ingestionErrors, nil)
+			},
+			actor: adminUser,
+			want: &service.AdminDashboard{
+				Channels: []model.Channel{
+					{ID: 1, Name: "test", Enabled: true, Hidden: true, Stats: model.Stat{MessagesCount: 2, RepliesCount: 3}},
+				},
+				IngestionErrors: ingestionErrors,
+			},
+		},
+		{
+			name: "GetDashboard successful without channels and errors",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("GetAllChannels", mock.Anything).Return(nil, nil)
+				repos.ingestionError.On("GetIngestionErrors", mock.Anything, 20).Return(nil, nil)
+			},
+			actor: adminUser,
+			want:  &service.AdminDashboard{},
+		},
+		{
+			name:          "GetDashboard failed with not permitted user",
+			mock:          func(repos *adminRepos) {},
+			actor:         memberUser,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "GetDashboard failed with some store error when get channels",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("GetAllChannels", mock.Anything).Return(nil, fmt.Errorf("some store error"))
+			},
+			actor:         adminUser,
+			expectedError: fmt.Errorf("get all channels from db: %w", fmt.Errorf("some store error")),
+		},
+		{
+			name: "GetDashboard failed with some store error when get ingestion errors",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("GetAllChannels", mock.Anything).Return(nil, nil)
+				repos.ingestionError.On("GetIngestionErrors", mock.Anything, 20).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			actor: adminUser,
+			expectedError: fmt.Errorf(
+				"[GetDashboard]: %w",
+				fmt.Errorf("get ingestion errors from db: %w", fmt.Errorf("some store error")),
+			),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adminService, repos := newAdminService(t)
+			tt.mock(repos)
+
+			got, err := adminService.GetDashboard(context.Background(), tt.actor)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_SetChannelEnabled(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *adminRepos)
+		actor         *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "SetChannelEnabled successful",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("UpdateChannelEnabled", mock.Anything, 1, false).Return(true, nil)
+			},
+			actor: adminUser,
+		},
+		{
+			name:          "SetChannelEnabled failed with not permitted user",
+			mock:          func(repos *adminRepos) {},
+			actor:         &model.WebUser{ID: 2, Role: model.RoleModerator},
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "SetChannelEnabled failed with not found channel",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("UpdateChannelEnabled", mock.Anything, 1, false).Return(false, nil)
+			},
+			actor:         adminUser,
+			expectedError: service.ErrChannelNotFound,
+		},
+		{
+			name: "SetChannelEnabled failed with some store error",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("UpdateChannelEnabled", mock.Anything, 1, false).
+					Return(false, fmt.Errorf("some store error"))
+			},
+			actor:         adminUser,
+			expectedError: fmt.Errorf("update channel enabled in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adminService, repos := newAdminService(t)
+			tt.mock(repos)
+
+			err := adminService.SetChannelEnabled(context.Background(), tt.actor, 1, false)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_SetChannelHidden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *adminRepos)
+		actor         *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "SetChannelHidden successful",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("UpdateChannelHidden", mock.Anything, 1, true).Return(true, nil)
+			},
+			actor: adminUser,
+		},
+		{
+			name:          "SetChannelHidden failed with not permitted user",
+			mock:          func(repos *adminRepos) {},
+			actor:         &model.WebUser{ID: 2, Role: model.RoleModerator},
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "SetChannelHidden failed with not found channel",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("UpdateChannelHidden", mock.Anything, 1, true).Return(false, nil)
+			},
+			actor:         adminUser,
+			expectedError: service.ErrChannelNotFound,
+		},
+		{
+			name: "SetChannelHidden failed with some store error",
+			mock: func(repos *adminRepos) {
+				repos.channel.On("UpdateChannelHidden", mock.Anything, 1, true).
+					Return(false, fmt.Errorf("some store error"))
+			},
+			actor:         adminUser,
+			expectedError: fmt.Errorf("update channel hidden in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adminService, repos := newAdminService(t)
+			tt.mock(repos)
+
+			err := adminService.SetChannelHidden(context.Background(), tt.actor, 1, true)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_BanWebUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *adminRepos)
+		actor         *model.WebUser
+		userID        int
+		expectedError error
+	}{
+		{
+			name: "BanWebUser successful",
+			mock: func(repos *adminRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 2).Return(memberUser, nil)
+				repos.webUser.On("UpdateWebUserBannedAt", mock.Anything, 2, mock.MatchedBy(func(at *time.Time) bool {
+					return at != nil
+				})).Return(nil)
+				repos.session.On("DeleteSessionsByUserID", mock.Anything, 2).Return(nil)
+				repos.apiToken.On("DeleteAPITokensByUserID", mock.Anything, 2).Return(nil)
+			},
+			actor:  adminUser,
+			userID: 2,
+		},
+		{
+			name:          "BanWebUser failed with not permitted user",
+			mock:          func(repos *adminRepos) {},
+			actor:         memberUser,
+			userID:        1,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name:          "BanWebUser failed with own account",
+			mock:          func(repos *adminRepos) {},
+			actor:         adminUser,
+			userID:        1,
+			expectedError: service.ErrOwnBan,
+		},
+		{
+			name: "BanWebUser failed with not found user",
+			mock: func(repos *adminRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 2).Return(nil, nil)
+			},
+			actor:         adminUser,
+			userID:        2,
+			expectedError: service.ErrWebUserNotFound,
+		},
+		{
+			name: "BanWebUser failed with some store error when delete sessions",
+			mock: func(repos *adminRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 2).Return(memberUser, nil)
+				repos.webUser.On("UpdateWebUserBannedAt", mock.Anything, 2, mock.Anything).Return(nil)
+				repos.session.On("DeleteSessionsByUserID", mock.Anything, 2).Return(fmt.Errorf("some store error"))
+			},
+			actor:         adminUser,
+			userID:        2,
+			expectedError: fmt.Errorf("delete sessions by user id from db: %w", fmt.Errorf("some store error")),
+		},
+		{
+			name: "BanWebUser failed with some store error when delete api tokens",
+			mock: func(repos *adminRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 2).Return(memberUser, nil)
+				repos.webUser.On("UpdateWebUserBannedAt", mock.Anything, 2, mock.Anything).Return(nil)
+				repos.session.On("DeleteSessionsByUserID", mock.Anything, 2).Return(nil)
+				repos.apiToken.On("DeleteAPITokensByUserID", mock.Anything, 2).Return(fmt.Errorf("some store error"))
+			},
+			actor:         adminUser,
+			userID:        2,
+			expectedError: fmt.Errorf("delete api tokens by user id from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adminService, repos := newAdminService(t)
+			tt.mock(repos)
+
+			err := adminService.BanWebUser(context.Background(), tt.actor, tt.userID)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_UnbanWebUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *adminRepos)
+		actor         *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "UnbanWebUser successful",
+			mock: func(repos *adminRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 2).Return(memberUser, nil)
+				repos.webUser.On("UpdateWebUserBannedAt", mock.Anything, 2, (*time.Time)(nil)).Return(nil)
+			},
+			actor: adminUser,
+		},
+		{
+			name:          "UnbanWebUser failed with not permitted user",
+			mock:          func(repos *adminRepos) {},
+			actor:         memberUser,
+			expectedError: service.ErrPermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adminService, repos := newAdminService(t)
+			tt.mock(repos)
+
+			err := adminService.UnbanWebUser(context.Background(), tt.actor, 2)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_DismissIngestionError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *adminRepos)
+		actor         *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "DismissIngestionError successful",
+			mock: func(repos *adminRepos) {
+				repos.ingestionError.On("DeleteIngestionError", mock.Anything, 1).Return(true, nil)
+			},
+			actor: adminUser,
+		},
+		{
+			name:          "DismissIngestionError failed with not permitted user",
+			mock:          func(repos *adminRepos) {},
+			actor:         &model.WebUser{ID: 2, Role: model.RoleModerator},
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "DismissIngestionError failed with not found error",
+			mock: func(repos *adminRepos) {
+				repos.ingestionError.On("DeleteIngestionError", mock.Anything, 1).Return(false, nil)
+			},
+			actor:         adminUser,
+			expectedError: service.ErrIngestionErrorNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adminService, repos := newAdminService(t)
+			tt.mock(repos)
+
+			err := adminService.DismissIngestionError(context.Background(), tt.actor, 1)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_GetQuarantinedMessagesByPage(t *testing.T) {
+	t.Parallel()
+
+	messages := []model.QuarantinedMessage{{ID: 1, ChannelID: 1, Title: "hi", Rule: model.FilterRuleMinLength}}
+
+	tests := []struct {
+		name          string
+		mock          func(repos *adminRepos)
+		actor         *model.WebUser
+		want          []model.QuarantinedMessage
+		expectedError error
+	}{
+		{
+			name: "GetQuarantinedMessagesByPage successful",
+			mock: func(repos *adminRepos) {
+				repos.quarantine.On("GetQuarantinedMessagesByPage", mock.Anything, 10).Return(messages, nil)
+			},
+			actor: adminUser,
+			want:  messages,
+		},
+		{
+			name:          "GetQuarantinedMessagesByPage failed with not permitted user",
+			mock:          func(repos *adminRepos) {},
+			actor:         &model.WebUser{ID: 2, Role: model.RoleModerator},
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "GetQuarantinedMessagesByPage failed with some store error",
+			mock: func(repos *adminRepos) {
+				repos.quarantine.On("GetQuarantinedMessagesByPage", mock.Anything, 10).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			actor:         adminUser,
+			expectedError: fmt.Errorf("get quarantined messages by page from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adminService, repos := newAdminService(t)
+			tt.mock(repos)
+
+			got, err := adminService.GetQuarantinedMessagesByPage(context.Background(), tt.actor, 2)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_DismissQuarantinedMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *adminRepos)
+		actor         *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "DismissQuarantinedMessage successful",
+			mock: func(repos *adminRepos) {
+				repos.quarantine.On("DeleteQuarantinedMessage", mock.Anything, 1).Return(true, nil)
+			},
+			actor: adminUser,
+		},
+		{
+			name:          "DismissQuarantinedMessage failed with not permitted user",
+			mock:          func(repos *adminRepos) {},
+			actor:         memberUser,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "DismissQuarantinedMessage failed with not found message",
+			mock: func(repos *adminRepos) {
+				repos.quarantine.On("DeleteQuarantinedMessage", mock.Anything, 1).Return(false, nil)
+			},
+			actor:         adminUser,
+			expectedError: service.ErrQuarantinedMessageNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			adminService, repos := newAdminService(t)
+			tt.mock(repos)
+
+			err := adminService.DismissQuarantinedMessage(context.Background(), tt.actor, 1)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
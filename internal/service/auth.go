@@ -1,32 +1,52 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/mail"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 	"github.com/VladPetriv/scanner_backend/pkg/logger"
 	"github.com/VladPetriv/scanner_backend/pkg/password"
 )
 
+// dummyPasswordHash is compared with the password of unknown email,
+// so response time doesn't reveal whether the email is registered.
+const dummyPasswordHash = "$2a$12$pImH6OJLMcEky9zizSRh6e2wmlwClh5/I7Rvx/4g54FmdGtIVswPG"
+
 type authService struct {
-	logger         *logger.Logger
-	WebUserService WebUserService
+	logger              *logger.Logger
+	WebUserService      WebUserService
+	LoginAttemptService LoginAttemptService
 }
 
 var _ AuthService = (*authService)(nil)
 
-func NewAuthService(webUserService WebUserService, logger *logger.Logger) *authService {
+func NewAuthService(
+	webUserService WebUserService, loginAttemptService LoginAttemptService, logger *logger.Logger,
+) *authService {
 	return &authService{
-		WebUserService: webUserService,
-		logger:         logger,
+		WebUserService:      webUserService,
+		LoginAttemptService: loginAttemptService,
+		logger:              logger,
 	}
 }
 
-func (s authService) Register(user *model.WebUser) error {
-	logger := s.logger
+func (s authService) Register(ctx context.Context, user *model.WebUser) error {
+	logger := s.logger.ForContext(ctx)
+
+	if !validEmail(user.Email) {
+		logger.Info().Str("email", user.Email).Msg("invalid email")
+		return ErrInvalidEmail
+	}
+
+	if len(user.Password) < minPasswordLength {
+		logger.Info().Msg("password is too short")
+		return ErrPasswordTooShort
+	}
 
-	candidate, err := s.WebUserService.GetWebUserByEmail(user.Email)
+	candidate, err := s.WebUserService.GetWebUserByEmail(ctx, user.Email)
 	if err != nil {
 		if !errors.Is(err, ErrWebUserNotFound) {
 			logger.Error().Err(err).Msg("get web user by email")
@@ -48,7 +68,7 @@ func (s authService) Register(user *model.WebUser) error {
 
 	user.Password = hashedPassword
 
-	err = s.WebUserService.CreateWebUser(user)
+	err = s.WebUserService.CreateWebUser(ctx, user)
 	if err != nil {
 		logger.Error().Err(err).Msg("create web user")
 		return fmt.Errorf("[Register]: %w", err)
@@ -58,12 +78,21 @@ func (s authService) Register(user *model.WebUser) error {
 	return nil
 }
 
-func (s authService) Login(email string, userPassword string) (string, error) {
-	logger := s.logger
+// Login checks the password of the web user, failed attempts are recorded and locked out after the limit.
+func (s authService) Login(ctx context.Context, email string, userPassword string, ipAddress string) (string, error) {
+	logger := s.logger.ForContext(ctx)
 
-	candidate, err := s.WebUserService.GetWebUserByEmail(email)
+	err := s.LoginAttemptService.CheckLoginAttempt(ctx, email, ipAddress)
+	if err != nil {
+		return "", err
+	}
+
+	candidate, err := s.WebUserService.GetWebUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, ErrWebUserNotFound) {
+			password.ComparePassword(userPassword, dummyPasswordHash)
+			s.recordLoginFailure(ctx, email, ipAddress)
+
 			logger.Info().Msg("web user not found")
 			return "", err
 		}
@@ -73,10 +102,41 @@ func (s authService) Login(email string, userPassword string) (string, error) {
 	}
 
 	if !password.ComparePassword(userPassword, candidate.Password) {
+		s.recordLoginFailure(ctx, email, ipAddress)
+
 		logger.Info().Msg("incorrect password")
 		return "", ErrIncorrectPassword
 	}
 
+	if candidate.Banned() {
+		logger.Info().Int("user id", candidate.ID).Msg("web user is banned")
+		return "", ErrWebUserBanned
+	}
+
+	// Failures of the user with two-factor authentication are reset only after the code is checked,
+	// otherwise the known password would allow to guess the code without limit.
+	if !candidate.TOTPEnabled {
+		err = s.LoginAttemptService.ResetLoginFailures(ctx, email)
+		if err != nil {
+			logger.Error().Err(err).Msg("reset login failures")
+		}
+	}
+
 	logger.Info().Msg("user successfully logined")
 	return email, nil
 }
+
+// recordLoginFailure doesn't fail login, the failure is returned to the user anyway.
+func (s authService) recordLoginFailure(ctx context.Context, email string, ipAddress string) {
+	err := s.LoginAttemptService.RecordLoginFailure(ctx, email, ipAddress)
+	if err != nil {
+		s.logger.ForContext(ctx).Error().Err(err).Msg("record login failure")
+	}
+}
+
+// validEmail accepts bare address like "user@example.com", display names and comments are rejected.
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+
+	return err == nil && address.Address == email
+}
@@ -1,8 +1,10 @@
 package service_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 	"github.com/VladPetriv/scanner_backend/internal/service"
@@ -11,12 +13,13 @@ import (
 	"github.com/VladPetriv/scanner_backend/pkg/config"
 	"github.com/VladPetriv/scanner_backend/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestAuthService_Register(t *testing.T) {
 	t.Parallel()
 
-	input := &model.WebUser{Email: "test@test.com", Password: "test"}
+	input := &model.WebUser{Email: "test@test.com", Password: "password"}
 
 	tests := []struct {
 		name          string
@@ -27,15 +30,27 @@ func TestAuthService_Register(t *testing.T) {
 		{
 			name: "Register successful",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(nil, nil)
-				webUserRepo.On("CreateWebUser", input).Return(nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, nil)
+				webUserRepo.On("CreateWebUser", mock.Anything, input).Return(nil)
 			},
 			input: input,
 		},
+		{
+			name:          "Register failed with invalid email",
+			mock:          func(webUserRepo *mocks.WebUserRepo) {},
+			input:         &model.WebUser{Email: "Test <test@test.com>", Password: "password"},
+			expectedError: service.ErrInvalidEmail,
+		},
+		{
+			name:          "Register failed with too short password",
+			mock:          func(webUserRepo *mocks.WebUserRepo) {},
+			input:         &model.WebUser{Email: "test@test.com", Password: "short"},
+			expectedError: service.ErrPasswordTooShort,
+		},
 		{
 			name: "Register failed with existed user",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(input, nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(input, nil)
 			},
 			input:         input,
 			expectedError: service.ErrWebUserIsExist,
@@ -43,9 +58,9 @@ func TestAuthService_Register(t *testing.T) {
 		{
 			name: "Register failed with some store error when get user by email",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(nil, fmt.Errorf("some store error"))
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, fmt.Errorf("some store error"))
 			},
-			input: &model.WebUser{Email: "test@test.com", Password: "test"},
+			input: &model.WebUser{Email: "test@test.com", Password: "password"},
 			expectedError: fmt.Errorf(
 				"[Register]: %w",
 				fmt.Errorf("get web user by email from db: %w", fmt.Errorf("some store error")),
@@ -54,8 +69,8 @@ func TestAuthService_Register(t *testing.T) {
 		{
 			name: "Register failed with some store error when create user",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(nil, nil)
-				webUserRepo.On("CreateWebUser", input).Return(fmt.Errorf("some store error"))
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, nil)
+				webUserRepo.On("CreateWebUser", mock.Anything, input).Return(fmt.Errorf("some store error"))
 			},
 			input: input,
 			expectedError: fmt.Errorf(
@@ -73,10 +88,11 @@ func TestAuthService_Register(t *testing.T) {
 
 			logger := logger.Get(&config.Config{LogLevel: "info"})
 			webUserService := service.NewWebUserService(&store.Store{WebUser: webUserRepo}, logger)
-			authService := service.NewAuthService(webUserService, logger)
+			loginAttemptService := service.NewLoginAttemptService(&store.Store{}, logger, service.LoginLimits{})
+			authService := service.NewAuthService(webUserService, loginAttemptService, logger)
 			tt.mock(webUserRepo)
 
-			err := authService.Register(tt.input)
+			err := authService.Register(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 
 			webUserRepo.AssertExpectations(t)
@@ -84,6 +100,13 @@ func TestAuthService_Register(t *testing.T) {
 	}
 }
 
+var loginLimits = service.LoginLimits{
+	MaxFailures:      5,
+	MaxFailuresPerIP: 20,
+	Window:           time.Hour,
+	Lockout:          time.Minute,
+}
+
 func TestAuthService_Login(t *testing.T) {
 	t.Parallel()
 
@@ -94,15 +117,46 @@ func TestAuthService_Login(t *testing.T) {
 
 	tests := []struct {
 		name          string
-		mock          func(webUserRepo *mocks.WebUserRepo)
+		mock          func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo)
 		input         *model.WebUser
 		want          string
 		expectedError error
 	}{
 		{
 			name: "Login successful",
-			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(returned, nil)
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				expectLoginAttempt(loginFailureRepo)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(returned, nil)
+				loginFailureRepo.On("DeleteLoginFailuresByEmail", mock.Anything, "test@test.com").Return(nil)
+			},
+			want: "test@test.com",
+			input: &model.WebUser{
+				Email:    "test@test.com",
+				Password: "test",
+			},
+		},
+		{
+			name: "Login successful with expired lockout",
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				loginFailureRepo.On("GetLoginFailuresByEmail", mock.Anything, "test@test.com", mock.Anything).
+					Return(&model.LoginFailureStats{Count: 6, LastAt: time.Now().UTC().Add(-3 * time.Minute)}, nil)
+				loginFailureRepo.On("GetLoginFailuresByIP", mock.Anything, "127.0.0.1", mock.Anything).
+					Return(&model.LoginFailureStats{}, nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(returned, nil)
+				loginFailureRepo.On("DeleteLoginFailuresByEmail", mock.Anything, "test@test.com").Return(nil)
+			},
+			want: "test@test.com",
+			input: &model.WebUser{
+				Email:    "test@test.com",
+				Password: "test",
+			},
+		},
+		{
+			name: "Login successful without reset of failures when two-factor authentication is enabled",
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				expectLoginAttempt(loginFailureRepo)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
+					Return(&model.WebUser{Email: returned.Email, Password: returned.Password, TOTPEnabled: true}, nil)
 			},
 			want: "test@test.com",
 			input: &model.WebUser{
@@ -112,8 +166,13 @@ func TestAuthService_Login(t *testing.T) {
 		},
 		{
 			name: "Login failed with not found user",
-			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(nil, nil)
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				expectLoginAttempt(loginFailureRepo)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, nil)
+				loginFailureRepo.On("DeleteLoginFailuresBefore", mock.Anything, mock.Anything).Return(nil)
+				loginFailureRepo.On("CreateLoginFailure", mock.Anything, mock.MatchedBy(func(failure *model.LoginFailure) bool {
+					return failure.Email == "test@test.com" && failure.IPAddress == "127.0.0.1"
+				})).Return(nil)
 			},
 			input: &model.WebUser{
 				Email: "test@test.com",
@@ -122,8 +181,11 @@ func TestAuthService_Login(t *testing.T) {
 		},
 		{
 			name: "Login failed with incorrect password",
-			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(returned, nil)
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				expectLoginAttempt(loginFailureRepo)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(returned, nil)
+				loginFailureRepo.On("DeleteLoginFailuresBefore", mock.Anything, mock.Anything).Return(nil)
+				loginFailureRepo.On("CreateLoginFailure", mock.Anything, mock.Anything).Return(nil)
 			},
 			input: &model.WebUser{
 				Email:    "test@test.com",
@@ -131,10 +193,63 @@ func TestAuthService_Login(t *testing.T) {
 			},
 			expectedError: service.ErrIncorrectPassword,
 		},
+		{
+			name: "Login failed with banned user",
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				bannedAt := time.Now().UTC()
+
+				expectLoginAttempt(loginFailureRepo)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
+					Return(&model.WebUser{Email: returned.Email, Password: returned.Password, BannedAt: &bannedAt}, nil)
+			},
+			input: &model.WebUser{
+				Email:    "test@test.com",
+				Password: "test",
+			},
+			expectedError: service.ErrWebUserBanned,
+		},
+		{
+			name: "Login failed with locked out email",
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				loginFailureRepo.On("GetLoginFailuresByEmail", mock.Anything, "test@test.com", mock.Anything).
+					Return(&model.LoginFailureStats{Count: 6, LastAt: time.Now().UTC().Add(-time.Minute)}, nil)
+			},
+			input: &model.WebUser{
+				Email:    "Test@test.com",
+				Password: "test",
+			},
+			expectedError: service.ErrTooManyLoginAttempts,
+		},
+		{
+			name: "Login failed with locked out ip",
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				loginFailureRepo.On("GetLoginFailuresByEmail", mock.Anything, "test@test.com", mock.Anything).
+					Return(&model.LoginFailureStats{}, nil)
+				loginFailureRepo.On("GetLoginFailuresByIP", mock.Anything, "127.0.0.1", mock.Anything).
+					Return(&model.LoginFailureStats{Count: 20, LastAt: time.Now().UTC()}, nil)
+			},
+			input: &model.WebUser{
+				Email:    "test@test.com",
+				Password: "test",
+			},
+			expectedError: service.ErrTooManyLoginAttempts,
+		},
+		{
+			name: "Login failed with some store error when get login failures",
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				loginFailureRepo.On("GetLoginFailuresByEmail", mock.Anything, "test@test.com", mock.Anything).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			input: &model.WebUser{
+				Email: "test@test.com",
+			},
+			expectedError: fmt.Errorf("get login failures by email from db: %w", fmt.Errorf("some store error")),
+		},
 		{
 			name: "Login failed with some store error when get user",
-			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(nil, fmt.Errorf("some store error"))
+			mock: func(webUserRepo *mocks.WebUserRepo, loginFailureRepo *mocks.LoginFailureRepo) {
+				expectLoginAttempt(loginFailureRepo)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, fmt.Errorf("some store error"))
 			},
 			input: &model.WebUser{
 				Email: "test@test.com",
@@ -151,17 +266,29 @@ func TestAuthService_Login(t *testing.T) {
 			t.Parallel()
 
 			webUserRepo := &mocks.WebUserRepo{}
+			loginFailureRepo := &mocks.LoginFailureRepo{}
 
 			logger := logger.Get(&config.Config{LogLevel: "info"})
-			webUserService := service.NewWebUserService(&store.Store{WebUser: webUserRepo}, logger)
-			authService := service.NewAuthService(webUserService, logger)
-			tt.mock(webUserRepo)
+			store := &store.Store{WebUser: webUserRepo, LoginFailure: loginFailureRepo}
+			webUserService := service.NewWebUserService(store, logger)
+			loginAttemptService := service.NewLoginAttemptService(store, logger, loginLimits)
+			authService := service.NewAuthService(webUserService, loginAttemptService, logger)
+			tt.mock(webUserRepo, loginFailureRepo)
 
-			got, err := authService.Login(tt.input.Email, tt.input.Password)
+			got, err := authService.Login(context.Background(), tt.input.Email, tt.input.Password, "127.0.0.1")
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
 			webUserRepo.AssertExpectations(t)
+			loginFailureRepo.AssertExpectations(t)
 		})
 	}
 }
+
+// expectLoginAttempt expects check of the login without previous failures.
+func expectLoginAttempt(loginFailureRepo *mocks.LoginFailureRepo) {
+	noFailures := &model.LoginFailureStats{}
+
+	loginFailureRepo.On("GetLoginFailuresByEmail", mock.Anything, "test@test.com", mock.Anything).Return(noFailures, nil)
+	loginFailureRepo.On("GetLoginFailuresByIP", mock.Anything, "127.0.0.1", mock.Anything).Return(noFailures, nil)
+}
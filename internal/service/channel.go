@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -26,10 +27,10 @@ func NewChannelService(store *store.Store, logger *logger.Logger, messageService
 	}
 }
 
-func (s channelService) CreateChannel(channel *model.DBChannel) error {
-	logger := s.logger
+func (s channelService) CreateChannel(ctx context.Context, channel *model.DBChannel) error {
+	logger := s.logger.ForContext(ctx)
 
-	candidate, err := s.GetChannelByName(channel.Name)
+	candidate, err := s.GetChannelByName(ctx, channel.Name)
 	if err != nil {
 		if !errors.Is(err, ErrChannelNotFound) {
 			logger.Error().Err(err).Msg("get channel by name")
@@ -41,7 +42,7 @@ func (s channelService) CreateChannel(channel *model.DBChannel) error {
 		return ErrChannelExists
 	}
 
-	err = s.store.Channel.CreateChannel(channel)
+	err = s.store.Channel.CreateChannel(ctx, channel)
 	if err != nil {
 		logger.Error().Err(err).Msg("create channel")
 		return fmt.Errorf("create channel in db: %w", err)
@@ -51,10 +52,40 @@ func (s channelService) CreateChannel(channel *model.DBChannel) error {
 	return nil
 }
 
-func (s channelService) GetChannels() ([]model.Channel, error) {
-	logger := s.logger
+func (s channelService) UpdateChannel(ctx context.Context, channel *model.DBChannel) error {
+	logger := s.logger.ForContext(ctx)
 
-	channels, err := s.store.Channel.GetChannels()
+	candidate, err := s.GetChannelByName(ctx, channel.Name)
+	if err != nil {
+		if errors.Is(err, ErrChannelNotFound) {
+			return err
+		}
+
+		logger.Error().Err(err).Msg("get channel by name")
+		return fmt.Errorf("[UpdateChannel]: %w", err)
+	}
+
+	if candidate.Title == channel.Title && candidate.ImageURL == channel.ImageURL {
+		logger.Info().Str("channel name", channel.Name).Msg("channel is up to date")
+		return nil
+	}
+
+	channel.ID = candidate.ID
+
+	err = s.store.Channel.UpdateChannel(ctx, channel)
+	if err != nil {
+		logger.Error().Err(err).Msg("update channel")
+		return fmt.Errorf("update channel in db: %w", err)
+	}
+
+	logger.Info().Int("channel id", channel.ID).Msg("channel successfully updated")
+	return nil
+}
+
+func (s channelService) GetChannels(ctx context.Context) ([]model.Channel, error) {
+	logger := s.logger.ForContext(ctx)
+
+	channels, err := s.store.Channel.GetChannels(ctx)
 	if err != nil {
 		logger.Error().Err(err).Msg("get channels")
 		return nil, fmt.Errorf("get channels from db: %w", err)
@@ -69,10 +100,10 @@ func (s channelService) GetChannels() ([]model.Channel, error) {
 	return channels, nil
 }
 
-func (s channelService) GetChannelsByPage(page int) ([]model.Channel, error) {
-	logger := s.logger
+func (s channelService) GetChannelsByPage(ctx context.Context, page int) ([]model.Channel, error) {
+	logger := s.logger.ForContext(ctx)
 
-	channels, err := s.store.Channel.GetChannelsByPage(convert.PageToOffset(page))
+	channels, err := s.store.Channel.GetChannelsByPage(ctx, convert.PageToOffset(page))
 	if err != nil {
 		logger.Error().Err(err).Msg("get channels by page")
 		return nil, fmt.Errorf("get channels by page from db: %w", err)
@@ -87,10 +118,10 @@ func (s channelService) GetChannelsByPage(page int) ([]model.Channel, error) {
 	return channels, nil
 }
 
-func (s channelService) GetChannelByName(name string) (*model.Channel, error) {
-	logger := s.logger
+func (s channelService) GetChannelByName(ctx context.Context, name string) (*model.Channel, error) {
+	logger := s.logger.ForContext(ctx)
 
-	channel, err := s.store.Channel.GetChannelByName(name)
+	channel, err := s.store.Channel.GetChannelByName(ctx, name)
 	if err != nil {
 		logger.Error().Err(err).Msg("get channel by name")
 		return nil, fmt.Errorf("get channel by name from db: %w", err)
@@ -105,10 +136,10 @@ func (s channelService) GetChannelByName(name string) (*model.Channel, error) {
 	return channel, nil
 }
 
-func (s channelService) GetChannelStats(channelID int) (*model.Stat, error) {
-	logger := s.logger
+func (s channelService) GetChannelStats(ctx context.Context, channelID int) (*model.Stat, error) {
+	logger := s.logger.ForContext(ctx)
 
-	stat, err := s.store.Channel.GetChannelStats(channelID)
+	stat, err := s.store.Channel.GetChannelStats(ctx, channelID)
 	if err != nil {
 		logger.Error().Err(err).Msg("get channels statistic")
 		return nil, fmt.Errorf("get channel statistic from db: %w", err)
@@ -123,10 +154,10 @@ func (s channelService) GetChannelStats(channelID int) (*model.Stat, error) {
 	return stat, nil
 }
 
-func (s channelService) ProcessChannelPage(channelName string, page int) (*LoadChannelOutput, error) {
-	logger := s.logger
+func (s channelService) ProcessChannelPage(ctx context.Context, channelName string, page int) (*LoadChannelOutput, error) {
+	logger := s.logger.ForContext(ctx)
 
-	channel, err := s.GetChannelByName(channelName)
+	channel, err := s.GetChannelByName(ctx, channelName)
 	if err != nil {
 		if errors.Is(err, ErrChannelNotFound) {
 			logger.Info().Str("channel name", channelName).Msg("channel by name not found")
@@ -137,7 +168,12 @@ func (s channelService) ProcessChannelPage(channelName string, page int) (*LoadC
 		return nil, fmt.Errorf("[ProcessChannelPage]: %w", err)
 	}
 
-	messagesCount, err := s.message.GetMessagesCountByChannelID(channel.ID)
+	if channel.Hidden {
+		logger.Info().Str("channel name", channelName).Msg("channel is hidden")
+		return &LoadChannelOutput{}, nil
+	}
+
+	messagesCount, err := s.message.GetMessagesCountByChannelID(ctx, channel.ID)
 	if err != nil {
 		if errors.Is(err, ErrMessagesCountNotFound) {
 			logger.Info().Int("channel id", channel.ID).Msg("messages count by channel id not found")
@@ -150,7 +186,7 @@ func (s channelService) ProcessChannelPage(channelName string, page int) (*LoadC
 		return nil, fmt.Errorf("[ProcessChannelPage]: %w", err)
 	}
 
-	messages, err := s.message.GetFullMessagesByChannelIDAndPage(channel.ID, page)
+	messages, err := s.message.GetFullMessagesByChannelIDAndPage(ctx, channel.ID, page)
 	if err != nil {
 		if errors.Is(err, ErrMessagesNotFound) {
 			logger.Info().Int("page", page).Msg("messages not found")
@@ -171,10 +207,10 @@ func (s channelService) ProcessChannelPage(channelName string, page int) (*LoadC
 	}, nil
 }
 
-func (s channelService) ProcessChannelsPage(page int) (*LoadChannelsOutput, error) {
-	logger := s.logger
+func (s channelService) ProcessChannelsPage(ctx context.Context, page int) (*LoadChannelsOutput, error) {
+	logger := s.logger.ForContext(ctx)
 
-	channels, err := s.GetChannelsByPage(page)
+	channels, err := s.GetChannelsByPage(ctx, page)
 	if err != nil {
 		if errors.Is(err, ErrChannelsNotFound) {
 			logger.Info().Int("page", page).Msg("channels by page not found")
@@ -186,7 +222,7 @@ func (s channelService) ProcessChannelsPage(page int) (*LoadChannelsOutput, erro
 	}
 
 	for index, channel := range channels {
-		stat, err := s.GetChannelStats(channel.ID)
+		stat, err := s.GetChannelStats(ctx, channel.ID)
 		if err != nil {
 			logger.Error().Err(err).Msg("get channel stats")
 
@@ -1,10 +1,12 @@
 package service_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 	"github.com/VladPetriv/scanner_backend/internal/service"
@@ -26,8 +28,8 @@ func TestChannelService_CreateChannel(t *testing.T) {
 		{
 			name: "CreateChannel successful",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(nil, nil)
-				channelRepo.On("CreateChannel", &model.DBChannel{
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(nil, nil)
+				channelRepo.On("CreateChannel", mock.Anything, &model.DBChannel{
 					Name:     "test",
 					Title:    "test T",
 					ImageURL: "test.jpg",
@@ -38,7 +40,7 @@ func TestChannelService_CreateChannel(t *testing.T) {
 		{
 			name: "CreateChannel failed with existed channel",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(&model.Channel{Name: "test"}, nil)
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(&model.Channel{Name: "test"}, nil)
 			},
 			input:         &model.DBChannel{Name: "test", Title: "test T", ImageURL: "test.jpg"},
 			expectedError: service.ErrChannelExists,
@@ -46,7 +48,7 @@ func TestChannelService_CreateChannel(t *testing.T) {
 		{
 			name: "CreateChannel failed with some store error when get channel by name",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(nil, fmt.Errorf("some store error"))
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(nil, fmt.Errorf("some store error"))
 			},
 			input: &model.DBChannel{Name: "test", Title: "test T", ImageURL: "test.jpg"},
 			expectedError: fmt.Errorf(
@@ -60,8 +62,8 @@ func TestChannelService_CreateChannel(t *testing.T) {
 		{
 			name: "CreateChannel failed with some store error when create channel",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(nil, nil)
-				channelRepo.On("CreateChannel", &model.DBChannel{Name: "test",
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(nil, nil)
+				channelRepo.On("CreateChannel", mock.Anything, &model.DBChannel{Name: "test",
 					Title:    "test T",
 					ImageURL: "test.jpg",
 				}).Return(fmt.Errorf("some store error"))
@@ -88,7 +90,7 @@ func TestChannelService_CreateChannel(t *testing.T) {
 			channelService := service.NewChannelService(&store.Store{Channel: channelRepo}, logger, messageService)
 			tt.mock(channelRepo)
 
-			err := channelService.CreateChannel(tt.input)
+			err := channelService.CreateChannel(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 
 			channelRepo.AssertExpectations(t)
@@ -98,6 +100,83 @@ func TestChannelService_CreateChannel(t *testing.T) {
 	}
 }
 
+func TestChannelService_UpdateChannel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(channelRepo *mocks.ChannelRepo)
+		input         *model.DBChannel
+		expectedError error
+	}{
+		{
+			name: "UpdateChannel successful",
+			mock: func(channelRepo *mocks.ChannelRepo) {
+				channelRepo.On("GetChannelByName", mock.Anything, "test").
+					Return(&model.Channel{ID: 1, Name: "test", Title: "old", ImageURL: "old.jpg"}, nil)
+				channelRepo.On("UpdateChannel", mock.Anything, &model.DBChannel{
+					ID:       1,
+					Name:     "test",
+					Title:    "test T",
+					ImageURL: "test.jpg",
+				}).Return(nil)
+			},
+			input: &model.DBChannel{Name: "test", Title: "test T", ImageURL: "test.jpg"},
+		},
+		{
+			name: "UpdateChannel successful without changes",
+			mock: func(channelRepo *mocks.ChannelRepo) {
+				channelRepo.On("GetChannelByName", mock.Anything, "test").
+					Return(&model.Channel{ID: 1, Name: "test", Title: "test T", ImageURL: "test.jpg"}, nil)
+			},
+			input: &model.DBChannel{Name: "test", Title: "test T", ImageURL: "test.jpg"},
+		},
+		{
+			name: "UpdateChannel failed with not found channel",
+			mock: func(channelRepo *mocks.ChannelRepo) {
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(nil, nil)
+			},
+			input:         &model.DBChannel{Name: "test", Title: "test T", ImageURL: "test.jpg"},
+			expectedError: service.ErrChannelNotFound,
+		},
+		{
+			name: "UpdateChannel failed with some store error when update channel",
+			mock: func(channelRepo *mocks.ChannelRepo) {
+				channelRepo.On("GetChannelByName", mock.Anything, "test").
+					Return(&model.Channel{ID: 1, Name: "test", Title: "old", ImageURL: "old.jpg"}, nil)
+				channelRepo.On("UpdateChannel", mock.Anything, &model.DBChannel{
+					ID:       1,
+					Name:     "test",
+					Title:    "test T",
+					ImageURL: "test.jpg",
+				}).Return(fmt.Errorf("some store error"))
+			},
+			input: &model.DBChannel{Name: "test", Title: "test T", ImageURL: "test.jpg"},
+			expectedError: fmt.Errorf(
+				"update channel in db: %w",
+				fmt.Errorf("some store error"),
+			),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			channelRepo := &mocks.ChannelRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			channelService := service.NewChannelService(&store.Store{Channel: channelRepo}, logger, nil)
+			tt.mock(channelRepo)
+
+			err := channelService.UpdateChannel(context.Background(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+
+			channelRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestChannelService_GetChannels(t *testing.T) {
 	t.Parallel()
 
@@ -115,21 +194,21 @@ func TestChannelService_GetChannels(t *testing.T) {
 		{
 			name: "GetChannels successful",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannels").Return(data, nil)
+				channelRepo.On("GetChannels", mock.Anything).Return(data, nil)
 			},
 			want: data,
 		},
 		{
 			name: "GetChannel failed with not found channels",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannels").Return(nil, nil)
+				channelRepo.On("GetChannels", mock.Anything).Return(nil, nil)
 			},
 			expectedError: service.ErrChannelsNotFound,
 		},
 		{
 			name: "GetChannel failed with some store error",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannels").Return(nil, fmt.Errorf("some store error"))
+				channelRepo.On("GetChannels", mock.Anything).Return(nil, fmt.Errorf("some store error"))
 			},
 			expectedError: fmt.Errorf(
 				"get channels from db: %w",
@@ -152,7 +231,7 @@ func TestChannelService_GetChannels(t *testing.T) {
 			channelService := service.NewChannelService(&store.Store{Channel: channelRepo}, logger, messageService)
 			tt.mock(channelRepo)
 
-			got, err := channelService.GetChannels()
+			got, err := channelService.GetChannels(context.Background())
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -181,7 +260,7 @@ func TestChannelService_GetChannelsByPage(t *testing.T) {
 		{
 			name: "GetChannelsByPage successful with first page",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 0).Return(data[:10], nil)
+				channelRepo.On("GetChannelsByPage", mock.Anything, 0).Return(data[:10], nil)
 			},
 			input: 1,
 			want:  data[:10],
@@ -189,7 +268,7 @@ func TestChannelService_GetChannelsByPage(t *testing.T) {
 		{
 			name: "GetChannelsByPage successful with second page",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 10).Return(data[9:], nil)
+				channelRepo.On("GetChannelsByPage", mock.Anything, 10).Return(data[9:], nil)
 			},
 			input: 2,
 			want:  data[9:],
@@ -197,14 +276,14 @@ func TestChannelService_GetChannelsByPage(t *testing.T) {
 		{
 			name: "GetChannelsByPage failed with not found channels by page",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 4030).Return(nil, nil)
+				channelRepo.On("GetChannelsByPage", mock.Anything, 4030).Return(nil, nil)
 			},
 			input:         404,
 			expectedError: service.ErrChannelsNotFound,
 		},
 		{name: "GetChannelsByPage failed with some store error",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 0).Return(nil, fmt.Errorf("some store error"))
+				channelRepo.On("GetChannelsByPage", mock.Anything, 0).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -228,7 +307,7 @@ func TestChannelService_GetChannelsByPage(t *testing.T) {
 			channelService := service.NewChannelService(&store.Store{Channel: channelRepo}, logger, messageService)
 			tt.mock(channelRepo)
 
-			got, err := channelService.GetChannelsByPage(tt.input)
+			got, err := channelService.GetChannelsByPage(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -252,7 +331,7 @@ func TestChannelService_GetChannelByName(t *testing.T) {
 		{
 			name: "GetChannelByName successful",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(&model.Channel{
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(&model.Channel{
 					ID:       1,
 					Name:     "test",
 					Title:    "test",
@@ -265,7 +344,7 @@ func TestChannelService_GetChannelByName(t *testing.T) {
 		{
 			name: "GetChannelByName failed with not found channel",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(nil, nil)
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(nil, nil)
 			},
 			input:         "test",
 			expectedError: service.ErrChannelNotFound,
@@ -273,7 +352,7 @@ func TestChannelService_GetChannelByName(t *testing.T) {
 		{
 			name: "GetChannelByName failed with some store error",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(nil, fmt.Errorf("some store error"))
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(nil, fmt.Errorf("some store error"))
 			},
 			input: "test",
 			expectedError: fmt.Errorf(
@@ -297,7 +376,7 @@ func TestChannelService_GetChannelByName(t *testing.T) {
 			channelService := service.NewChannelService(&store.Store{Channel: channelRepo}, logger, messageService)
 			tt.mock(channelRepo)
 
-			got, err := channelService.GetChannelByName(tt.input)
+			got, err := channelService.GetChannelByName(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -322,7 +401,7 @@ func TestChannelService_GetChannelStats(t *testing.T) {
 		{
 			name: "GetChannelStats successful",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelStats", 1).Return(&model.Stat{MessagesCount: 1, RepliesCount: 12}, nil)
+				channelRepo.On("GetChannelStats", mock.Anything, 1).Return(&model.Stat{MessagesCount: 1, RepliesCount: 12}, nil)
 			},
 			input: 1,
 			want:  &model.Stat{MessagesCount: 1, RepliesCount: 12},
@@ -330,7 +409,7 @@ func TestChannelService_GetChannelStats(t *testing.T) {
 		{
 			name: "GetChannelStats failed with some store error",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelStats", 1).Return(nil, fmt.Errorf("some store error"))
+				channelRepo.On("GetChannelStats", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -354,7 +433,7 @@ func TestChannelService_GetChannelStats(t *testing.T) {
 			channelService := service.NewChannelService(&store.Store{Channel: channelRepo}, logger, messageService)
 			tt.mock(channelRepo)
 
-			got, err := channelService.GetChannelStats(tt.input)
+			got, err := channelService.GetChannelStats(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -379,13 +458,13 @@ func TestChannelService_ProcessChannelPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage successful",
 			mock: func(channelRepo *mocks.ChannelRepo, messageRepo *mocks.MessageRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(&model.Channel{
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(&model.Channel{
 					ID:   1,
 					Name: "test",
 				}, nil)
 
-				messageRepo.On("GetMessagesCountByChannelID", 1).Return(2, nil)
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 1, 0).Return([]model.FullMessage{
+				messageRepo.On("GetMessagesCountByChannelID", mock.Anything, 1).Return(2, nil)
+				messageRepo.On("GetFullMessagesByChannelIDAndPage", mock.Anything, 1, 0).Return([]model.FullMessage{
 					{ID: 1, ChannelID: 1},
 					{ID: 2, ChannelID: 1},
 				}, nil)
@@ -407,7 +486,20 @@ func TestChannelService_ProcessChannelPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage failed with not found channel",
 			mock: func(channelRepo *mocks.ChannelRepo, messageRepo *mocks.MessageRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(nil, nil)
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(nil, nil)
+			},
+			inputChannelName: "test",
+			inputPage:        1,
+			want:             &service.LoadChannelOutput{},
+		},
+		{
+			name: "ProcessChannelPage failed with hidden channel",
+			mock: func(channelRepo *mocks.ChannelRepo, messageRepo *mocks.MessageRepo) {
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(&model.Channel{
+					ID:     1,
+					Name:   "test",
+					Hidden: true,
+				}, nil)
 			},
 			inputChannelName: "test",
 			inputPage:        1,
@@ -416,11 +508,11 @@ func TestChannelService_ProcessChannelPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage failed with not found messages count",
 			mock: func(channelRepo *mocks.ChannelRepo, messageRepo *mocks.MessageRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(&model.Channel{
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(&model.Channel{
 					ID:   1,
 					Name: "test",
 				}, nil)
-				messageRepo.On("GetMessagesCountByChannelID", 1).Return(0, nil)
+				messageRepo.On("GetMessagesCountByChannelID", mock.Anything, 1).Return(0, nil)
 			},
 			inputChannelName: "test",
 			inputPage:        1,
@@ -434,7 +526,7 @@ func TestChannelService_ProcessChannelPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage failed with some store error when get channel by name",
 			mock: func(channelRepo *mocks.ChannelRepo, messageRepo *mocks.MessageRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(nil, fmt.Errorf("some store error"))
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(nil, fmt.Errorf("some store error"))
 			},
 			inputChannelName: "test",
 			inputPage:        1,
@@ -449,11 +541,11 @@ func TestChannelService_ProcessChannelPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage failed with some store error when get messages count",
 			mock: func(channelRepo *mocks.ChannelRepo, messageRepo *mocks.MessageRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(&model.Channel{
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(&model.Channel{
 					ID:   1,
 					Name: "test",
 				}, nil)
-				messageRepo.On("GetMessagesCountByChannelID", 1).Return(0, fmt.Errorf("some store error"))
+				messageRepo.On("GetMessagesCountByChannelID", mock.Anything, 1).Return(0, fmt.Errorf("some store error"))
 			},
 			inputChannelName: "test",
 			inputPage:        1,
@@ -468,12 +560,12 @@ func TestChannelService_ProcessChannelPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage failed with some store error when get messages",
 			mock: func(channelRepo *mocks.ChannelRepo, messageRepo *mocks.MessageRepo) {
-				channelRepo.On("GetChannelByName", "test").Return(&model.Channel{
+				channelRepo.On("GetChannelByName", mock.Anything, "test").Return(&model.Channel{
 					ID:   1,
 					Name: "test",
 				}, nil)
-				messageRepo.On("GetMessagesCountByChannelID", 1).Return(1, nil)
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 1, 0).Return(nil, fmt.Errorf("some store error"))
+				messageRepo.On("GetMessagesCountByChannelID", mock.Anything, 1).Return(1, nil)
+				messageRepo.On("GetFullMessagesByChannelIDAndPage", mock.Anything, 1, 0).Return(nil, fmt.Errorf("some store error"))
 			},
 			inputChannelName: "test",
 			inputPage:        1,
@@ -501,7 +593,7 @@ func TestChannelService_ProcessChannelPage(t *testing.T) {
 			channelService := service.NewChannelService(&store.Store{Channel: channelRepo}, logger, messageService)
 			tt.mock(channelRepo, messageRepo)
 
-			got, err := channelService.ProcessChannelPage(tt.inputChannelName, tt.inputPage)
+			got, err := channelService.ProcessChannelPage(context.Background(), tt.inputChannelName, tt.inputPage)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -525,15 +617,15 @@ func TestChannelService_ProcessChannelsPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage successful",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 0).Return([]model.Channel{
+				channelRepo.On("GetChannelsByPage", mock.Anything, 0).Return([]model.Channel{
 					{ID: 1},
 					{ID: 2},
 				}, nil)
-				channelRepo.On("GetChannelStats", 1).Return(&model.Stat{
+				channelRepo.On("GetChannelStats", mock.Anything, 1).Return(&model.Stat{
 					MessagesCount: 1,
 					RepliesCount:  2,
 				}, nil)
-				channelRepo.On("GetChannelStats", 2).Return(&model.Stat{
+				channelRepo.On("GetChannelStats", mock.Anything, 2).Return(&model.Stat{
 					MessagesCount: 3,
 					RepliesCount:  2,
 				}, nil)
@@ -549,7 +641,7 @@ func TestChannelService_ProcessChannelsPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage failed with not found channels",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 0).Return(nil, nil)
+				channelRepo.On("GetChannelsByPage", mock.Anything, 0).Return(nil, nil)
 			},
 			input: 1,
 			want:  &service.LoadChannelsOutput{},
@@ -557,7 +649,7 @@ func TestChannelService_ProcessChannelsPage(t *testing.T) {
 		{
 			name: "ProcessChannelPage failed some store when get channels by page",
 			mock: func(channelRepo *mocks.ChannelRepo) {
-				channelRepo.On("GetChannelsByPage", 0).Return(nil, fmt.Errorf("some store error"))
+				channelRepo.On("GetChannelsByPage", mock.Anything, 0).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -584,7 +676,7 @@ func TestChannelService_ProcessChannelsPage(t *testing.T) {
 			channelService := service.NewChannelService(&store.Store{Channel: channelRepo}, logger, messageService)
 			tt.mock(channelRepo)
 
-			got, err := channelService.ProcessChannelsPage(tt.input)
+			got, err := channelService.ProcessChannelsPage(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+// reclassifyBatchSize is count of the messages loaded at once by ReclassifyMessages.
+const reclassifyBatchSize = 100
+
+var (
+	// fencedCodePattern finds markdown code blocks, question marks inside of them aren't counted.
+	fencedCodePattern = regexp.MustCompile("(?s)```.*?(```|$)")
+	// tracePattern finds panics and stack traces of Go, Python and Java.
+	tracePattern = regexp.MustCompile(
+		`(?m)^(panic: |goroutine \d+ \[|Traceback \(most recent call last\)|\s+at [\w.$]+\()`,
+	)
+	// indentedCodePattern finds lines indented with a tab or four spaces.
+	indentedCodePattern = regexp.MustCompile(`(?m)^(\t|    )\S`)
+	hashtagPattern      = regexp.MustCompile(`(?:^|\s)#[\p{L}\p{N}_]+`)
+	versionPattern      = regexp.MustCompile(`(?i)(?:^|\s)v?\d+\.\d+(?:\.\d+)?(?:\s|$|[,.!])`)
+)
+
+// interrogativeWords start questions in English, Russian and Ukrainian.
+var interrogativeWords = wordSet(
+	"how", "what", "why", "when", "where", "which", "who", "whom", "whose", "anyone", "anybody",
+	"как", "что", "почему", "зачем", "когда", "где", "куда", "откуда", "какой", "какая", "какие", "каким",
+	"кто", "чем", "сколько", "подскажите", "помогите",
+	"чому", "навіщо", "як", "що", "де", "коли", "хто", "який", "яка", "які", "скільки", "чи",
+	"підкажіть", "допоможіть",
+)
+
+// auxiliaryWords start questions with inversion, but statements start with them too.
+var auxiliaryWords = wordSet(
+	"is", "are", "was", "were", "do", "does", "did", "can", "could", "should", "would", "will", "has", "have",
+	"можно", "есть", "стоит", "можна", "є", "варто",
+)
+
+// helpWords are often used in questions, but they aren't enough to detect a question alone.
+var helpWords = wordSet(
+	"help", "stuck", "issue", "problem", "error", "advice", "recommend", "suggest",
+	"помогите", "подскажите", "посоветуйте", "ошибка", "ошибку", "проблема", "проблему",
+	"допоможіть", "підкажіть", "порадьте", "помилка", "помилку",
+)
+
+var announcementWords = wordSet(
+	"announce", "announcing", "announcement", "release", "released", "releases", "meetup", "webinar",
+	"conference", "vacancy", "vacancies", "hiring", "job", "giveaway", "digest", "newsletter",
+	"анонс", "релиз", "вышел", "вышла", "вышло", "митап", "вебинар", "конференция", "вакансия", "вакансии",
+	"приглашаем", "дайджест", "розыгрыш",
+	"реліз", "вийшов", "вийшла", "конференція", "вакансія", "вакансії", "запрошуємо",
+)
+
+type classifierService struct {
+	store  *store.Store
+	logger *logger.Logger
+}
+
+var _ ClassifierService = (*classifierService)(nil)
+
+func NewClassifierService(store *store.Store, logger *logger.Logger) *classifierService {
+	return &classifierService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ClassifyMessage detects kind of the message text with heuristics.
+// Question is preferred over announcement when both of them score the same.
+func (s classifierService) ClassifyMessage(text string) model.MessageKind {
+	questionScore, announcementScore := scoreMessage(text)
+
+	switch {
+	case questionScore >= 2 && questionScore >= announcementScore:
+		return model.MessageKindQuestion
+	case announcementScore >= 2:
+		return model.MessageKindAnnouncement
+	default:
+		return model.MessageKindOther
+	}
+}
+
+// ReclassifyMessages classifies all saved messages again and returns count of the messages which kind is changed.
+func (s classifierService) ReclassifyMessages(ctx context.Context) (int, error) {
+	logger := s.logger.ForContext(ctx)
+
+	var lastID, updated int
+	for {
+		messages, err := s.store.Message.GetMessagesAfterID(ctx, lastID, reclassifyBatchSize)
+		if err != nil {
+			logger.Error().Err(err).Msg("get messages after id")
+			return updated, fmt.Errorf("get messages after id from db: %w", err)
+		}
+		if messages == nil {
+			break
+		}
+
+		for _, message := range messages {
+			lastID = message.ID
+
+			kind := s.ClassifyMessage(message.Title)
+			if kind == message.Kind {
+				continue
+			}
+
+			_, err = s.store.Message.UpdateMessageKind(ctx, message.ID, kind)
+			if err != nil {
+				logger.Error().Err(err).Msg("update message kind")
+				return updated, fmt.Errorf("update message kind in db: %w", err)
+			}
+
+			updated++
+		}
+	}
+
+	logger.Info().Int("updated", updated).Msg("successfully reclassified messages")
+	return updated, nil
+}
+
+// scoreMessage returns how much the text looks like a question and like an announcement.
+func scoreMessage(text string) (int, int) {
+	hasCode := fencedCodePattern.MatchString(text) || tracePattern.MatchString(text) ||
+		indentedCodePattern.MatchString(text)
+	prose := strings.ToLower(fencedCodePattern.ReplaceAllString(text, " "))
+
+	var questionScore, announcementScore int
+
+	// Links are removed, so query strings of them aren't taken for question marks.
+	if strings.ContainsAny(linkPattern.ReplaceAllString(prose, " "), "?？؟¿") {
+		questionScore += 2
+	}
+
+	questionScore += scoreFirstWords(prose)
+
+	words := splitWords(prose)
+
+	// "ли" is the question particle of Russian, e.g. "есть ли", "знает ли кто".
+	if containsWord(words, map[string]struct{}{"ли": {}}) {
+		questionScore++
+	}
+
+	if containsWord(words, helpWords) {
+		questionScore++
+	}
+
+	if hasCode {
+		questionScore++
+	}
+
+	if containsWord(words, announcementWords) {
+		announcementScore += 2
+	}
+
+	if hashtagPattern.MatchString(prose) {
+		announcementScore++
+	}
+
+	if versionPattern.MatchString(prose) {
+		announcementScore++
+	}
+
+	if linkPattern.MatchString(prose) {
+		announcementScore++
+	}
+
+	return questionScore, announcementScore
+}
+
+// scoreFirstWords scores lines of the text which start with an interrogative or auxiliary word.
+func scoreFirstWords(text string) int {
+	var score int
+	for _, line := range strings.Split(text, "\n") {
+		words := splitWords(line)
+		if len(words) == 0 {
+			continue
+		}
+
+		if _, ok := interrogativeWords[words[0]]; ok {
+			return 2
+		}
+
+		if _, ok := auxiliaryWords[words[0]]; ok {
+			score = 1
+		}
+	}
+
+	return score
+}
+
+// splitWords splits the text by any characters except letters and digits,
+// regexp isn't used since its word boundaries don't support non-ASCII letters.
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func containsWord(words []string, set map[string]struct{}) bool {
+	for _, word := range words {
+		if _, ok := set[word]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func wordSet(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[word] = struct{}{}
+	}
+
+	return set
+}
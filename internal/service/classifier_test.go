@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+func TestClassifierService_ClassifyMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want model.MessageKind
+	}{
+		{
+			name: "ClassifyMessage question with question mark",
+			text: "Close a channel twice in Go?",
+			want: model.MessageKindQuestion,
+		},
+		{
+			name: "ClassifyMessage question with interrogative word",
+			text: "How to close a channel twice",
+			want: model.MessageKindQuestion,
+		},
+		{
+			name: "ClassifyMessage question in russian",
+			text: "Подскажите, как правильно закрыть канал",
+			want: model.MessageKindQuestion,
+		},
+		{
+			name: "ClassifyMessage question in ukrainian",
+			text: "Чи є сенс використовувати generics тут",
+			want: model.MessageKindQuestion,
+		},
+		{
+			name: "ClassifyMessage question with code block",
+			text: "Getting an error here\n```\npanic: close of closed channel\n```",
+			want: model.MessageKindQuestion,
+		},
+		{
+			name: "ClassifyMessage question mark in code block isn't counted",
+			text: "```\nx := a ? b : c\n```",
+			want: model.MessageKindOther,
+		},
+		{
+			name: "ClassifyMessage question mark in link isn't counted",
+			text: "Go 1.22 is released https://go.dev/blog?from=tg #golang",
+			want: model.MessageKindAnnouncement,
+		},
+		{
+			name: "ClassifyMessage announcement in russian",
+			text: "Приглашаем на митап гоферов в эту пятницу",
+			want: model.MessageKindAnnouncement,
+		},
+		{
+			name: "ClassifyMessage question is preferred over announcement",
+			text: "When is the next meetup?",
+			want: model.MessageKindQuestion,
+		},
+		{
+			name: "ClassifyMessage other with auxiliary word",
+			text: "Have a nice weekend everyone",
+			want: model.MessageKindOther,
+		},
+		{
+			name: "ClassifyMessage other",
+			text: "Thanks, it works now",
+			want: model.MessageKindOther,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			classifierService := service.NewClassifierService(&store.Store{}, logger.Get(&config.Config{LogLevel: "info"}))
+
+			assert.Equal(t, tt.want, classifierService.ClassifyMessage(tt.text))
+		})
+	}
+}
+
+func TestClassifierService_ReclassifyMessages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(messageRepo *mocks.MessageRepo)
+		want          int
+		expectedError error
+	}{
+		{
+			name: "ReclassifyMessages successful",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("GetMessagesAfterID", mock.Anything, 0, 100).Return([]model.DBMessage{
+					{ID: 1, Title: "How to close a channel?", Kind: model.MessageKindOther},
+					{ID: 2, Title: "Thanks", Kind: model.MessageKindOther},
+				}, nil)
+				messageRepo.On("GetMessagesAfterID", mock.Anything, 2, 100).Return(nil, nil)
+				messageRepo.On("UpdateMessageKind", mock.Anything, 1, model.MessageKindQuestion).Return(true, nil)
+			},
+			want: 1,
+		},
+		{
+			name: "ReclassifyMessages failed with some store error when get messages",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("GetMessagesAfterID", mock.Anything, 0, 100).Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("get messages after id from db: %w", fmt.Errorf("some store error")),
+		},
+		{
+			name: "ReclassifyMessages failed with some store error when update message kind",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("GetMessagesAfterID", mock.Anything, 0, 100).Return([]model.DBMessage{
+					{ID: 1, Title: "How to close a channel?", Kind: model.MessageKindOther},
+				}, nil)
+				messageRepo.On("UpdateMessageKind", mock.Anything, 1, model.MessageKindQuestion).
+					Return(false, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("update message kind in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			messageRepo := &mocks.MessageRepo{}
+			classifierService := service.NewClassifierService(
+				&store.Store{Message: messageRepo}, logger.Get(&config.Config{LogLevel: "info"}),
+			)
+			tt.mock(messageRepo)
+
+			got, err := classifierService.ReclassifyMessages(context.Background())
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			messageRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+// linkPattern finds links with or without scheme, e.g. "https://t.me/channel" or "bit.ly/abc".
+var linkPattern = regexp.MustCompile(`(?i)(?:https?://)?(?:[a-z0-9-]+\.)+[a-z]{2,}(?:/\S*)?`)
+
+type filterService struct {
+	global   filterRules
+	channels map[string]filterRules
+}
+
+// filterRules are the rules of the global set or of the channel, phrases and links are in lower case.
+type filterRules struct {
+	disabled  bool
+	minLength int
+	phrases   []string
+	links     []string
+	patterns  []*regexp.Regexp
+}
+
+var _ FilterService = (*filterService)(nil)
+
+// NewFilterService compiles the rules, error is returned when any of the patterns is invalid.
+func NewFilterService(rules *model.FilterRules) (*filterService, error) {
+	global, err := compileFilterRules(filterRules{}, rules.FilterRuleSet)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := make(map[string]filterRules, len(rules.Channels))
+	for name, ruleSet := range rules.Channels {
+		channels[strings.ToLower(name)], err = compileFilterRules(global, ruleSet)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %w", name, err)
+		}
+	}
+
+	return &filterService{
+		global:   global,
+		channels: channels,
+	}, nil
+}
+
+// LoadFilterRules reads the rules from JSON file, no rules are returned when path is empty.
+func LoadFilterRules(path string) (*model.FilterRules, error) {
+	var rules model.FilterRules
+
+	if path == "" {
+		return &rules, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read filter rules: %w", err)
+	}
+
+	err = json.Unmarshal(data, &rules)
+	if err != nil {
+		return nil, fmt.Errorf("parse filter rules: %w", err)
+	}
+
+	return &rules, nil
+}
+
+// FilterMessage checks the message of the channel and returns the verdict when any rule matches it.
+func (s filterService) FilterMessage(channelName string, message *model.DBMessage) *FilterVerdict {
+	rules, ok := s.channels[strings.ToLower(channelName)]
+	if !ok {
+		rules = s.global
+	}
+
+	if rules.disabled {
+		return nil
+	}
+
+	text := strings.TrimSpace(message.Title)
+
+	if message.ImageURL == "" && utf8.RuneCountInString(text) < rules.minLength {
+		return &FilterVerdict{
+			Rule:   model.FilterRuleMinLength,
+			Reason: fmt.Sprintf("message is shorter than %d characters", rules.minLength),
+		}
+	}
+
+	lowerText := strings.ToLower(text)
+
+	for _, phrase := range rules.phrases {
+		if strings.Contains(lowerText, phrase) {
+			return &FilterVerdict{Rule: model.FilterRuleBlockedPhrase, Reason: fmt.Sprintf("blocked phrase %q", phrase)}
+		}
+	}
+
+	if len(rules.links) != 0 {
+		for _, link := range linkPattern.FindAllString(lowerText, -1) {
+			host := linkHost(link)
+
+			for _, domain := range rules.links {
+				if host == domain || strings.HasSuffix(host, "."+domain) {
+					return &FilterVerdict{Rule: model.FilterRuleBlockedLink, Reason: fmt.Sprintf("blocked link %q", link)}
+				}
+			}
+		}
+	}
+
+	for _, pattern := range rules.patterns {
+		if pattern.MatchString(text) {
+			return &FilterVerdict{Rule: model.FilterRulePattern, Reason: fmt.Sprintf("pattern %q", pattern.String())}
+		}
+	}
+
+	return nil
+}
+
+// compileFilterRules extends the base rules with the rule set.
+func compileFilterRules(base filterRules, ruleSet model.FilterRuleSet) (filterRules, error) {
+	rules := filterRules{
+		disabled:  ruleSet.Disabled,
+		minLength: base.minLength,
+		phrases:   append([]string{}, base.phrases...),
+		links:     append([]string{}, base.links...),
+		patterns:  append([]*regexp.Regexp{}, base.patterns...),
+	}
+
+	if ruleSet.MinLength != nil {
+		rules.minLength = *ruleSet.MinLength
+	}
+
+	for _, phrase := range ruleSet.BlockedPhrases {
+		rules.phrases = append(rules.phrases, strings.ToLower(phrase))
+	}
+
+	for _, link := range ruleSet.BlockedLinks {
+		rules.links = append(rules.links, strings.TrimPrefix(strings.ToLower(link), "www."))
+	}
+
+	for _, pattern := range ruleSet.Patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return filterRules{}, fmt.Errorf("compile filter pattern: %w", err)
+		}
+
+		rules.patterns = append(rules.patterns, compiled)
+	}
+
+	return rules, nil
+}
+
+// linkHost returns the host of the link found in the text.
+func linkHost(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(parsed.Hostname(), "www.")
+}
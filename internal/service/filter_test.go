@@ -0,0 +1,139 @@
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterService_FilterMessage(t *testing.T) {
+	t.Parallel()
+
+	minLength, channelMinLength := 10, 3
+
+	filterService, err := service.NewFilterService(&model.FilterRules{
+		FilterRuleSet: model.FilterRuleSet{
+			MinLength:      &minLength,
+			BlockedPhrases: []string{"Buy Now"},
+			BlockedLinks:   []string{"bit.ly"},
+			Patterns:       []string{`(?i)^(hi|hello)\b`},
+		},
+		Channels: map[string]model.FilterRuleSet{
+			"Short":   {MinLength: &channelMinLength, BlockedPhrases: []string{"crypto"}},
+			"trusted": {Disabled: true},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		channelName string
+		message     *model.DBMessage
+		want        *service.FilterVerdict
+	}{
+		{
+			name:        "FilterMessage passed message",
+			channelName: "go",
+			message:     &model.DBMessage{Title: "How to close a channel twice?"},
+		},
+		{
+			name:        "FilterMessage filtered short message",
+			channelName: "go",
+			message:     &model.DBMessage{Title: " ok  "},
+			want: &service.FilterVerdict{
+				Rule: model.FilterRuleMinLength, Reason: "message is shorter than 10 characters",
+			},
+		},
+		{
+			name:        "FilterMessage passed short message with image",
+			channelName: "go",
+			message:     &model.DBMessage{Title: "why?", ImageURL: "https://example.com/image.png"},
+		},
+		{
+			name:        "FilterMessage filtered blocked phrase",
+			channelName: "go",
+			message:     &model.DBMessage{Title: "Course for gophers, BUY NOW!"},
+			want:        &service.FilterVerdict{Rule: model.FilterRuleBlockedPhrase, Reason: `blocked phrase "buy now"`},
+		},
+		{
+			name:        "FilterMessage filtered blocked link",
+			channelName: "go",
+			message:     &model.DBMessage{Title: "Best course: https://www.bit.ly/abc"},
+			want: &service.FilterVerdict{
+				Rule: model.FilterRuleBlockedLink, Reason: `blocked link "https://www.bit.ly/abc"`,
+			},
+		},
+		{
+			name:        "FilterMessage passed link with similar domain",
+			channelName: "go",
+			message:     &model.DBMessage{Title: "Docs are at notbit.ly/abc"},
+		},
+		{
+			name:        "FilterMessage filtered pattern",
+			channelName: "go",
+			message:     &model.DBMessage{Title: "Hello everyone in the chat"},
+			want:        &service.FilterVerdict{Rule: model.FilterRulePattern, Reason: `pattern "(?i)^(hi|hello)\\b"`},
+		},
+		{
+			name:        "FilterMessage passed short message with channel min length",
+			channelName: "short",
+			message:     &model.DBMessage{Title: "why?"},
+		},
+		{
+			name:        "FilterMessage filtered channel phrase",
+			channelName: "short",
+			message:     &model.DBMessage{Title: "crypto signals"},
+			want:        &service.FilterVerdict{Rule: model.FilterRuleBlockedPhrase, Reason: `blocked phrase "crypto"`},
+		},
+		{
+			name:        "FilterMessage passed message of channel with disabled filter",
+			channelName: "trusted",
+			message:     &model.DBMessage{Title: "hi"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := filterService.FilterMessage(tt.channelName, tt.message)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewFilterService(t *testing.T) {
+	t.Parallel()
+
+	_, err := service.NewFilterService(&model.FilterRules{
+		Channels: map[string]model.FilterRuleSet{"go": {Patterns: []string{"("}}},
+	})
+	assert.Error(t, err)
+}
+
+func TestLoadFilterRules(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rules.json")
+
+	err := os.WriteFile(path, []byte(`{"minLength": 5, "channels": {"go": {"blockedLinks": ["t.me"]}}}`), 0o600)
+	require.NoError(t, err)
+
+	minLength := 5
+
+	rules, err := service.LoadFilterRules(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.FilterRules{
+		FilterRuleSet: model.FilterRuleSet{MinLength: &minLength},
+		Channels:      map[string]model.FilterRuleSet{"go": {BlockedLinks: []string{"t.me"}}},
+	}, rules)
+
+	rules, err = service.LoadFilterRules("")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.FilterRules{}, rules)
+}
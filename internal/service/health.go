@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+type healthService struct {
+	store  *store.Store
+	logger *logger.Logger
+}
+
+var _ HealthService = (*healthService)(nil)
+
+func NewHealthService(store *store.Store, logger *logger.Logger) *healthService {
+	return &healthService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// CheckDatabase checks database connectivity and returns version of the applied migrations.
+func (s healthService) CheckDatabase(ctx context.Context) (*model.MigrationVersion, error) {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.store.Health.Ping(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("ping db")
+		return nil, fmt.Errorf("ping db: %w", err)
+	}
+
+	version, err := s.store.Health.GetMigrationVersion(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("get migration version")
+		return nil, fmt.Errorf("get migration version from db: %w", err)
+	}
+
+	if version == nil {
+		logger.Info().Msg("migration version not found")
+		return nil, ErrMigrationVersionNotFound
+	}
+
+	return version, nil
+}
@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+func TestHealthService_CheckDatabase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(healthRepo *mocks.HealthRepo)
+		want          *model.MigrationVersion
+		expectedError error
+	}{
+		{
+			name: "CheckDatabase successful",
+			mock: func(healthRepo *mocks.HealthRepo) {
+				healthRepo.On("Ping", mock.Anything).Return(nil)
+				healthRepo.On("GetMigrationVersion", mock.Anything).Return(&model.MigrationVersion{Version: 3}, nil)
+			},
+			want: &model.MigrationVersion{Version: 3},
+		},
+		{
+			name: "CheckDatabase failed with unavailable db",
+			mock: func(healthRepo *mocks.HealthRepo) {
+				healthRepo.On("Ping", mock.Anything).Return(fmt.Errorf("connection refused"))
+			},
+			expectedError: fmt.Errorf("ping db: %w", fmt.Errorf("connection refused")),
+		},
+		{
+			name: "CheckDatabase failed with not found migration version",
+			mock: func(healthRepo *mocks.HealthRepo) {
+				healthRepo.On("Ping", mock.Anything).Return(nil)
+				healthRepo.On("GetMigrationVersion", mock.Anything).Return(nil, nil)
+			},
+			expectedError: service.ErrMigrationVersionNotFound,
+		},
+		{
+			name: "CheckDatabase failed with some store error",
+			mock: func(healthRepo *mocks.HealthRepo) {
+				healthRepo.On("Ping", mock.Anything).Return(nil)
+				healthRepo.On("GetMigrationVersion", mock.Anything).Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("get migration version from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			healthRepo := &mocks.HealthRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			healthService := service.NewHealthService(&store.Store{Health: healthRepo}, logger)
+			tt.mock(healthRepo)
+
+			got, err := healthService.CheckDatabase(context.Background())
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			healthRepo.AssertExpectations(t)
+		})
+	}
+}
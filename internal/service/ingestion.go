@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+type ingestionService struct {
+	store  *store.Store
+	logger *logger.Logger
+}
+
+var _ IngestionService = (*ingestionService)(nil)
+
+func NewIngestionService(store *store.Store, logger *logger.Logger) *ingestionService {
+	return &ingestionService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// RecordIngestionError keeps the record which failed ingestion, so it can be reprocessed later.
+func (s ingestionService) RecordIngestionError(ctx context.Context, ingestionError *model.IngestionError) error {
+	logger := s.logger.ForContext(ctx)
+
+	if ingestionError.CreatedAt.IsZero() {
+		ingestionError.CreatedAt = time.Now().UTC()
+	}
+
+	err := s.store.IngestionError.CreateIngestionError(ctx, ingestionError)
+	if err != nil {
+		logger.Error().Err(err).Msg("create ingestion error")
+		return fmt.Errorf("create ingestion error in db: %w", err)
+	}
+
+	return nil
+}
+
+func (s ingestionService) GetIngestionErrors(ctx context.Context, limit int) ([]model.IngestionError, error) {
+	logger := s.logger.ForContext(ctx)
+
+	ingestionErrors, err := s.store.IngestionError.GetIngestionErrors(ctx, limit)
+	if err != nil {
+		logger.Error().Err(err).Msg("get ingestion errors")
+		return nil, fmt.Errorf("get ingestion errors from db: %w", err)
+	}
+
+	if ingestionErrors == nil {
+		logger.Info().Msg("ingestion errors not found")
+		return nil, ErrIngestionErrorsNotFound
+	}
+
+	return ingestionErrors, nil
+}
+
+func (s ingestionService) GetIngestionError(ctx context.Context, id int) (*model.IngestionError, error) {
+	logger := s.logger.ForContext(ctx)
+
+	ingestionError, err := s.store.IngestionError.GetIngestionErrorByID(ctx, id)
+	if err != nil {
+		logger.Error().Err(err).Msg("get ingestion error by id")
+		return nil, fmt.Errorf("get ingestion error by id from db: %w", err)
+	}
+
+	if ingestionError == nil {
+		logger.Info().Int("id", id).Msg("ingestion error not found")
+		return nil, ErrIngestionErrorNotFound
+	}
+
+	return ingestionError, nil
+}
+
+func (s ingestionService) DeleteIngestionError(ctx context.Context, id int) error {
+	logger := s.logger.ForContext(ctx)
+
+	deleted, err := s.store.IngestionError.DeleteIngestionError(ctx, id)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete ingestion error")
+		return fmt.Errorf("delete ingestion error from db: %w", err)
+	}
+
+	if !deleted {
+		logger.Info().Int("id", id).Msg("ingestion error not found")
+		return ErrIngestionErrorNotFound
+	}
+
+	logger.Info().Int("id", id).Msg("ingestion error successfully deleted")
+	return nil
+}
+
+// BufferRecord keeps the record of the disabled channel, so it can be replayed when the channel is enabled.
+func (s ingestionService) BufferRecord(ctx context.Context, record *model.BufferedRecord) error {
+	logger := s.logger.ForContext(ctx)
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now().UTC()
+	}
+
+	err := s.store.BufferedRecord.CreateBufferedRecord(ctx, record)
+	if err != nil {
+		logger.Error().Err(err).Msg("create buffered record")
+		return fmt.Errorf("create buffered record in db: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimBufferedRecord removes the oldest buffered record of the channel from the buffer and returns it.
+func (s ingestionService) ClaimBufferedRecord(ctx context.Context, channelID int) (*model.BufferedRecord, error) {
+	logger := s.logger.ForContext(ctx)
+
+	record, err := s.store.BufferedRecord.ClaimBufferedRecord(ctx, channelID)
+	if err != nil {
+		logger.Error().Err(err).Msg("claim buffered record")
+		return nil, fmt.Errorf("claim buffered record from db: %w", err)
+	}
+
+	if record == nil {
+		logger.Info().Int("channel id", channelID).Msg("buffered records not found")
+		return nil, ErrBufferedRecordsNotFound
+	}
+
+	return record, nil
+}
+
+// QuarantineMessage keeps the message record caught by the filter, so an admin can review it.
+func (s ingestionService) QuarantineMessage(ctx context.Context, message *model.QuarantinedMessage) error {
+	logger := s.logger.ForContext(ctx)
+
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = time.Now().UTC()
+	}
+
+	err := s.store.Quarantine.CreateQuarantinedMessage(ctx, message)
+	if err != nil {
+		logger.Error().Err(err).Msg("create quarantined message")
+		return fmt.Errorf("create quarantined message in db: %w", err)
+	}
+
+	return nil
+}
+
+func (s ingestionService) GetQuarantinedMessage(ctx context.Context, id int) (*model.QuarantinedMessage, error) {
+	logger := s.logger.ForContext(ctx)
+
+	message, err := s.store.Quarantine.GetQuarantinedMessageByID(ctx, id)
+	if err != nil {
+		logger.Error().Err(err).Msg("get quarantined message by id")
+		return nil, fmt.Errorf("get quarantined message by id from db: %w", err)
+	}
+
+	if message == nil {
+		logger.Info().Int("id", id).Msg("quarantined message not found")
+		return nil, ErrQuarantinedMessageNotFound
+	}
+
+	return message, nil
+}
+
+func (s ingestionService) DeleteQuarantinedMessage(ctx context.Context, id int) error {
+	logger := s.logger.ForContext(ctx)
+
+	deleted, err := s.store.Quarantine.DeleteQuarantinedMessage(ctx, id)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete quarantined message")
+		return fmt.Errorf("delete quarantined message from db: %w", err)
+	}
+
+	if !deleted {
+		logger.Info().Int("id", id).Msg("quarantined message not found")
+		return ErrQuarantinedMessageNotFound
+	}
+
+	logger.Info().Int("id", id).Msg("quarantined message successfully deleted")
+	return nil
+}
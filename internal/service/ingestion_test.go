@@ -0,0 +1,457 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIngestionService_RecordIngestionError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(ingestionErrorRepo *mocks.IngestionErrorRepo)
+		expectedError error
+	}{
+		{
+			name: "RecordIngestionError successful",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("CreateIngestionError", mock.Anything, mock.MatchedBy(func(e *model.IngestionError) bool {
+					return e.Topic == "messages" && !e.CreatedAt.IsZero()
+				})).Return(nil)
+			},
+		},
+		{
+			name: "RecordIngestionError failed with some store error",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("CreateIngestionError", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("create ingestion error in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ingestionErrorRepo := &mocks.IngestionErrorRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{IngestionError: ingestionErrorRepo}, logger)
+			tt.mock(ingestionErrorRepo)
+
+			err := ingestionService.RecordIngestionError(
+				context.Background(), &model.IngestionError{Topic: "messages", Reason: "invalid"},
+			)
+			assert.Equal(t, tt.expectedError, err)
+
+			ingestionErrorRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestionService_GetIngestionErrors(t *testing.T) {
+	t.Parallel()
+
+	data := []model.IngestionError{{ID: 1, Topic: "messages", Reason: "invalid"}}
+
+	tests := []struct {
+		name          string
+		mock          func(ingestionErrorRepo *mocks.IngestionErrorRepo)
+		want          []model.IngestionError
+		expectedError error
+	}{
+		{
+			name: "GetIngestionErrors successful",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("GetIngestionErrors", mock.Anything, 20).Return(data, nil)
+			},
+			want: data,
+		},
+		{
+			name: "GetIngestionErrors failed with not found errors",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("GetIngestionErrors", mock.Anything, 20).Return(nil, nil)
+			},
+			expectedError: service.ErrIngestionErrorsNotFound,
+		},
+		{
+			name: "GetIngestionErrors failed with some store error",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("GetIngestionErrors", mock.Anything, 20).Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("get ingestion errors from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ingestionErrorRepo := &mocks.IngestionErrorRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{IngestionError: ingestionErrorRepo}, logger)
+			tt.mock(ingestionErrorRepo)
+
+			got, err := ingestionService.GetIngestionErrors(context.Background(), 20)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			ingestionErrorRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestionService_GetIngestionError(t *testing.T) {
+	t.Parallel()
+
+	data := &model.IngestionError{ID: 1, Topic: "messages", Reason: "invalid"}
+
+	tests := []struct {
+		name          string
+		mock          func(ingestionErrorRepo *mocks.IngestionErrorRepo)
+		want          *model.IngestionError
+		expectedError error
+	}{
+		{
+			name: "GetIngestionError successful",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("GetIngestionErrorByID", mock.Anything, 1).Return(data, nil)
+			},
+			want: data,
+		},
+		{
+			name: "GetIngestionError failed with not found error",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("GetIngestionErrorByID", mock.Anything, 1).Return(nil, nil)
+			},
+			expectedError: service.ErrIngestionErrorNotFound,
+		},
+		{
+			name: "GetIngestionError failed with some store error",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("GetIngestionErrorByID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("get ingestion error by id from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ingestionErrorRepo := &mocks.IngestionErrorRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{IngestionError: ingestionErrorRepo}, logger)
+			tt.mock(ingestionErrorRepo)
+
+			got, err := ingestionService.GetIngestionError(context.Background(), 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			ingestionErrorRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestionService_DeleteIngestionError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(ingestionErrorRepo *mocks.IngestionErrorRepo)
+		expectedError error
+	}{
+		{
+			name: "DeleteIngestionError successful",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("DeleteIngestionError", mock.Anything, 1).Return(true, nil)
+			},
+		},
+		{
+			name: "DeleteIngestionError failed with not found error",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("DeleteIngestionError", mock.Anything, 1).Return(false, nil)
+			},
+			expectedError: service.ErrIngestionErrorNotFound,
+		},
+		{
+			name: "DeleteIngestionError failed with some store error",
+			mock: func(ingestionErrorRepo *mocks.IngestionErrorRepo) {
+				ingestionErrorRepo.On("DeleteIngestionError", mock.Anything, 1).Return(false, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("delete ingestion error from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ingestionErrorRepo := &mocks.IngestionErrorRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{IngestionError: ingestionErrorRepo}, logger)
+			tt.mock(ingestionErrorRepo)
+
+			err := ingestionService.DeleteIngestionError(context.Background(), 1)
+			assert.Equal(t, tt.expectedError, err)
+
+			ingestionErrorRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestionService_BufferRecord(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(bufferedRecordRepo *mocks.BufferedRecordRepo)
+		expectedError error
+	}{
+		{
+			name: "BufferRecord successful",
+			mock: func(bufferedRecordRepo *mocks.BufferedRecordRepo) {
+				bufferedRecordRepo.On("CreateBufferedRecord", mock.Anything, mock.MatchedBy(func(r *model.BufferedRecord) bool {
+					return r.ChannelID == 1 && !r.CreatedAt.IsZero()
+				})).Return(nil)
+			},
+		},
+		{
+			name: "BufferRecord failed with some store error",
+			mock: func(bufferedRecordRepo *mocks.BufferedRecordRepo) {
+				bufferedRecordRepo.On("CreateBufferedRecord", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("create buffered record in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bufferedRecordRepo := &mocks.BufferedRecordRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{BufferedRecord: bufferedRecordRepo}, logger)
+			tt.mock(bufferedRecordRepo)
+
+			err := ingestionService.BufferRecord(context.Background(), &model.BufferedRecord{ChannelID: 1})
+			assert.Equal(t, tt.expectedError, err)
+
+			bufferedRecordRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestionService_ClaimBufferedRecord(t *testing.T) {
+	t.Parallel()
+
+	record := &model.BufferedRecord{ID: 1, ChannelID: 1, Topic: "messages"}
+
+	tests := []struct {
+		name          string
+		mock          func(bufferedRecordRepo *mocks.BufferedRecordRepo)
+		want          *model.BufferedRecord
+		expectedError error
+	}{
+		{
+			name: "ClaimBufferedRecord successful",
+			mock: func(bufferedRecordRepo *mocks.BufferedRecordRepo) {
+				bufferedRecordRepo.On("ClaimBufferedRecord", mock.Anything, 1).Return(record, nil)
+			},
+			want: record,
+		},
+		{
+			name: "ClaimBufferedRecord failed with not found records",
+			mock: func(bufferedRecordRepo *mocks.BufferedRecordRepo) {
+				bufferedRecordRepo.On("ClaimBufferedRecord", mock.Anything, 1).Return(nil, nil)
+			},
+			expectedError: service.ErrBufferedRecordsNotFound,
+		},
+		{
+			name: "ClaimBufferedRecord failed with some store error",
+			mock: func(bufferedRecordRepo *mocks.BufferedRecordRepo) {
+				bufferedRecordRepo.On("ClaimBufferedRecord", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("claim buffered record from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			bufferedRecordRepo := &mocks.BufferedRecordRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{BufferedRecord: bufferedRecordRepo}, logger)
+			tt.mock(bufferedRecordRepo)
+
+			got, err := ingestionService.ClaimBufferedRecord(context.Background(), 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			bufferedRecordRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestionService_QuarantineMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(quarantineRepo *mocks.QuarantineRepo)
+		expectedError error
+	}{
+		{
+			name: "QuarantineMessage successful",
+			mock: func(quarantineRepo *mocks.QuarantineRepo) {
+				quarantineRepo.On("CreateQuarantinedMessage", mock.Anything, mock.MatchedBy(func(m *model.QuarantinedMessage) bool {
+					return m.ChannelID == 1 && !m.CreatedAt.IsZero()
+				})).Return(nil)
+			},
+		},
+		{
+			name: "QuarantineMessage failed with some store error",
+			mock: func(quarantineRepo *mocks.QuarantineRepo) {
+				quarantineRepo.On("CreateQuarantinedMessage", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("create quarantined message in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			quarantineRepo := &mocks.QuarantineRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{Quarantine: quarantineRepo}, logger)
+			tt.mock(quarantineRepo)
+
+			err := ingestionService.QuarantineMessage(context.Background(), &model.QuarantinedMessage{ChannelID: 1})
+			assert.Equal(t, tt.expectedError, err)
+
+			quarantineRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestionService_GetQuarantinedMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(quarantineRepo *mocks.QuarantineRepo)
+		want          *model.QuarantinedMessage
+		expectedError error
+	}{
+		{
+			name: "GetQuarantinedMessage successful",
+			mock: func(quarantineRepo *mocks.QuarantineRepo) {
+				quarantineRepo.On("GetQuarantinedMessageByID", mock.Anything, 1).
+					Return(&model.QuarantinedMessage{ID: 1, ChannelID: 1}, nil)
+			},
+			want: &model.QuarantinedMessage{ID: 1, ChannelID: 1},
+		},
+		{
+			name: "GetQuarantinedMessage failed with not found message",
+			mock: func(quarantineRepo *mocks.QuarantineRepo) {
+				quarantineRepo.On("GetQuarantinedMessageByID", mock.Anything, 1).Return(nil, nil)
+			},
+			expectedError: service.ErrQuarantinedMessageNotFound,
+		},
+		{
+			name: "GetQuarantinedMessage failed with some store error",
+			mock: func(quarantineRepo *mocks.QuarantineRepo) {
+				quarantineRepo.On("GetQuarantinedMessageByID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("get quarantined message by id from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			quarantineRepo := &mocks.QuarantineRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{Quarantine: quarantineRepo}, logger)
+			tt.mock(quarantineRepo)
+
+			got, err := ingestionService.GetQuarantinedMessage(context.Background(), 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			quarantineRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIngestionService_DeleteQuarantinedMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(quarantineRepo *mocks.QuarantineRepo)
+		expectedError error
+	}{
+		{
+			name: "DeleteQuarantinedMessage successful",
+			mock: func(quarantineRepo *mocks.QuarantineRepo) {
+				quarantineRepo.On("DeleteQuarantinedMessage", mock.Anything, 1).Return(true, nil)
+			},
+		},
+		{
+			name: "DeleteQuarantinedMessage failed with not found message",
+			mock: func(quarantineRepo *mocks.QuarantineRepo) {
+				quarantineRepo.On("DeleteQuarantinedMessage", mock.Anything, 1).Return(false, nil)
+			},
+			expectedError: service.ErrQuarantinedMessageNotFound,
+		},
+		{
+			name: "DeleteQuarantinedMessage failed with some store error",
+			mock: func(quarantineRepo *mocks.QuarantineRepo) {
+				quarantineRepo.On("DeleteQuarantinedMessage", mock.Anything, 1).Return(false, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("delete quarantined message from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			quarantineRepo := &mocks.QuarantineRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			ingestionService := service.NewIngestionService(&store.Store{Quarantine: quarantineRepo}, logger)
+			tt.mock(quarantineRepo)
+
+			err := ingestionService.DeleteQuarantinedMessage(context.Background(), 1)
+			assert.Equal(t, tt.expectedError, err)
+
+			quarantineRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+// maxLockoutDoublings limits growth of the lockout, failures are forgotten after the window anyway.
+const maxLockoutDoublings = 16
+
+type loginAttemptService struct {
+	store  *store.Store
+	logger *logger.Logger
+	limits LoginLimits
+}
+
+var _ LoginAttemptService = (*loginAttemptService)(nil)
+
+func NewLoginAttemptService(store *store.Store, logger *logger.Logger, limits LoginLimits) *loginAttemptService {
+	return &loginAttemptService{
+		store:  store,
+		logger: logger,
+		limits: limits,
+	}
+}
+
+// CheckLoginAttempt returns ErrTooManyLoginAttempts while the email or the client address is locked out.
+// It's checked before the password, so locked out attempts don't cost hashing.
+func (s loginAttemptService) CheckLoginAttempt(ctx context.Context, email string, ipAddress string) error {
+	logger := s.logger.ForContext(ctx)
+
+	now := time.Now().UTC()
+	since := now.Add(-s.limits.Window)
+
+	emailFailures, err := s.store.LoginFailure.GetLoginFailuresByEmail(ctx, normalizeEmail(email), since)
+	if err != nil {
+		logger.Error().Err(err).Msg("get login failures by email")
+		return fmt.Errorf("get login failures by email from db: %w", err)
+	}
+
+	if now.Before(lockedUntil(emailFailures, s.limits.MaxFailures, s.limits.Lockout)) {
+		logger.Info().Str("email", email).Int("failures", emailFailures.Count).Msg("login is locked for email")
+		return ErrTooManyLoginAttempts
+	}
+
+	ipFailures, err := s.store.LoginFailure.GetLoginFailuresByIP(ctx, ipAddress, since)
+	if err != nil {
+		logger.Error().Err(err).Msg("get login failures by ip")
+		return fmt.Errorf("get login failures by ip from db: %w", err)
+	}
+
+	if now.Before(lockedUntil(ipFailures, s.limits.MaxFailuresPerIP, s.limits.Lockout)) {
+		logger.Info().Str("ip", ipAddress).Int("failures", ipFailures.Count).Msg("login is locked for ip")
+		return ErrTooManyLoginAttempts
+	}
+
+	return nil
+}
+
+func (s loginAttemptService) RecordLoginFailure(ctx context.Context, email string, ipAddress string) error {
+	logger := s.logger.ForContext(ctx)
+
+	now := time.Now().UTC()
+
+	// Old failures are cleaned up on the next failure, so the table doesn't grow without a separate job.
+	err := s.store.LoginFailure.DeleteLoginFailuresBefore(ctx, now.Add(-s.limits.Window))
+	if err != nil {
+		logger.Error().Err(err).Msg("delete old login failures")
+	}
+
+	err = s.store.LoginFailure.CreateLoginFailure(ctx, &model.LoginFailure{
+		Email:     normalizeEmail(email),
+		IPAddress: ipAddress,
+		CreatedAt: now,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("create login failure")
+		return fmt.Errorf("create login failure in db: %w", err)
+	}
+
+	logger.Info().Str("email", email).Str("ip", ipAddress).Msg("login failure successfully recorded")
+	return nil
+}
+
+// ResetLoginFailures forgets failures of the email after successful login, failures of the address are kept.
+func (s loginAttemptService) ResetLoginFailures(ctx context.Context, email string) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.store.LoginFailure.DeleteLoginFailuresByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		logger.Error().Err(err).Msg("delete login failures by email")
+		return fmt.Errorf("delete login failures by email from db: %w", err)
+	}
+
+	return nil
+}
+
+// lockedUntil returns the end of the lockout, the lockout is doubled with every failure over the limit.
+func lockedUntil(failures *model.LoginFailureStats, maxFailures int, lockout time.Duration) time.Time {
+	if maxFailures <= 0 || failures.Count < maxFailures {
+		return time.Time{}
+	}
+
+	doublings := failures.Count - maxFailures
+	if doublings > maxLockoutDoublings {
+		doublings = maxLockoutDoublings
+	}
+
+	return failures.LastAt.Add(lockout << doublings)
+}
+
+// normalizeEmail makes failures of the same account counted together regardless of letter case.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,133 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+func TestLoginAttemptService_CheckLoginAttempt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name          string
+		emailFailures *model.LoginFailureStats
+		ipFailures    *model.LoginFailureStats
+		expectedError error
+	}{
+		{
+			name:          "CheckLoginAttempt successful below the limit",
+			emailFailures: &model.LoginFailureStats{Count: 4, LastAt: now},
+			ipFailures:    &model.LoginFailureStats{Count: 19, LastAt: now},
+		},
+		{
+			name:          "CheckLoginAttempt successful after the first lockout",
+			emailFailures: &model.LoginFailureStats{Count: 5, LastAt: now.Add(-61 * time.Second)},
+			ipFailures:    &model.LoginFailureStats{},
+		},
+		{
+			name:          "CheckLoginAttempt failed with doubled lockout",
+			emailFailures: &model.LoginFailureStats{Count: 7, LastAt: now.Add(-3 * time.Minute)},
+			expectedError: service.ErrTooManyLoginAttempts,
+		},
+		{
+			name:          "CheckLoginAttempt failed with locked out ip",
+			emailFailures: &model.LoginFailureStats{},
+			ipFailures:    &model.LoginFailureStats{Count: 21, LastAt: now.Add(-time.Minute)},
+			expectedError: service.ErrTooManyLoginAttempts,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			loginFailureRepo := &mocks.LoginFailureRepo{}
+			loginFailureRepo.On("GetLoginFailuresByEmail", mock.Anything, "test@test.com", mock.Anything).
+				Return(tt.emailFailures, nil)
+			if tt.ipFailures != nil {
+				loginFailureRepo.On("GetLoginFailuresByIP", mock.Anything, "127.0.0.1", mock.Anything).
+					Return(tt.ipFailures, nil)
+			}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			loginAttemptService := service.NewLoginAttemptService(
+				&store.Store{LoginFailure: loginFailureRepo}, logger, loginLimits,
+			)
+
+			err := loginAttemptService.CheckLoginAttempt(context.Background(), "test@test.com", "127.0.0.1")
+			assert.Equal(t, tt.expectedError, err)
+
+			loginFailureRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoginAttemptService_RecordLoginFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(loginFailureRepo *mocks.LoginFailureRepo)
+		expectedError error
+	}{
+		{
+			name: "RecordLoginFailure successful",
+			mock: func(loginFailureRepo *mocks.LoginFailureRepo) {
+				loginFailureRepo.On("DeleteLoginFailuresBefore", mock.Anything, mock.Anything).Return(nil)
+				loginFailureRepo.On("CreateLoginFailure", mock.Anything, mock.MatchedBy(func(failure *model.LoginFailure) bool {
+					return failure.Email == "test@test.com" && failure.IPAddress == "127.0.0.1" && !failure.CreatedAt.IsZero()
+				})).Return(nil)
+			},
+		},
+		{
+			name: "RecordLoginFailure successful when delete old failures failed",
+			mock: func(loginFailureRepo *mocks.LoginFailureRepo) {
+				loginFailureRepo.On("DeleteLoginFailuresBefore", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("some store error"))
+				loginFailureRepo.On("CreateLoginFailure", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "RecordLoginFailure failed with some store error",
+			mock: func(loginFailureRepo *mocks.LoginFailureRepo) {
+				loginFailureRepo.On("DeleteLoginFailuresBefore", mock.Anything, mock.Anything).Return(nil)
+				loginFailureRepo.On("CreateLoginFailure", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("create login failure in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			loginFailureRepo := &mocks.LoginFailureRepo{}
+			tt.mock(loginFailureRepo)
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			loginAttemptService := service.NewLoginAttemptService(
+				&store.Store{LoginFailure: loginFailureRepo}, logger, loginLimits,
+			)
+
+			err := loginAttemptService.RecordLoginFailure(context.Background(), "Test@test.com", "127.0.0.1")
+			assert.Equal(t, tt.expectedError, err)
+
+			loginFailureRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -4,20 +4,36 @@ import (
 	"fmt"
 
 	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
 	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/mailer"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
 )
 
 type Manager struct {
-	Channel ChannelService
-	Message MessageService
-	Reply   ReplyService
-	User    UserService
-	WebUser WebUserService
-	Saved   SavedService
-	Auth    AuthService
+	Channel      ChannelService
+	Message      MessageService
+	Reply        ReplyService
+	User         UserService
+	WebUser      WebUserService
+	Saved        SavedService
+	Auth         AuthService
+	LoginAttempt LoginAttemptService
+	Session      SessionService
+	Password     PasswordService
+	Verification VerificationService
+	TwoFactor    TwoFactorService
+	APIToken     APITokenService
+	Access       AccessService
+	Ingestion    IngestionService
+	Filter       FilterService
+	Classifier   ClassifierService
+	Admin        AdminService
+	Moderation   ModerationService
+	Health       HealthService
 }
 
-func NewManager(store *store.Store, logger *logger.Logger) (*Manager, error) {
+func NewManager(store *store.Store, logger *logger.Logger, cfg *config.Config, mailer mailer.Mailer) (*Manager, error) {
 	if store == nil {
 		return nil, fmt.Errorf("no store provided")
 	}
@@ -28,17 +44,83 @@ func NewManager(store *store.Store, logger *logger.Logger) (*Manager, error) {
 	channelService := NewChannelService(store, logger, messageService)
 	userService := NewUserService(store, logger, messageService)
 	savedService := NewSavedService(store, logger, messageService)
-	authService := NewAuthService(webUserService, logger)
+	loginAttemptService := NewLoginAttemptService(store, logger, LoginLimits{
+		MaxFailures:      cfg.LoginMaxFailures,
+		MaxFailuresPerIP: cfg.LoginMaxFailuresPerIP,
+		Window:           cfg.LoginFailuresWindow,
+		Lockout:          cfg.LoginLockout,
+	})
+	authService := NewAuthService(webUserService, loginAttemptService, logger)
+	sessionService := NewSessionService(store, logger, cfg.SessionTTL)
+	passwordService := NewPasswordService(store, logger, mailer, cfg.BaseURL, cfg.PasswordResetTTL)
+
+	signer, err := newSigner(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactorService := NewTwoFactorService(store, logger, signer, loginAttemptService)
+	verificationService := NewVerificationService(
+		store, logger, mailer, signer, cfg.BaseURL, cfg.VerificationTTL,
+	)
+	apiTokenService := NewAPITokenService(store, logger)
+	accessService := NewAccessService(store, logger)
+	ingestionService := NewIngestionService(store, logger)
+
+	filterRules, err := LoadFilterRules(cfg.FilterRulesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	filterService, err := NewFilterService(filterRules)
+	if err != nil {
+		return nil, fmt.Errorf("create filter service: %w", err)
+	}
+
+	classifierService := NewClassifierService(store, logger)
+	adminService := NewAdminService(store, logger, accessService, channelService, ingestionService)
+	moderationService := NewModerationService(store, logger, accessService)
+	healthService := NewHealthService(store, logger)
 
 	srvManager := &Manager{
-		Channel: channelService,
-		Message: messageService,
-		Reply:   replyService,
-		User:    userService,
-		WebUser: webUserService,
-		Saved:   savedService,
-		Auth:    authService,
+		Channel:      channelService,
+		Message:      messageService,
+		Reply:        replyService,
+		User:         userService,
+		WebUser:      webUserService,
+		Saved:        savedService,
+		Auth:         authService,
+		LoginAttempt: loginAttemptService,
+		Session:      sessionService,
+		Password:     passwordService,
+		Verification: verificationService,
+		TwoFactor:    twoFactorService,
+		APIToken:     apiTokenService,
+		Access:       accessService,
+		Ingestion:    ingestionService,
+		Filter:       filterService,
+		Classifier:   classifierService,
+		Admin:        adminService,
+		Moderation:   moderationService,
+		Health:       healthService,
 	}
 
 	return srvManager, nil
 }
+
+// newSigner returns signer of the verification links and pending logins.
+// Random key is used when it's not configured, so the tokens issued before restart stop working.
+func newSigner(cfg *config.Config, logger *logger.Logger) (*token.Signer, error) {
+	if cfg.SigningKey != "" {
+		return token.NewSigner([]byte(cfg.SigningKey)), nil
+	}
+
+	logger.Warn().Msg("SIGNING_KEY and EMAIL_VERIFICATION_KEY are not set, random key is used")
+
+	key, err := token.Generate(token.DefaultLength)
+	if err != nil {
+		return nil, fmt.Errorf("generate signing key: %w", err)
+	}
+
+	return token.NewSigner([]byte(key)), nil
+}
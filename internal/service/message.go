@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -26,10 +27,10 @@ func NewMessageService(store *store.Store, logger *logger.Logger, replyService R
 	}
 }
 
-func (s messageService) CreateMessage(message *model.DBMessage) (int, error) {
-	logger := s.logger
+func (s messageService) CreateMessage(ctx context.Context, message *model.DBMessage) (int, error) {
+	logger := s.logger.ForContext(ctx)
 
-	candidate, err := s.store.Message.GetMessageByTitle(message.Title)
+	candidate, err := s.store.Message.GetMessageByTitle(ctx, message.Title)
 	if err != nil {
 		logger.Error().Err(err).Msg("get message by title")
 		return 0, fmt.Errorf("get message by title from db: %w", err)
@@ -43,7 +44,11 @@ func (s messageService) CreateMessage(message *model.DBMessage) (int, error) {
 		return 0, ErrMessageExists
 	}
 
-	id, err := s.store.Message.CreateMessage(message)
+	if message.Kind == "" {
+		message.Kind = model.MessageKindOther
+	}
+
+	id, err := s.store.Message.CreateMessage(ctx, message)
 	if err != nil {
 		logger.Error().Err(err).Msg("create message")
 		return id, fmt.Errorf("create message in db: %w", err)
@@ -53,10 +58,10 @@ func (s messageService) CreateMessage(message *model.DBMessage) (int, error) {
 	return id, nil
 }
 
-func (s messageService) GetMessagesCount() (int, error) {
-	logger := s.logger
+func (s messageService) GetMessagesCount(ctx context.Context) (int, error) {
+	logger := s.logger.ForContext(ctx)
 
-	count, err := s.store.Message.GetMessagesCount()
+	count, err := s.store.Message.GetMessagesCount(ctx)
 	if err != nil {
 		logger.Error().Err(err).Msg("get messages count")
 		return 0, fmt.Errorf("get messages count from db: %w", err)
@@ -71,10 +76,10 @@ func (s messageService) GetMessagesCount() (int, error) {
 	return count, nil
 }
 
-func (s messageService) GetMessagesCountByChannelID(id int) (int, error) {
-	logger := s.logger
+func (s messageService) GetMessagesCountByChannelID(ctx context.Context, id int) (int, error) {
+	logger := s.logger.ForContext(ctx)
 
-	count, err := s.store.Message.GetMessagesCountByChannelID(id)
+	count, err := s.store.Message.GetMessagesCountByChannelID(ctx, id)
 	if err != nil {
 		logger.Error().Err(err).Msg("get messages count by channel id")
 		return 0, fmt.Errorf("get messages count by channel id from db: %w", err)
@@ -89,10 +94,10 @@ func (s messageService) GetMessagesCountByChannelID(id int) (int, error) {
 	return count, nil
 }
 
-func (s messageService) GetFullMessagesByChannelIDAndPage(id, page int) ([]model.FullMessage, error) {
-	logger := s.logger
+func (s messageService) GetFullMessagesByChannelIDAndPage(ctx context.Context, id, page int) ([]model.FullMessage, error) {
+	logger := s.logger.ForContext(ctx)
 
-	messages, err := s.store.Message.GetFullMessagesByChannelIDAndPage(id, convert.PageToOffset(page))
+	messages, err := s.store.Message.GetFullMessagesByChannelIDAndPage(ctx, id, convert.PageToOffset(page))
 	if err != nil {
 		logger.Error().Err(err).Msg("get full messages by channel id and page")
 		return nil, fmt.Errorf("get full messages by channel id and page from db: %w", err)
@@ -107,10 +112,10 @@ func (s messageService) GetFullMessagesByChannelIDAndPage(id, page int) ([]model
 	return messages, nil
 }
 
-func (s messageService) GetFullMessagesByUserID(id int) ([]model.FullMessage, error) {
-	logger := s.logger
+func (s messageService) GetFullMessagesByUserID(ctx context.Context, id int) ([]model.FullMessage, error) {
+	logger := s.logger.ForContext(ctx)
 
-	messages, err := s.store.Message.GetFullMessagesByUserID(id)
+	messages, err := s.store.Message.GetFullMessagesByUserID(ctx, id)
 	if err != nil {
 		logger.Error().Err(err).Msg("get full messages by user id")
 		return nil, fmt.Errorf("get full messages by user id from db: %w", err)
@@ -125,16 +130,16 @@ func (s messageService) GetFullMessagesByUserID(id int) ([]model.FullMessage, er
 	return messages, nil
 }
 
-func (s messageService) GetFullMessageByMessageID(id int) (*model.FullMessage, error) {
-	logger := s.logger
+func (s messageService) GetFullMessageByMessageID(ctx context.Context, id int) (*model.FullMessage, error) {
+	logger := s.logger.ForContext(ctx)
 
-	message, err := s.store.Message.GetFullMessageByID(id)
+	message, err := s.store.Message.GetFullMessageByID(ctx, id)
 	if err != nil {
 		logger.Error().Err(err).Msg("get full message by message id")
 		return nil, fmt.Errorf("get full message by message id from db: %w", err)
 	}
 
-	if message == nil {
+	if message == nil || message.Hidden {
 		logger.Info().Int("message id", id).Msg("message by id not found")
 		return nil, ErrMessageNotFound
 	}
@@ -143,20 +148,20 @@ func (s messageService) GetFullMessageByMessageID(id int) (*model.FullMessage, e
 	return message, nil
 }
 
-func (s messageService) ProcessMessagePage(messageID int) (*LoadMessageOutput, error) {
-	logger := s.logger
+func (s messageService) ProcessMessagePage(ctx context.Context, messageID int) (*LoadMessageOutput, error) {
+	logger := s.logger.ForContext(ctx)
 
-	message, err := s.store.Message.GetFullMessageByID(messageID)
+	message, err := s.store.Message.GetFullMessageByID(ctx, messageID)
 	if err != nil {
 		logger.Error().Err(err).Msg("get full message by id")
 		return nil, fmt.Errorf("get full message by id from db: %w", err)
 	}
-	if message == nil {
+	if message == nil || message.Hidden {
 		logger.Info().Int("message id", messageID).Msg("message by id not found")
 		return &LoadMessageOutput{}, nil
 	}
 
-	replies, err := s.reply.GetFullRepliesByMessageID(message.ID)
+	replies, err := s.reply.GetFullRepliesByMessageID(ctx, message.ID)
 	if err != nil {
 		if errors.Is(err, ErrRepliesNotFound) {
 			logger.Info().Int("message id", message.ID).Msg("full replies by message id not found")
@@ -175,10 +180,21 @@ func (s messageService) ProcessMessagePage(messageID int) (*LoadMessageOutput, e
 	return &LoadMessageOutput{Message: message}, nil
 }
 
-func (s messageService) ProcessHomePage(page int) (*LoadHomeOutput, error) {
-	logger := s.logger
-
-	messagesCount, err := s.store.Message.GetMessagesCount()
+// ProcessHomePage loads the page of the feed, only messages of the kind are loaded when it isn't empty.
+func (s messageService) ProcessHomePage(
+	ctx context.Context, page int, kind model.MessageKind,
+) (*LoadHomeOutput, error) {
+	logger := s.logger.ForContext(ctx)
+
+	var (
+		messagesCount int
+		err           error
+	)
+	if kind == "" {
+		messagesCount, err = s.store.Message.GetMessagesCount(ctx)
+	} else {
+		messagesCount, err = s.store.Message.GetMessagesCountByKind(ctx, kind)
+	}
 	if err != nil {
 		logger.Error().Err(err).Msg("get messages count")
 		return nil, fmt.Errorf("get messages count from db: %w", err)
@@ -188,7 +204,12 @@ func (s messageService) ProcessHomePage(page int) (*LoadHomeOutput, error) {
 		return &LoadHomeOutput{}, nil
 	}
 
-	messages, err := s.store.Message.GetFullMessagesByPage(convert.PageToOffset(page))
+	var messages []model.FullMessage
+	if kind == "" {
+		messages, err = s.store.Message.GetFullMessagesByPage(ctx, convert.PageToOffset(page))
+	} else {
+		messages, err = s.store.Message.GetFullMessagesByKindAndPage(ctx, kind, convert.PageToOffset(page))
+	}
 	if err != nil {
 		logger.Error().Err(err).Msg("get messages by page")
 		return nil, fmt.Errorf("get full messages by page from db: %w", err)
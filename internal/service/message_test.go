@@ -1,10 +1,12 @@
 package service_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 	"github.com/VladPetriv/scanner_backend/internal/service"
@@ -27,13 +29,14 @@ func TestMessageService_CreateMessage(t *testing.T) {
 		{
 			name: "CreateMessage successful",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessageByTitle", "test").Return(nil, nil)
-				messageRepo.On("CreateMessage", &model.DBMessage{
+				messageRepo.On("GetMessageByTitle", mock.Anything, "test").Return(nil, nil)
+				messageRepo.On("CreateMessage", mock.Anything, &model.DBMessage{
 					ChannelID:  1,
 					UserID:     1,
 					Title:      "test",
 					MessageURL: "test.url",
 					ImageURL:   "test.jpg",
+					Kind:       model.MessageKindOther,
 				}).Return(1, nil)
 			},
 			input: &model.DBMessage{
@@ -48,7 +51,7 @@ func TestMessageService_CreateMessage(t *testing.T) {
 		{
 			name: "CreateMessage failed with existed message",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessageByTitle", "test").Return(&model.DBMessage{
+				messageRepo.On("GetMessageByTitle", mock.Anything, "test").Return(&model.DBMessage{
 					ChannelID:  1,
 					UserID:     1,
 					Title:      "test",
@@ -68,7 +71,7 @@ func TestMessageService_CreateMessage(t *testing.T) {
 		{
 			name: "CreateMessage failed with some store error when get message by title",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessageByTitle", "test").Return(nil, fmt.Errorf("some store error"))
+				messageRepo.On("GetMessageByTitle", mock.Anything, "test").Return(nil, fmt.Errorf("some store error"))
 			},
 			input: &model.DBMessage{
 				ChannelID:  1,
@@ -85,13 +88,14 @@ func TestMessageService_CreateMessage(t *testing.T) {
 		{
 			name: "CreateMessage failed with some store error",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessageByTitle", "test").Return(nil, nil)
-				messageRepo.On("CreateMessage", &model.DBMessage{
+				messageRepo.On("GetMessageByTitle", mock.Anything, "test").Return(nil, nil)
+				messageRepo.On("CreateMessage", mock.Anything, &model.DBMessage{
 					ChannelID:  1,
 					UserID:     1,
 					Title:      "test",
 					MessageURL: "test.url",
 					ImageURL:   "test.jpg",
+					Kind:       model.MessageKindOther,
 				},
 				).Return(0, fmt.Errorf("some store error"))
 			},
@@ -120,7 +124,7 @@ func TestMessageService_CreateMessage(t *testing.T) {
 			messageService := service.NewMessageService(&store.Store{Message: messageRepo}, logger, replyService)
 			tt.mock(messageRepo)
 
-			got, err := messageService.CreateMessage(tt.input)
+			got, err := messageService.CreateMessage(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -142,21 +146,21 @@ func TestMessageService_GetMessagesCount(t *testing.T) {
 		{
 			name: "GetMessageCount successful",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(10, nil)
+				messageRepo.On("GetMessagesCount", mock.Anything).Return(10, nil)
 			},
 			want: 10,
 		},
 		{
 			name: "GetMessagesCount failed with not found messages count",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(0, nil)
+				messageRepo.On("GetMessagesCount", mock.Anything).Return(0, nil)
 			},
 			expectedError: service.ErrMessagesCountNotFound,
 		},
 		{
 			name: "GetMessagesCount failed with some store error",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(0, fmt.Errorf("some store error"))
+				messageRepo.On("GetMessagesCount", mock.Anything).Return(0, fmt.Errorf("some store error"))
 			},
 			expectedError: fmt.Errorf(
 				"get messages count from db: %w",
@@ -177,7 +181,7 @@ func TestMessageService_GetMessagesCount(t *testing.T) {
 			messageService := service.NewMessageService(&store.Store{Message: messageRepo}, logger, replyService)
 			tt.mock(messageRepo)
 
-			got, err := messageService.GetMessagesCount()
+			got, err := messageService.GetMessagesCount(context.Background())
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -200,7 +204,7 @@ func TestMessageService_GetMessagesCountByChannelID(t *testing.T) {
 		{
 			name: "GetMessagesCountByChannelID successful",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCountByChannelID", 1).Return(3, nil)
+				messageRepo.On("GetMessagesCountByChannelID", mock.Anything, 1).Return(3, nil)
 			},
 			input: 1,
 			want:  3,
@@ -208,7 +212,7 @@ func TestMessageService_GetMessagesCountByChannelID(t *testing.T) {
 		{
 			name: "GetMessagesCountByChannelID failed with not found messages count",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCountByChannelID", 1).Return(0, nil)
+				messageRepo.On("GetMessagesCountByChannelID", mock.Anything, 1).Return(0, nil)
 			},
 			input:         1,
 			expectedError: service.ErrMessagesCountNotFound,
@@ -216,7 +220,7 @@ func TestMessageService_GetMessagesCountByChannelID(t *testing.T) {
 		{
 			name: "GetMessagesCountByChannelID failed with some store error",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCountByChannelID", 1).
+				messageRepo.On("GetMessagesCountByChannelID", mock.Anything, 1).
 					Return(0, fmt.Errorf("some store error"))
 			},
 			input: 1,
@@ -239,7 +243,7 @@ func TestMessageService_GetMessagesCountByChannelID(t *testing.T) {
 			messageService := service.NewMessageService(&store.Store{Message: messageRepo}, logger, replyService)
 			tt.mock(messageRepo)
 
-			got, err := messageService.GetMessagesCountByChannelID(tt.input)
+			got, err := messageService.GetMessagesCountByChannelID(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -267,7 +271,7 @@ func TestMessageService_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 		{
 			name: "GetFullMessagesByChannelIDAndPage successful",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 1, 0).Return(messages, nil)
+				messageRepo.On("GetFullMessagesByChannelIDAndPage", mock.Anything, 1, 0).Return(messages, nil)
 			},
 			input: 1,
 			want:  messages,
@@ -275,7 +279,7 @@ func TestMessageService_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 		{
 			name: "GetFullMessagesByChannelIDAndPage failed with not found messages",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 1, 0).Return(nil, nil)
+				messageRepo.On("GetFullMessagesByChannelIDAndPage", mock.Anything, 1, 0).Return(nil, nil)
 			},
 			input:         1,
 			expectedError: service.ErrMessagesNotFound,
@@ -283,7 +287,7 @@ func TestMessageService_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 		{
 			name: "GetFullMessagesByChannelIDAndPage failed with some store error",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByChannelIDAndPage", 1, 0).Return(nil, fmt.Errorf("some store error"))
+				messageRepo.On("GetFullMessagesByChannelIDAndPage", mock.Anything, 1, 0).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -304,7 +308,7 @@ func TestMessageService_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 			messageService := service.NewMessageService(&store.Store{Message: messageRepo}, logger, replyService)
 			tt.mock(messageRepo)
 
-			got, err := messageService.GetFullMessagesByChannelIDAndPage(tt.input, 1)
+			got, err := messageService.GetFullMessagesByChannelIDAndPage(context.Background(), tt.input, 1)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -332,7 +336,7 @@ func TestMessageService_GetFullMessagesByUserID(t *testing.T) {
 		{
 			name: "GetFullMessagesByUserID successful",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByUserID", 1).Return(messages, nil)
+				messageRepo.On("GetFullMessagesByUserID", mock.Anything, 1).Return(messages, nil)
 			},
 			input: 1,
 			want:  messages,
@@ -340,7 +344,7 @@ func TestMessageService_GetFullMessagesByUserID(t *testing.T) {
 		{
 			name: "GetFullMessagesByUserID failed with not found messages",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByUserID", 1).Return(nil, nil)
+				messageRepo.On("GetFullMessagesByUserID", mock.Anything, 1).Return(nil, nil)
 			},
 			input:         1,
 			expectedError: service.ErrMessagesNotFound,
@@ -348,7 +352,7 @@ func TestMessageService_GetFullMessagesByUserID(t *testing.T) {
 		{
 			name: "GetFullMessagesByUserID failed with some store error",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessagesByUserID", 1).Return(nil, fmt.Errorf("some store error"))
+				messageRepo.On("GetFullMessagesByUserID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -370,7 +374,7 @@ func TestMessageService_GetFullMessagesByUserID(t *testing.T) {
 			messageService := service.NewMessageService(&store.Store{Message: messageRepo}, logger, replyService)
 			tt.mock(messageRepo)
 
-			got, err := messageService.GetFullMessagesByUserID(tt.input)
+			got, err := messageService.GetFullMessagesByUserID(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -393,7 +397,7 @@ func Test_GetFullMessagesByMessageID(t *testing.T) {
 		{
 			name: "GetFullMessagesByMessageID successful",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessageByID", 1).Return(&model.FullMessage{
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{
 					ID:        1,
 					Title:     "test1",
 					ChannelID: 1,
@@ -409,7 +413,15 @@ func Test_GetFullMessagesByMessageID(t *testing.T) {
 		{
 			name: "GetFullMessagesByMessageID failed with not found message",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessageByID", 1).Return(nil, nil)
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(nil, nil)
+			},
+			input:         1,
+			expectedError: service.ErrMessageNotFound,
+		},
+		{
+			name: "GetFullMessagesByMessageID failed with hidden message",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{ID: 1, Hidden: true}, nil)
 			},
 			input:         1,
 			expectedError: service.ErrMessageNotFound,
@@ -417,7 +429,7 @@ func Test_GetFullMessagesByMessageID(t *testing.T) {
 		{
 			name: "GetFullMessagesByMessageID failed with some store error",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetFullMessageByID", 1).Return(nil, fmt.Errorf("some store error"))
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -439,7 +451,7 @@ func Test_GetFullMessagesByMessageID(t *testing.T) {
 			messageService := service.NewMessageService(&store.Store{Message: messageRepo}, logger, replyService)
 			tt.mock(messageRepo)
 
-			got, err := messageService.GetFullMessageByMessageID(tt.input)
+			got, err := messageService.GetFullMessageByMessageID(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -462,11 +474,11 @@ func TestMessageService_ProcessMessagePager(t *testing.T) {
 		{
 			name: "ProcessMessagePage successful",
 			mock: func(messageRepo *mocks.MessageRepo, replyRepo *mocks.ReplyRepo) {
-				messageRepo.On("GetFullMessageByID", 1).Return(&model.FullMessage{
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{
 					ID:    1,
 					Title: "test",
 				}, nil)
-				replyRepo.On("GetFullRepliesByMessageID", 1).Return([]model.FullReply{
+				replyRepo.On("GetFullRepliesByMessageID", mock.Anything, 1).Return([]model.FullReply{
 					{
 						ID:    1,
 						Title: "test 1",
@@ -499,7 +511,15 @@ func TestMessageService_ProcessMessagePager(t *testing.T) {
 		{
 			name: "ProcessMessagePage failed with not found message",
 			mock: func(messageRepo *mocks.MessageRepo, replyRepo *mocks.ReplyRepo) {
-				messageRepo.On("GetFullMessageByID", 1).Return(nil, nil)
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(nil, nil)
+			},
+			input: 1,
+			want:  &service.LoadMessageOutput{},
+		},
+		{
+			name: "ProcessMessagePage failed with hidden message",
+			mock: func(messageRepo *mocks.MessageRepo, replyRepo *mocks.ReplyRepo) {
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{ID: 1, Hidden: true}, nil)
 			},
 			input: 1,
 			want:  &service.LoadMessageOutput{},
@@ -507,7 +527,7 @@ func TestMessageService_ProcessMessagePager(t *testing.T) {
 		{
 			name: "ProcessMessagePage failed with not found replies",
 			mock: func(messageRepo *mocks.MessageRepo, replyRepo *mocks.ReplyRepo) {
-				messageRepo.On("GetFullMessageByID", 1).Return(nil, nil)
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(nil, nil)
 			},
 			input: 1,
 			want:  &service.LoadMessageOutput{},
@@ -515,7 +535,7 @@ func TestMessageService_ProcessMessagePager(t *testing.T) {
 		{
 			name: "ProcessMessagePage failed with some store error when get message by id",
 			mock: func(messageRepo *mocks.MessageRepo, replyRepo *mocks.ReplyRepo) {
-				messageRepo.On("GetFullMessageByID", 1).Return(nil, fmt.Errorf("some store error"))
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -526,11 +546,11 @@ func TestMessageService_ProcessMessagePager(t *testing.T) {
 		{
 			name: "ProcessMessagePage failed with some store error when get full replies by message id",
 			mock: func(messageRepo *mocks.MessageRepo, replyRepo *mocks.ReplyRepo) {
-				messageRepo.On("GetFullMessageByID", 1).Return(&model.FullMessage{
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{
 					ID:    1,
 					Title: "test",
 				}, nil)
-				replyRepo.On("GetFullRepliesByMessageID", 1).Return(nil, fmt.Errorf("some store error"))
+				replyRepo.On("GetFullRepliesByMessageID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -555,7 +575,7 @@ func TestMessageService_ProcessMessagePager(t *testing.T) {
 			messageService := service.NewMessageService(&store.Store{Message: messageRepo}, logger, replyService)
 			tt.mock(messageRepo, replyRepo)
 
-			got, err := messageService.ProcessMessagePage(tt.input)
+			got, err := messageService.ProcessMessagePage(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -572,14 +592,15 @@ func TestMessageService_ProcessHomePage(t *testing.T) {
 		name          string
 		mock          func(messageRepo *mocks.MessageRepo)
 		input         int
+		kind          model.MessageKind
 		want          *service.LoadHomeOutput
 		expectedError error
 	}{
 		{
 			name: "ProcessHomePage successful",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(2, nil)
-				messageRepo.On("GetFullMessagesByPage", 0).Return([]model.FullMessage{
+				messageRepo.On("GetMessagesCount", mock.Anything).Return(2, nil)
+				messageRepo.On("GetFullMessagesByPage", mock.Anything, 0).Return([]model.FullMessage{
 					{ID: 1},
 					{ID: 2},
 				}, nil)
@@ -593,10 +614,28 @@ func TestMessageService_ProcessHomePage(t *testing.T) {
 				MessagesCount: 2,
 			},
 		},
+		{
+			name: "ProcessHomePage successful with kind",
+			mock: func(messageRepo *mocks.MessageRepo) {
+				messageRepo.On("GetMessagesCountByKind", mock.Anything, model.MessageKindQuestion).Return(1, nil)
+				messageRepo.On("GetFullMessagesByKindAndPage", mock.Anything, model.MessageKindQuestion, 0).
+					Return([]model.FullMessage{
+						{ID: 1, Kind: model.MessageKindQuestion},
+					}, nil)
+			},
+			input: 1,
+			kind:  model.MessageKindQuestion,
+			want: &service.LoadHomeOutput{
+				Messages: []model.FullMessage{
+					{ID: 1, Kind: model.MessageKindQuestion},
+				},
+				MessagesCount: 1,
+			},
+		},
 		{
 			name: "ProcessHomePage failed with not found messages",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(0, nil)
+				messageRepo.On("GetMessagesCount", mock.Anything).Return(0, nil)
 			},
 			input: 1,
 			want:  &service.LoadHomeOutput{},
@@ -604,7 +643,7 @@ func TestMessageService_ProcessHomePage(t *testing.T) {
 		{
 			name: "ProcessHomePage failed with some store error when get messages count",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(0, fmt.Errorf("some store error"))
+				messageRepo.On("GetMessagesCount", mock.Anything).Return(0, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -615,8 +654,8 @@ func TestMessageService_ProcessHomePage(t *testing.T) {
 		{
 			name: "ProcessHomePage failed with some store error when get messages by page",
 			mock: func(messageRepo *mocks.MessageRepo) {
-				messageRepo.On("GetMessagesCount").Return(1, nil)
-				messageRepo.On("GetFullMessagesByPage", 0).Return(nil, fmt.Errorf("some store error"))
+				messageRepo.On("GetMessagesCount", mock.Anything).Return(1, nil)
+				messageRepo.On("GetFullMessagesByPage", mock.Anything, 0).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -638,7 +677,7 @@ func TestMessageService_ProcessHomePage(t *testing.T) {
 			messageService := service.NewMessageService(&store.Store{Message: messageRepo}, logger, replyService)
 			tt.mock(messageRepo)
 
-			got, err := messageService.ProcessHomePage(tt.input)
+			got, err := messageService.ProcessHomePage(context.Background(), tt.input, tt.kind)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
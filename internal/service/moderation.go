@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/convert"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+const (
+	// maxReportReasonLength is a maximum number of characters in the reason of the report.
+	maxReportReasonLength = 500
+
+	// moderationActionsLimit is a number of the latest moderation actions shown in the audit trail.
+	moderationActionsLimit = 20
+)
+
+type moderationService struct {
+	store  *store.Store
+	logger *logger.Logger
+	access AccessService
+}
+
+var _ ModerationService = (*moderationService)(nil)
+
+func NewModerationService(store *store.Store, logger *logger.Logger, access AccessService) *moderationService {
+	return &moderationService{
+		store:  store,
+		logger: logger,
+		access: access,
+	}
+}
+
+// ReportMessage saves the report of the message for review by moderators.
+func (s moderationService) ReportMessage(
+	ctx context.Context, reporter *model.WebUser, messageID int, reason string,
+) error {
+	logger := s.logger.ForContext(ctx)
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxReportReasonLength {
+		logger.Info().Int("user id", reporter.ID).Msg("invalid report reason")
+		return ErrInvalidReportReason
+	}
+
+	message, err := s.store.Message.GetFullMessageByID(ctx, messageID)
+	if err != nil {
+		logger.Error().Err(err).Msg("get full message by id")
+		return fmt.Errorf("get full message by id from db: %w", err)
+	}
+	if message == nil || message.Hidden {
+		logger.Info().Int("message id", messageID).Msg("message by id not found")
+		return ErrMessageNotFound
+	}
+
+	err = s.store.Report.CreateReport(ctx, &model.Report{
+		WebUserID: reporter.ID,
+		MessageID: messageID,
+		Reason:    reason,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("create report")
+		return fmt.Errorf("create report in db: %w", err)
+	}
+
+	logger.Info().Int("user id", reporter.ID).Int("message id", messageID).Msg("message successfully reported")
+	return nil
+}
+
+// GetReportsByPage returns the moderation queue, open reports are ordered from the oldest one.
+func (s moderationService) GetReportsByPage(
+	ctx context.Context, actor *model.WebUser, page int,
+) ([]model.ReportedMessage, error) {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionModerate)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := s.store.Report.GetOpenReportsByPage(ctx, convert.PageToOffset(page))
+	if err != nil {
+		logger.Error().Err(err).Msg("get open reports by page")
+		return nil, fmt.Errorf("get open reports by page from db: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetModerationActions returns the latest actions of the audit trail.
+func (s moderationService) GetModerationActions(
+	ctx context.Context, actor *model.WebUser,
+) ([]model.ModerationAction, error) {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionModerate)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := s.store.ModerationAction.GetModerationActions(ctx, moderationActionsLimit)
+	if err != nil {
+		logger.Error().Err(err).Msg("get moderation actions")
+		return nil, fmt.Errorf("get moderation actions from db: %w", err)
+	}
+
+	return actions, nil
+}
+
+// SetMessageHidden hides or shows the message, open reports of the message are resolved when it's hidden.
+func (s moderationService) SetMessageHidden(
+	ctx context.Context, actor *model.WebUser, messageID int, hidden bool,
+) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionModerate)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.store.Message.UpdateMessageHidden(ctx, messageID, hidden)
+	if err != nil {
+		logger.Error().Err(err).Msg("update message hidden")
+		return fmt.Errorf("update message hidden in db: %w", err)
+	}
+	if !updated {
+		logger.Info().Int("message id", messageID).Msg("message not found")
+		return ErrMessageNotFound
+	}
+
+	if hidden {
+		_, err = s.store.Report.ResolveReportsByMessageID(ctx, messageID, time.Now().UTC())
+		if err != nil {
+			logger.Error().Err(err).Msg("resolve reports by message id")
+			return fmt.Errorf("resolve reports by message id in db: %w", err)
+		}
+	}
+
+	return s.recordAction(ctx, actor, model.ModerationTargetMessage, messageID, hiddenAction(hidden))
+}
+
+func (s moderationService) SetReplyHidden(ctx context.Context, actor *model.WebUser, replyID int, hidden bool) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionModerate)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.store.Reply.UpdateReplyHidden(ctx, replyID, hidden)
+	if err != nil {
+		logger.Error().Err(err).Msg("update reply hidden")
+		return fmt.Errorf("update reply hidden in db: %w", err)
+	}
+	if !updated {
+		logger.Info().Int("reply id", replyID).Msg("reply not found")
+		return ErrReplyNotFound
+	}
+
+	return s.recordAction(ctx, actor, model.ModerationTargetReply, replyID, hiddenAction(hidden))
+}
+
+// DismissReports resolves open reports of the message without hiding it.
+func (s moderationService) DismissReports(ctx context.Context, actor *model.WebUser, messageID int) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.access.Authorize(ctx, actor, model.PermissionModerate)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := s.store.Report.ResolveReportsByMessageID(ctx, messageID, time.Now().UTC())
+	if err != nil {
+		logger.Error().Err(err).Msg("resolve reports by message id")
+		return fmt.Errorf("resolve reports by message id in db: %w", err)
+	}
+	if !resolved {
+		logger.Info().Int("message id", messageID).Msg("open reports not found")
+		return ErrReportsNotFound
+	}
+
+	return s.recordAction(ctx, actor, model.ModerationTargetMessage, messageID, model.ModerationActionDismiss)
+}
+
+// recordAction adds the action to the audit trail.
+func (s moderationService) recordAction(
+	ctx context.Context, actor *model.WebUser, target model.ModerationTarget, targetID int,
+	action model.ModerationActionType,
+) error {
+	logger := s.logger.ForContext(ctx)
+
+	moderatorID := actor.ID
+
+	err := s.store.ModerationAction.CreateModerationAction(ctx, &model.ModerationAction{
+		ModeratorID: &moderatorID,
+		TargetType:  target,
+		TargetID:    targetID,
+		Action:      action,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("create moderation action")
+		return fmt.Errorf("create moderation action in db: %w", err)
+	}
+
+	logger.Info().Int("moderator id", actor.ID).Str("target", string(target)).Int("target id", targetID).
+		Str("action", string(action)).Msg("moderation action successfully applied")
+	return nil
+}
+
+func hiddenAction(hidden bool) model.ModerationActionType {
+	if hidden {
+		return model.ModerationActionHide
+	}
+
+	return model.ModerationActionUnhide
+}
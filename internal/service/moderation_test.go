@@ -0,0 +1,438 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var moderatorUser = &model.WebUser{ID: 3, Role: model.RoleModerator}
+
+type moderationRepos struct {
+	message          *mocks.MessageRepo
+	reply            *mocks.ReplyRepo
+	report           *mocks.ReportRepo
+	moderationAction *mocks.ModerationActionRepo
+}
+
+func newModerationService(t *testing.T) (service.ModerationService, *moderationRepos) {
+	t.Helper()
+
+	repos := &moderationRepos{
+		message:          &mocks.MessageRepo{},
+		reply:            &mocks.ReplyRepo{},
+		report:           &mocks.ReportRepo{},
+		moderationAction: &mocks.ModerationActionRepo{},
+	}
+
+	store := &store.Store{
+		Message:          repos.message,
+		Reply:            repos.reply,
+		Report:           repos.report,
+		ModerationAction: repos.moderationAction,
+	}
+
+	logger := logger.Get(&config.Config{LogLevel: "info"})
+	accessService := service.NewAccessService(store, logger)
+
+	return service.NewModerationService(store, logger, accessService), repos
+}
+
+func (r *moderationRepos) assertExpectations(t *testing.T) {
+	t.Helper()
+
+	r.message.AssertExpectations(t)
+	r.reply.AssertExpectations(t)
+	r.report.AssertExpectations(t)
+	r.moderationAction.AssertExpectations(t)
+}
+
+// matchAction matches the moderation action of the moderator user in the audit trail.
+func matchAction(target model.ModerationTarget, targetID int, action model.ModerationActionType) interface{} {
+	return mock.MatchedBy(func(a *model.ModerationAction) bool {
+		return a.ModeratorID != nil && *a.ModeratorID == moderatorUser.ID && a.TargetType == target &&
+			a.TargetID == targetID && a.Action == action && !a.CreatedAt.IsZero()
+	})
+}
+
+func TestModerationService_ReportMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *moderationRepos)
+		reason        string
+		expectedError error
+	}{
+		{
+			name: "ReportMessage successful",
+			mock: func(repos *moderationRepos) {
+				repos.message.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{ID: 1}, nil)
+				repos.report.On("CreateReport", mock.Anything, mock.MatchedBy(func(r *model.Report) bool {
+					return r.WebUserID == memberUser.ID && r.MessageID == 1 && r.Reason == "spam" && !r.CreatedAt.IsZero()
+				})).Return(nil)
+			},
+			reason: "  spam ",
+		},
+		{
+			name:          "ReportMessage failed with empty reason",
+			mock:          func(repos *moderationRepos) {},
+			reason:        " ",
+			expectedError: service.ErrInvalidReportReason,
+		},
+		{
+			name:          "ReportMessage failed with too long reason",
+			mock:          func(repos *moderationRepos) {},
+			reason:        strings.Repeat("a", 501),
+			expectedError: service.ErrInvalidReportReason,
+		},
+		{
+			name: "ReportMessage failed with hidden message",
+			mock: func(repos *moderationRepos) {
+				repos.message.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{ID: 1, Hidden: true}, nil)
+			},
+			reason:        "spam",
+			expectedError: service.ErrMessageNotFound,
+		},
+		{
+			name: "ReportMessage failed with some store error when create report",
+			mock: func(repos *moderationRepos) {
+				repos.message.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{ID: 1}, nil)
+				repos.report.On("CreateReport", mock.Anything, mock.Anything).Return(fmt.Errorf("some store error"))
+			},
+			reason:        "spam",
+			expectedError: fmt.Errorf("create report in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			moderationService, repos := newModerationService(t)
+			tt.mock(repos)
+
+			err := moderationService.ReportMessage(context.Background(), memberUser, 1, tt.reason)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_GetReportsByPage(t *testing.T) {
+	t.Parallel()
+
+	reports := []model.ReportedMessage{{Report: model.Report{ID: 1, MessageID: 1, Reason: "spam"}}}
+
+	tests := []struct {
+		name          string
+		mock          func(repos *moderationRepos)
+		actor         *model.WebUser
+		want          []model.ReportedMessage
+		expectedError error
+	}{
+		{
+			name: "GetReportsByPage successful",
+			mock: func(repos *moderationRepos) {
+				repos.report.On("GetOpenReportsByPage", mock.Anything, 10).Return(reports, nil)
+			},
+			actor: moderatorUser,
+			want:  reports,
+		},
+		{
+			name:          "GetReportsByPage failed with not permitted user",
+			mock:          func(repos *moderationRepos) {},
+			actor:         memberUser,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "GetReportsByPage failed with some store error",
+			mock: func(repos *moderationRepos) {
+				repos.report.On("GetOpenReportsByPage", mock.Anything, 10).Return(nil, fmt.Errorf("some store error"))
+			},
+			actor:         moderatorUser,
+			expectedError: fmt.Errorf("get open reports by page from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			moderationService, repos := newModerationService(t)
+			tt.mock(repos)
+
+			got, err := moderationService.GetReportsByPage(context.Background(), tt.actor, 2)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_GetModerationActions(t *testing.T) {
+	t.Parallel()
+
+	actions := []model.ModerationAction{{ID: 1, TargetType: model.ModerationTargetMessage, TargetID: 1}}
+
+	tests := []struct {
+		name          string
+		mock          func(repos *moderationRepos)
+		actor         *model.WebUser
+		want          []model.ModerationAction
+		expectedError error
+	}{
+		{
+			name: "GetModerationActions successful",
+			mock: func(repos *moderationRepos) {
+				repos.moderationAction.On("GetModerationActions", mock.Anything, 20).Return(actions, nil)
+			},
+			actor: moderatorUser,
+			want:  actions,
+		},
+		{
+			name:          "GetModerationActions failed with not permitted user",
+			mock:          func(repos *moderationRepos) {},
+			actor:         memberUser,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "GetModerationActions failed with some store error",
+			mock: func(repos *moderationRepos) {
+				repos.moderationAction.On("GetModerationActions", mock.Anything, 20).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			actor:         moderatorUser,
+			expectedError: fmt.Errorf("get moderation actions from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			moderationService, repos := newModerationService(t)
+			tt.mock(repos)
+
+			got, err := moderationService.GetModerationActions(context.Background(), tt.actor)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_SetMessageHidden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *moderationRepos)
+		actor         *model.WebUser
+		hidden        bool
+		expectedError error
+	}{
+		{
+			name: "SetMessageHidden successful with hidden message",
+			mock: func(repos *moderationRepos) {
+				repos.message.On("UpdateMessageHidden", mock.Anything, 1, true).Return(true, nil)
+				repos.report.On("ResolveReportsByMessageID", mock.Anything, 1, mock.AnythingOfType("time.Time")).
+					Return(true, nil)
+				repos.moderationAction.On(
+					"CreateModerationAction", mock.Anything,
+					matchAction(model.ModerationTargetMessage, 1, model.ModerationActionHide),
+				).Return(nil)
+			},
+			actor:  moderatorUser,
+			hidden: true,
+		},
+		{
+			name: "SetMessageHidden successful with shown message",
+			mock: func(repos *moderationRepos) {
+				repos.message.On("UpdateMessageHidden", mock.Anything, 1, false).Return(true, nil)
+				repos.moderationAction.On(
+					"CreateModerationAction", mock.Anything,
+					matchAction(model.ModerationTargetMessage, 1, model.ModerationActionUnhide),
+				).Return(nil)
+			},
+			actor: moderatorUser,
+		},
+		{
+			name:          "SetMessageHidden failed with not permitted user",
+			mock:          func(repos *moderationRepos) {},
+			actor:         memberUser,
+			hidden:        true,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "SetMessageHidden failed with not found message",
+			mock: func(repos *moderationRepos) {
+				repos.message.On("UpdateMessageHidden", mock.Anything, 1, true).Return(false, nil)
+			},
+			actor:         moderatorUser,
+			hidden:        true,
+			expectedError: service.ErrMessageNotFound,
+		},
+		{
+			name: "SetMessageHidden failed with some store error when create moderation action",
+			mock: func(repos *moderationRepos) {
+				repos.message.On("UpdateMessageHidden", mock.Anything, 1, true).Return(true, nil)
+				repos.report.On("ResolveReportsByMessageID", mock.Anything, 1, mock.AnythingOfType("time.Time")).
+					Return(false, nil)
+				repos.moderationAction.On("CreateModerationAction", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("some store error"))
+			},
+			actor:         moderatorUser,
+			hidden:        true,
+			expectedError: fmt.Errorf("create moderation action in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			moderationService, repos := newModerationService(t)
+			tt.mock(repos)
+
+			err := moderationService.SetMessageHidden(context.Background(), tt.actor, 1, tt.hidden)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_SetReplyHidden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *moderationRepos)
+		actor         *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "SetReplyHidden successful",
+			mock: func(repos *moderationRepos) {
+				repos.reply.On("UpdateReplyHidden", mock.Anything, 2, true).Return(true, nil)
+				repos.moderationAction.On(
+					"CreateModerationAction", mock.Anything,
+					matchAction(model.ModerationTargetReply, 2, model.ModerationActionHide),
+				).Return(nil)
+			},
+			actor: moderatorUser,
+		},
+		{
+			name:          "SetReplyHidden failed with not permitted user",
+			mock:          func(repos *moderationRepos) {},
+			actor:         memberUser,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "SetReplyHidden failed with not found reply",
+			mock: func(repos *moderationRepos) {
+				repos.reply.On("UpdateReplyHidden", mock.Anything, 2, true).Return(false, nil)
+			},
+			actor:         moderatorUser,
+			expectedError: service.ErrReplyNotFound,
+		},
+		{
+			name: "SetReplyHidden failed with some store error",
+			mock: func(repos *moderationRepos) {
+				repos.reply.On("UpdateReplyHidden", mock.Anything, 2, true).Return(false, fmt.Errorf("some store error"))
+			},
+			actor:         moderatorUser,
+			expectedError: fmt.Errorf("update reply hidden in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			moderationService, repos := newModerationService(t)
+			tt.mock(repos)
+
+			err := moderationService.SetReplyHidden(context.Background(), tt.actor, 2, true)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_DismissReports(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(repos *moderationRepos)
+		actor         *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "DismissReports successful",
+			mock: func(repos *moderationRepos) {
+				repos.report.On("ResolveReportsByMessageID", mock.Anything, 1, mock.AnythingOfType("time.Time")).
+					Return(true, nil)
+				repos.moderationAction.On(
+					"CreateModerationAction", mock.Anything,
+					matchAction(model.ModerationTargetMessage, 1, model.ModerationActionDismiss),
+				).Return(nil)
+			},
+			actor: moderatorUser,
+		},
+		{
+			name:          "DismissReports failed with not permitted user",
+			mock:          func(repos *moderationRepos) {},
+			actor:         memberUser,
+			expectedError: service.ErrPermissionDenied,
+		},
+		{
+			name: "DismissReports failed with not found reports",
+			mock: func(repos *moderationRepos) {
+				repos.report.On("ResolveReportsByMessageID", mock.Anything, 1, mock.AnythingOfType("time.Time")).
+					Return(false, nil)
+			},
+			actor:         moderatorUser,
+			expectedError: service.ErrReportsNotFound,
+		},
+		{
+			name: "DismissReports failed with some store error",
+			mock: func(repos *moderationRepos) {
+				repos.report.On("ResolveReportsByMessageID", mock.Anything, 1, mock.AnythingOfType("time.Time")).
+					Return(false, fmt.Errorf("some store error"))
+			},
+			actor:         moderatorUser,
+			expectedError: fmt.Errorf("resolve reports by message id in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			moderationService, repos := newModerationService(t)
+			tt.mock(repos)
+
+			err := moderationService.DismissReports(context.Background(), tt.actor, 1)
+			assert.Equal(t, tt.expectedError, err)
+
+			repos.assertExpectations(t)
+		})
+	}
+}
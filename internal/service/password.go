@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/mailer"
+	"github.com/VladPetriv/scanner_backend/pkg/password"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+// minPasswordLength is a minimal length of the new password.
+const minPasswordLength = 8
+
+type passwordService struct {
+	store   *store.Store
+	logger  *logger.Logger
+	mailer  mailer.Mailer
+	baseURL string
+	ttl     time.Duration
+}
+
+var _ PasswordService = (*passwordService)(nil)
+
+func NewPasswordService(
+	store *store.Store, logger *logger.Logger, mailer mailer.Mailer, baseURL string, ttl time.Duration,
+) *passwordService {
+	return &passwordService{
+		store:   store,
+		logger:  logger,
+		mailer:  mailer,
+		baseURL: baseURL,
+		ttl:     ttl,
+	}
+}
+
+// RequestPasswordReset emails link for password reset to the user.
+// Unknown email isn't reported as an error, so the form can't be used to find out registered emails.
+func (s passwordService) RequestPasswordReset(ctx context.Context, email string) error {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.store.WebUser.GetWebUserByEmail(ctx, email)
+	if err != nil {
+		logger.Error().Err(err).Msg("get web user by email")
+		return fmt.Errorf("get web user by email from db: %w", err)
+	}
+	if user == nil {
+		logger.Info().Str("user email", email).Msg("password reset requested for unknown email")
+		return nil
+	}
+
+	// Only the latest requested link is valid.
+	err = s.store.PasswordReset.DeletePasswordResetsByUserID(ctx, user.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete password resets by user id")
+		return fmt.Errorf("delete password resets by user id from db: %w", err)
+	}
+
+	resetToken, err := token.Generate(token.DefaultLength)
+	if err != nil {
+		logger.Error().Err(err).Msg("generate password reset token")
+		return fmt.Errorf("generate password reset token: %w", err)
+	}
+
+	err = s.store.PasswordReset.CreatePasswordReset(ctx, &model.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: token.Hash(resetToken),
+		ExpiresAt: time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("create password reset")
+		return fmt.Errorf("create password reset in db: %w", err)
+	}
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf(
+			"Follow the link to set a new password, it's valid for %s:\n\n%s/auth/reset-password?token=%s\n\n"+
+				"If you didn't request password reset, just ignore this email.",
+			s.ttl, s.baseURL, url.QueryEscape(resetToken),
+		),
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("send password reset email")
+		return fmt.Errorf("send password reset email: %w", err)
+	}
+
+	logger.Info().Int("user id", user.ID).Msg("password reset successfully requested")
+	return nil
+}
+
+// CheckPasswordResetToken checks that the token can be used for password reset.
+func (s passwordService) CheckPasswordResetToken(ctx context.Context, resetToken string) error {
+	logger := s.logger.ForContext(ctx)
+
+	reset, err := s.store.PasswordReset.GetPasswordReset(ctx, token.Hash(resetToken))
+	if err != nil {
+		logger.Error().Err(err).Msg("get password reset")
+		return fmt.Errorf("get password reset from db: %w", err)
+	}
+	if reset == nil {
+		logger.Info().Msg("password reset not found")
+		return ErrPasswordResetTokenInvalid
+	}
+
+	return nil
+}
+
+// ResetPassword sets the new password, ends all sessions and revokes API tokens of the user,
+// the token can be used only once.
+func (s passwordService) ResetPassword(ctx context.Context, resetToken string, newPassword string) error {
+	logger := s.logger.ForContext(ctx)
+
+	if len(newPassword) < minPasswordLength {
+		logger.Info().Msg("new password is too short")
+		return ErrPasswordTooShort
+	}
+
+	hashedPassword, err := password.HashPassword(newPassword)
+	if err != nil {
+		logger.Error().Err(err).Msg("hash user password")
+		return fmt.Errorf("hash user password: %w", err)
+	}
+
+	// The token is used in the same transaction as the password is updated, so a failure doesn't waste it.
+	reset, err := s.store.PasswordReset.ResetPassword(ctx, token.Hash(resetToken), hashedPassword)
+	if err != nil {
+		logger.Error().Err(err).Msg("reset password")
+		return fmt.Errorf("reset password in db: %w", err)
+	}
+	if reset == nil {
+		logger.Info().Msg("password reset not found")
+		return ErrPasswordResetTokenInvalid
+	}
+
+	logger.Info().Int("user id", reset.UserID).Msg("password successfully reset")
+	return nil
+}
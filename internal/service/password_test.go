@@ -0,0 +1,238 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/mailer"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+// fakeMailer remembers sent messages instead of sending them.
+type fakeMailer struct {
+	mu       sync.Mutex
+	err      error
+	messages []mailer.Message
+}
+
+func (m *fakeMailer) Send(_ context.Context, message mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.messages = append(m.messages, message)
+
+	return nil
+}
+
+func TestPasswordService_RequestPasswordReset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo, passwordResetRepo *mocks.PasswordResetRepo)
+		mailerError   error
+		expectedMails int
+		expectedError error
+	}{
+		{
+			name: "RequestPasswordReset successful",
+			mock: func(webUserRepo *mocks.WebUserRepo, passwordResetRepo *mocks.PasswordResetRepo) {
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com"}, nil)
+				passwordResetRepo.On("DeletePasswordResetsByUserID", mock.Anything, 1).Return(nil)
+				passwordResetRepo.On("CreatePasswordReset", mock.Anything, mock.MatchedBy(func(reset *model.PasswordReset) bool {
+					return reset.UserID == 1 && len(reset.TokenHash) == 64 && time.Until(reset.ExpiresAt) > 0
+				})).Return(nil)
+			},
+			expectedMails: 1,
+		},
+		{
+			name: "RequestPasswordReset successful with unknown email",
+			mock: func(webUserRepo *mocks.WebUserRepo, passwordResetRepo *mocks.PasswordResetRepo) {
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, nil)
+			},
+		},
+		{
+			name: "RequestPasswordReset failed with some store error",
+			mock: func(webUserRepo *mocks.WebUserRepo, passwordResetRepo *mocks.PasswordResetRepo) {
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com"}, nil)
+				passwordResetRepo.On("DeletePasswordResetsByUserID", mock.Anything, 1).Return(nil)
+				passwordResetRepo.On("CreatePasswordReset", mock.Anything, mock.Anything).
+					Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("create password reset in db: %w", fmt.Errorf("some store error")),
+		},
+		{
+			name: "RequestPasswordReset failed with mailer error",
+			mock: func(webUserRepo *mocks.WebUserRepo, passwordResetRepo *mocks.PasswordResetRepo) {
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com"}, nil)
+				passwordResetRepo.On("DeletePasswordResetsByUserID", mock.Anything, 1).Return(nil)
+				passwordResetRepo.On("CreatePasswordReset", mock.Anything, mock.Anything).Return(nil)
+			},
+			mailerError:   fmt.Errorf("some mailer error"),
+			expectedError: fmt.Errorf("send password reset email: %w", fmt.Errorf("some mailer error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+			passwordResetRepo := &mocks.PasswordResetRepo{}
+			mailer := &fakeMailer{err: tt.mailerError}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			passwordService := service.NewPasswordService(
+				&store.Store{WebUser: webUserRepo, PasswordReset: passwordResetRepo},
+				logger, mailer, "http://localhost:8080", time.Hour,
+			)
+			tt.mock(webUserRepo, passwordResetRepo)
+
+			err := passwordService.RequestPasswordReset(context.Background(), "test@test.com")
+			assert.Equal(t, tt.expectedError, err)
+			assert.Len(t, mailer.messages, tt.expectedMails)
+
+			if tt.expectedMails > 0 {
+				assert.Equal(t, "test@test.com", mailer.messages[0].To)
+				assert.Contains(t, mailer.messages[0].Body, "http://localhost:8080/auth/reset-password?token=")
+			}
+
+			webUserRepo.AssertExpectations(t)
+			passwordResetRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPasswordService_CheckPasswordResetToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(passwordResetRepo *mocks.PasswordResetRepo)
+		expectedError error
+	}{
+		{
+			name: "CheckPasswordResetToken successful",
+			mock: func(passwordResetRepo *mocks.PasswordResetRepo) {
+				passwordResetRepo.On("GetPasswordReset", mock.Anything, token.Hash("token")).
+					Return(&model.PasswordReset{ID: 1, UserID: 1}, nil)
+			},
+		},
+		{
+			name: "CheckPasswordResetToken failed with used or expired token",
+			mock: func(passwordResetRepo *mocks.PasswordResetRepo) {
+				passwordResetRepo.On("GetPasswordReset", mock.Anything, token.Hash("token")).Return(nil, nil)
+			},
+			expectedError: service.ErrPasswordResetTokenInvalid,
+		},
+		{
+			name: "CheckPasswordResetToken failed with some store error",
+			mock: func(passwordResetRepo *mocks.PasswordResetRepo) {
+				passwordResetRepo.On("GetPasswordReset", mock.Anything, token.Hash("token")).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("get password reset from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			passwordResetRepo := &mocks.PasswordResetRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			passwordService := service.NewPasswordService(
+				&store.Store{PasswordReset: passwordResetRepo}, logger, &fakeMailer{}, "http://localhost:8080", time.Hour,
+			)
+			tt.mock(passwordResetRepo)
+
+			err := passwordService.CheckPasswordResetToken(context.Background(), "token")
+			assert.Equal(t, tt.expectedError, err)
+
+			passwordResetRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPasswordService_ResetPassword(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(passwordResetRepo *mocks.PasswordResetRepo)
+		input         string
+		expectedError error
+	}{
+		{
+			name: "ResetPassword successful",
+			mock: func(passwordResetRepo *mocks.PasswordResetRepo) {
+				passwordResetRepo.On("ResetPassword", mock.Anything, token.Hash("token"), mock.Anything).
+					Return(&model.PasswordReset{ID: 1, UserID: 1}, nil)
+			},
+			input: "new password",
+		},
+		{
+			name:          "ResetPassword failed with too short password",
+			mock:          func(passwordResetRepo *mocks.PasswordResetRepo) {},
+			input:         "short",
+			expectedError: service.ErrPasswordTooShort,
+		},
+		{
+			name: "ResetPassword failed with used or expired token",
+			mock: func(passwordResetRepo *mocks.PasswordResetRepo) {
+				passwordResetRepo.On("ResetPassword", mock.Anything, token.Hash("token"), mock.Anything).Return(nil, nil)
+			},
+			input:         "new password",
+			expectedError: service.ErrPasswordResetTokenInvalid,
+		},
+		{
+			name: "ResetPassword failed with some store error",
+			mock: func(passwordResetRepo *mocks.PasswordResetRepo) {
+				passwordResetRepo.On("ResetPassword", mock.Anything, token.Hash("token"), mock.Anything).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			input:         "new password",
+			expectedError: fmt.Errorf("reset password in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			passwordResetRepo := &mocks.PasswordResetRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			passwordService := service.NewPasswordService(
+				&store.Store{PasswordReset: passwordResetRepo},
+				logger, &fakeMailer{}, "http://localhost:8080", time.Hour,
+			)
+			tt.mock(passwordResetRepo)
+
+			err := passwordService.ResetPassword(context.Background(), "token", tt.input)
+			assert.Equal(t, tt.expectedError, err)
+
+			passwordResetRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
@@ -22,10 +23,10 @@ func NewReplyService(store *store.Store, logger *logger.Logger) *replyService {
 	}
 }
 
-func (s replyService) CreateReply(reply *model.DBReply) error {
-	logger := s.logger
+func (s replyService) CreateReply(ctx context.Context, reply *model.DBReply) error {
+	logger := s.logger.ForContext(ctx)
 
-	err := s.store.Reply.CreateReply(reply)
+	err := s.store.Reply.CreateReply(ctx, reply)
 	if err != nil {
 		logger.Error().Err(err).Msg("create reply")
 		return fmt.Errorf("create reply in db: %w", err)
@@ -35,10 +36,23 @@ func (s replyService) CreateReply(reply *model.DBReply) error {
 	return nil
 }
 
-func (s replyService) GetFullRepliesByMessageID(messageID int) ([]model.FullReply, error) {
-	logger := s.logger
+func (s replyService) CreateReplies(ctx context.Context, replies []model.DBReply) error {
+	logger := s.logger.ForContext(ctx)
 
-	replies, err := s.store.Reply.GetFullRepliesByMessageID(messageID)
+	err := s.store.Reply.CreateReplies(ctx, replies)
+	if err != nil {
+		logger.Error().Err(err).Msg("create replies")
+		return fmt.Errorf("create replies in db: %w", err)
+	}
+
+	logger.Info().Int("replies count", len(replies)).Msg("replies successfully created")
+	return nil
+}
+
+func (s replyService) GetFullRepliesByMessageID(ctx context.Context, messageID int) ([]model.FullReply, error) {
+	logger := s.logger.ForContext(ctx)
+
+	replies, err := s.store.Reply.GetFullRepliesByMessageID(ctx, messageID)
 	if err != nil {
 		logger.Error().Err(err).Msg("get replies by message id")
 		return nil, fmt.Errorf("get replies by message id from db: %w", err)
@@ -1,10 +1,12 @@
 package service_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 	"github.com/VladPetriv/scanner_backend/internal/service"
@@ -28,14 +30,14 @@ func TestReplyService_CreateReply(t *testing.T) {
 		{
 			name: "CreateReply successful",
 			mock: func(replyRepo *mocks.ReplyRepo) {
-				replyRepo.On("CreateReply", replyInput).Return(nil)
+				replyRepo.On("CreateReply", mock.Anything, replyInput).Return(nil)
 			},
 			input: replyInput,
 		},
 		{
 			name: "CreteReply failed with some store error",
 			mock: func(replyRepo *mocks.ReplyRepo) {
-				replyRepo.On("CreateReply", replyInput).Return(fmt.Errorf("some store error"))
+				replyRepo.On("CreateReply", mock.Anything, replyInput).Return(fmt.Errorf("some store error"))
 			},
 			input: replyInput,
 			expectedError: fmt.Errorf(
@@ -53,13 +55,65 @@ func TestReplyService_CreateReply(t *testing.T) {
 		replyService := service.NewReplyService(&store.Store{Reply: replyRepo}, logger)
 		tt.mock(replyRepo)
 
-		err := replyService.CreateReply(tt.input)
+		err := replyService.CreateReply(context.Background(), tt.input)
 		assert.Equal(t, tt.expectedError, err)
 
 		replyRepo.AssertExpectations(t)
 	}
 }
 
+func TestReplyService_CreateReplies(t *testing.T) {
+	t.Parallel()
+
+	repliesInput := []model.DBReply{
+		{UserID: 1, MessageID: 1, Title: "test1", ImageURL: "test1.jpg"},
+		{UserID: 2, MessageID: 1, Title: "test2", ImageURL: "test2.jpg"},
+	}
+
+	tests := []struct {
+		name          string
+		mock          func(replyRepo *mocks.ReplyRepo)
+		input         []model.DBReply
+		expectedError error
+	}{
+		{
+			name: "CreateReplies successful",
+			mock: func(replyRepo *mocks.ReplyRepo) {
+				replyRepo.On("CreateReplies", mock.Anything, repliesInput).Return(nil)
+			},
+			input: repliesInput,
+		},
+		{
+			name: "CreateReplies failed with some store error",
+			mock: func(replyRepo *mocks.ReplyRepo) {
+				replyRepo.On("CreateReplies", mock.Anything, repliesInput).Return(fmt.Errorf("some store error"))
+			},
+			input: repliesInput,
+			expectedError: fmt.Errorf(
+				"create replies in db: %w",
+				fmt.Errorf("some store error"),
+			),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			replyRepo := &mocks.ReplyRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			replyService := service.NewReplyService(&store.Store{Reply: replyRepo}, logger)
+			tt.mock(replyRepo)
+
+			err := replyService.CreateReplies(context.Background(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+
+			replyRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func Test_GetFullRepliesByMessageID(t *testing.T) {
 	replies := []model.FullReply{
 		{ID: 1, UserID: 1, Title: "test1", FullName: "test test1", UserImageURL: "test1.jpg"},
@@ -76,7 +130,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		{
 			name: "GetFullRepliesByMessageID successful",
 			mock: func(replyRepo *mocks.ReplyRepo) {
-				replyRepo.On("GetFullRepliesByMessageID", 1).Return(replies, nil)
+				replyRepo.On("GetFullRepliesByMessageID", mock.Anything, 1).Return(replies, nil)
 			},
 			input: 1,
 			want:  replies,
@@ -84,7 +138,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		{
 			name: "GetFullRepliesByMessageID failed with not found replies",
 			mock: func(replyRepo *mocks.ReplyRepo) {
-				replyRepo.On("GetFullRepliesByMessageID", 1).Return(nil, nil)
+				replyRepo.On("GetFullRepliesByMessageID", mock.Anything, 1).Return(nil, nil)
 			},
 			input:         1,
 			expectedError: service.ErrRepliesNotFound,
@@ -92,7 +146,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		{
 			name: "GetFullRepliesByMessageID failed with store error",
 			mock: func(replyRepo *mocks.ReplyRepo) {
-				replyRepo.On("GetFullRepliesByMessageID", 1).Return(nil, fmt.Errorf("some store error"))
+				replyRepo.On("GetFullRepliesByMessageID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -111,7 +165,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		replyService := service.NewReplyService(&store.Store{Reply: replyRepo}, logger)
 		tt.mock(replyRepo)
 
-		got, err := replyService.GetFullRepliesByMessageID(tt.input)
+		got, err := replyService.GetFullRepliesByMessageID(context.Background(), tt.input)
 		assert.Equal(t, tt.expectedError, err)
 		assert.Equal(t, tt.want, got)
 
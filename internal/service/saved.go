@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -25,10 +26,10 @@ func NewSavedService(store *store.Store, logger *logger.Logger, messageService M
 	}
 }
 
-func (s savedService) GetSavedMessageByMessageID(id int) (*model.Saved, error) {
-	logger := s.logger
+func (s savedService) GetSavedMessageByMessageID(ctx context.Context, id int) (*model.Saved, error) {
+	logger := s.logger.ForContext(ctx)
 
-	message, err := s.store.Saved.GetSavedMessageByID(id)
+	message, err := s.store.Saved.GetSavedMessageByID(ctx, id)
 	if err != nil {
 		logger.Error().Err(err).Msg("get saved message by message id")
 		return nil, fmt.Errorf("get saved message by message id from db: %w", err)
@@ -42,10 +43,10 @@ func (s savedService) GetSavedMessageByMessageID(id int) (*model.Saved, error) {
 	return message, nil
 }
 
-func (s savedService) CreateSavedMessage(savedMessage *model.Saved) error {
-	logger := s.logger
+func (s savedService) CreateSavedMessage(ctx context.Context, savedMessage *model.Saved) error {
+	logger := s.logger.ForContext(ctx)
 
-	err := s.store.Saved.CreateSavedMessage(savedMessage)
+	err := s.store.Saved.CreateSavedMessage(ctx, savedMessage)
 	if err != nil {
 		logger.Error().Err(err).Msg("create saved message")
 		return fmt.Errorf("create saved message in db: %w", err)
@@ -55,10 +56,10 @@ func (s savedService) CreateSavedMessage(savedMessage *model.Saved) error {
 	return nil
 }
 
-func (s savedService) DeleteSavedMessage(id int) error {
-	logger := s.logger
+func (s savedService) DeleteSavedMessage(ctx context.Context, id int) error {
+	logger := s.logger.ForContext(ctx)
 
-	err := s.store.Saved.DeleteSavedMessage(id)
+	err := s.store.Saved.DeleteSavedMessage(ctx, id)
 	if err != nil {
 		logger.Error().Err(err).Msg("delete saved message")
 		return fmt.Errorf("delete saved message from db: %w", err)
@@ -68,12 +69,12 @@ func (s savedService) DeleteSavedMessage(id int) error {
 	return nil
 }
 
-func (s savedService) ProcessSavedMessages(userID int) (*LoadSavedMessagesOutput, error) {
-	logger := s.logger
+func (s savedService) ProcessSavedMessages(ctx context.Context, userID int) (*LoadSavedMessagesOutput, error) {
+	logger := s.logger.ForContext(ctx)
 
 	var savedMessages []model.FullMessage
 
-	messages, err := s.store.Saved.GetSavedMessages(userID)
+	messages, err := s.store.Saved.GetSavedMessages(ctx, userID)
 	if err != nil {
 		if errors.Is(err, ErrSavedMessagesNotFound) {
 			logger.Info().Msg("messages not found")
@@ -85,7 +86,7 @@ func (s savedService) ProcessSavedMessages(userID int) (*LoadSavedMessagesOutput
 	}
 
 	for _, msg := range messages {
-		fullMessage, err := s.message.GetFullMessageByMessageID(msg.MessageID)
+		fullMessage, err := s.message.GetFullMessageByMessageID(ctx, msg.MessageID)
 		if err != nil {
 			if errors.Is(err, ErrMessageNotFound) {
 				logger.Info().Msg("message not found")
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -12,6 +13,7 @@ import (
 	"github.com/VladPetriv/scanner_backend/pkg/config"
 	"github.com/VladPetriv/scanner_backend/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestSavedService_GetSavedMessageByMessageID(t *testing.T) {
@@ -27,7 +29,7 @@ func TestSavedService_GetSavedMessageByMessageID(t *testing.T) {
 		{
 			name: "GetSavedMessageByMessageID successful",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("GetSavedMessageByID", 1).Return(&model.Saved{ID: 1, WebUserID: 1, MessageID: 1}, nil)
+				savedRepo.On("GetSavedMessageByID", mock.Anything, 1).Return(&model.Saved{ID: 1, WebUserID: 1, MessageID: 1}, nil)
 			},
 			input: 1,
 			want:  &model.Saved{ID: 1, WebUserID: 1, MessageID: 1},
@@ -35,7 +37,7 @@ func TestSavedService_GetSavedMessageByMessageID(t *testing.T) {
 		{
 			name: "GetSavedMessageByMessageID failed with not found message",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("GetSavedMessageByID", 1).Return(nil, nil)
+				savedRepo.On("GetSavedMessageByID", mock.Anything, 1).Return(nil, nil)
 			},
 			input:         1,
 			expectedError: service.ErrSavedMessageNotFound,
@@ -43,7 +45,7 @@ func TestSavedService_GetSavedMessageByMessageID(t *testing.T) {
 		{
 			name: "GetSavedMessageByMessageID failed with some store erorr",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("GetSavedMessageByID", 1).Return(nil, errors.New("some store error"))
+				savedRepo.On("GetSavedMessageByID", mock.Anything, 1).Return(nil, errors.New("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -68,7 +70,7 @@ func TestSavedService_GetSavedMessageByMessageID(t *testing.T) {
 			savedService := service.NewSavedService(&store.Store{Saved: savedRepo}, logger, messageService)
 			tt.mock(savedRepo)
 
-			got, err := savedService.GetSavedMessageByMessageID(tt.input)
+			got, err := savedService.GetSavedMessageByMessageID(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -91,14 +93,14 @@ func TestSavedService_CreateSavedMessage(t *testing.T) {
 		{
 			name: "CreateSavedMessage successful",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("CreateSavedMessage", &model.Saved{WebUserID: 1, MessageID: 1}).Return(nil)
+				savedRepo.On("CreateSavedMessage", mock.Anything, &model.Saved{WebUserID: 1, MessageID: 1}).Return(nil)
 			},
 			input: &model.Saved{WebUserID: 1, MessageID: 1},
 		},
 		{
 			name: "CreateSavedMessage failed with some store error",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("CreateSavedMessage", &model.Saved{
+				savedRepo.On("CreateSavedMessage", mock.Anything, &model.Saved{
 					WebUserID: 1,
 					MessageID: 1,
 				}).Return(fmt.Errorf("some store error"))
@@ -125,7 +127,7 @@ func TestSavedService_CreateSavedMessage(t *testing.T) {
 			savedService := service.NewSavedService(&store.Store{Saved: savedRepo}, logger, messageService)
 			tt.mock(savedRepo)
 
-			err := savedService.CreateSavedMessage(tt.input)
+			err := savedService.CreateSavedMessage(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 
 			savedRepo.AssertExpectations(t)
@@ -147,14 +149,14 @@ func TestSavedService_DeleteSavedMessage(t *testing.T) {
 		{
 			name: "DeleteSavedMessage successful",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("DeleteSavedMessage", 1).Return(nil)
+				savedRepo.On("DeleteSavedMessage", mock.Anything, 1).Return(nil)
 			},
 			input: 1,
 		},
 		{
 			name: "DeleteSavedMessage failed with some store error",
 			mock: func(savedRepo *mocks.SavedRepo) {
-				savedRepo.On("DeleteSavedMessage", 1).Return(fmt.Errorf("some store error"))
+				savedRepo.On("DeleteSavedMessage", mock.Anything, 1).Return(fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -179,7 +181,7 @@ func TestSavedService_DeleteSavedMessage(t *testing.T) {
 			savedService := service.NewSavedService(&store.Store{Saved: savedRepo}, logger, messageService)
 			tt.mock(savedRepo)
 
-			err := savedService.DeleteSavedMessage(tt.input)
+			err := savedService.DeleteSavedMessage(context.Background(), tt.input)
 			assert.EqualValues(t, tt.expectedError, err)
 
 			savedRepo.AssertExpectations(t)
@@ -203,16 +205,16 @@ func TestSavedService_ProcessSavedMessages(t *testing.T) {
 			name:  "ProcessSavedMessages successful",
 			input: 1,
 			mock: func(savedRepo *mocks.SavedRepo, messageRepo *mocks.MessageRepo) {
-				savedRepo.On("GetSavedMessages", 1).Return([]model.Saved{
+				savedRepo.On("GetSavedMessages", mock.Anything, 1).Return([]model.Saved{
 					{MessageID: 1, WebUserID: 1},
 					{MessageID: 2, WebUserID: 1},
 				}, nil)
 
-				messageRepo.On("GetFullMessageByID", 1).Return(&model.FullMessage{
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{
 					ID: 1,
 				}, nil)
 
-				messageRepo.On("GetFullMessageByID", 2).Return(&model.FullMessage{
+				messageRepo.On("GetFullMessageByID", mock.Anything, 2).Return(&model.FullMessage{
 					ID: 2,
 				}, nil)
 			},
@@ -223,11 +225,36 @@ func TestSavedService_ProcessSavedMessages(t *testing.T) {
 				SavedMessagesCount: 2,
 			},
 		},
+		{
+			name:  "ProcessSavedMessages successful without hidden messages",
+			input: 1,
+			mock: func(savedRepo *mocks.SavedRepo, messageRepo *mocks.MessageRepo) {
+				savedRepo.On("GetSavedMessages", mock.Anything, 1).Return([]model.Saved{
+					{MessageID: 1, WebUserID: 1},
+					{MessageID: 2, WebUserID: 1},
+				}, nil)
+
+				messageRepo.On("GetFullMessageByID", mock.Anything, 1).Return(&model.FullMessage{
+					ID: 1,
+				}, nil)
+
+				messageRepo.On("GetFullMessageByID", mock.Anything, 2).Return(&model.FullMessage{
+					ID:     2,
+					Hidden: true,
+				}, nil)
+			},
+			want: &service.LoadSavedMessagesOutput{
+				SavedMessages: []model.FullMessage{
+					{ID: 1},
+				},
+				SavedMessagesCount: 1,
+			},
+		},
 		{
 			name:  "ProcessSavedMessages failed with not found saved messages",
 			input: 1,
 			mock: func(savedRepo *mocks.SavedRepo, messageRepo *mocks.MessageRepo) {
-				savedRepo.On("GetSavedMessages", 1).Return(nil, nil)
+				savedRepo.On("GetSavedMessages", mock.Anything, 1).Return(nil, nil)
 			},
 			want: &service.LoadSavedMessagesOutput{},
 		},
@@ -235,7 +262,7 @@ func TestSavedService_ProcessSavedMessages(t *testing.T) {
 			name:  "ProcessSavedMessages failed with some store error when get saved messages",
 			input: 1,
 			mock: func(savedRepo *mocks.SavedRepo, messageRepo *mocks.MessageRepo) {
-				savedRepo.On("GetSavedMessages", 1).Return(nil, fmt.Errorf("some store error"))
+				savedRepo.On("GetSavedMessages", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			expectedError: fmt.Errorf(
 				"get saved messages from db: %w",
@@ -259,7 +286,7 @@ func TestSavedService_ProcessSavedMessages(t *testing.T) {
 			savedService := service.NewSavedService(&store.Store{Saved: savedRepo}, logger, messageService)
 			tt.mock(savedRepo, messageRepo)
 
-			got, err := savedService.ProcessSavedMessages(tt.input)
+			got, err := savedService.ProcessSavedMessages(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
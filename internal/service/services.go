@@ -1,19 +1,22 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 )
 
 type ChannelService interface {
-	CreateChannel(channel *model.DBChannel) error
-	GetChannels() ([]model.Channel, error)
-	GetChannelsByPage(page int) ([]model.Channel, error)
-	GetChannelByName(name string) (*model.Channel, error)
-	GetChannelStats(channelID int) (*model.Stat, error)
-	ProcessChannelPage(channelName string, page int) (*LoadChannelOutput, error)
-	ProcessChannelsPage(page int) (*LoadChannelsOutput, error)
+	CreateChannel(ctx context.Context, channel *model.DBChannel) error
+	UpdateChannel(ctx context.Context, channel *model.DBChannel) error
+	GetChannels(ctx context.Context) ([]model.Channel, error)
+	GetChannelsByPage(ctx context.Context, page int) ([]model.Channel, error)
+	GetChannelByName(ctx context.Context, name string) (*model.Channel, error)
+	GetChannelStats(ctx context.Context, channelID int) (*model.Stat, error)
+	ProcessChannelPage(ctx context.Context, channelName string, page int) (*LoadChannelOutput, error)
+	ProcessChannelsPage(ctx context.Context, page int) (*LoadChannelsOutput, error)
 }
 
 type LoadChannelOutput struct {
@@ -34,14 +37,14 @@ var (
 )
 
 type MessageService interface {
-	CreateMessage(message *model.DBMessage) (int, error)
-	GetMessagesCount() (int, error)
-	GetMessagesCountByChannelID(ID int) (int, error)
-	GetFullMessagesByChannelIDAndPage(ID, page int) ([]model.FullMessage, error)
-	GetFullMessagesByUserID(ID int) ([]model.FullMessage, error)
-	GetFullMessageByMessageID(id int) (*model.FullMessage, error)
-	ProcessMessagePage(messageID int) (*LoadMessageOutput, error)
-	ProcessHomePage(page int) (*LoadHomeOutput, error)
+	CreateMessage(ctx context.Context, message *model.DBMessage) (int, error)
+	GetMessagesCount(ctx context.Context) (int, error)
+	GetMessagesCountByChannelID(ctx context.Context, ID int) (int, error)
+	GetFullMessagesByChannelIDAndPage(ctx context.Context, ID, page int) ([]model.FullMessage, error)
+	GetFullMessagesByUserID(ctx context.Context, ID int) ([]model.FullMessage, error)
+	GetFullMessageByMessageID(ctx context.Context, id int) (*model.FullMessage, error)
+	ProcessMessagePage(ctx context.Context, messageID int) (*LoadMessageOutput, error)
+	ProcessHomePage(ctx context.Context, page int, kind model.MessageKind) (*LoadHomeOutput, error)
 }
 
 type LoadMessageOutput struct {
@@ -61,17 +64,21 @@ var (
 )
 
 type ReplyService interface {
-	CreateReply(reply *model.DBReply) error
-	GetFullRepliesByMessageID(ID int) ([]model.FullReply, error)
+	CreateReply(ctx context.Context, reply *model.DBReply) error
+	CreateReplies(ctx context.Context, replies []model.DBReply) error
+	GetFullRepliesByMessageID(ctx context.Context, ID int) ([]model.FullReply, error)
 }
 
-var ErrRepliesNotFound = errors.New("replies not found")
+var (
+	ErrRepliesNotFound = errors.New("replies not found")
+	ErrReplyNotFound   = errors.New("reply not found")
+)
 
 type SavedService interface {
-	GetSavedMessageByMessageID(id int) (*model.Saved, error)
-	CreateSavedMessage(saved *model.Saved) error
-	DeleteSavedMessage(ID int) error
-	ProcessSavedMessages(userID int) (*LoadSavedMessagesOutput, error)
+	GetSavedMessageByMessageID(ctx context.Context, id int) (*model.Saved, error)
+	CreateSavedMessage(ctx context.Context, saved *model.Saved) error
+	DeleteSavedMessage(ctx context.Context, ID int) error
+	ProcessSavedMessages(ctx context.Context, userID int) (*LoadSavedMessagesOutput, error)
 }
 
 type LoadSavedMessagesOutput struct {
@@ -85,12 +92,14 @@ var (
 )
 
 type UserService interface {
-	CreateUser(user *model.User) (int, error)
-	ProcessUserPage(userID int) (*LoadUserOutput, error)
+	CreateUser(ctx context.Context, user *model.User) (int, error)
+	CreateUsers(ctx context.Context, users []model.User) (map[string]int, error)
+	ProcessUserPage(ctx context.Context, userID int) (*LoadUserOutput, error)
 }
 
 type LoadUserOutput struct {
 	TgUser        *model.User
+	History       []model.UserHistory
 	Messages      []model.FullMessage
 	MessagesCount int
 }
@@ -98,18 +107,204 @@ type LoadUserOutput struct {
 var ErrUserNotFound = errors.New("user not found")
 
 type WebUserService interface {
-	GetWebUserByEmail(email string) (*model.WebUser, error)
-	CreateWebUser(user *model.WebUser) error
+	GetWebUserByEmail(ctx context.Context, email string) (*model.WebUser, error)
+	GetWebUserByID(ctx context.Context, id int) (*model.WebUser, error)
+	CreateWebUser(ctx context.Context, user *model.WebUser) error
 }
 
 var ErrWebUserNotFound = errors.New("web user not found")
 
 type AuthService interface {
-	Login(email string, userPassword string) (string, error)
-	Register(user *model.WebUser) error
+	Login(ctx context.Context, email string, userPassword string, ipAddress string) (string, error)
+	Register(ctx context.Context, user *model.WebUser) error
 }
 
 var (
 	ErrIncorrectPassword = errors.New("incorrect password")
 	ErrWebUserIsExist    = errors.New("web user is exist")
+	ErrInvalidEmail      = errors.New("invalid email")
+	ErrWebUserBanned     = errors.New("web user is banned")
+)
+
+type VerificationService interface {
+	SendVerification(ctx context.Context, email string) error
+	VerifyEmail(ctx context.Context, verificationToken string) error
+}
+
+var (
+	ErrVerificationTokenInvalid = errors.New("verification token is invalid or expired")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+)
+
+type LoginAttemptService interface {
+	CheckLoginAttempt(ctx context.Context, email string, ipAddress string) error
+	RecordLoginFailure(ctx context.Context, email string, ipAddress string) error
+	ResetLoginFailures(ctx context.Context, email string) error
+}
+
+// LoginLimits configures how many failed logins are allowed before the lockout.
+type LoginLimits struct {
+	// MaxFailures is a number of failures for one email, MaxFailuresPerIP for one client address.
+	MaxFailures      int
+	MaxFailuresPerIP int
+	// Window is a period in which failures are counted.
+	Window time.Duration
+	// Lockout is the first lockout, it's doubled with every next failure.
+	Lockout time.Duration
+}
+
+var ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+
+type SessionService interface {
+	CreateSession(ctx context.Context, userID int, userAgent string, ipAddress string) (string, error)
+	GetSession(ctx context.Context, sessionToken string) (*model.Session, error)
+	GetUserSessions(ctx context.Context, userID int) ([]model.Session, error)
+	DeleteSession(ctx context.Context, sessionToken string) error
+	DeleteUserSession(ctx context.Context, userID int, sessionID string) error
+	DeleteUserSessions(ctx context.Context, userID int) error
+}
+
+var (
+	ErrSessionNotFound  = errors.New("session not found")
+	ErrSessionsNotFound = errors.New("sessions not found")
 )
+
+type PasswordService interface {
+	RequestPasswordReset(ctx context.Context, email string) error
+	CheckPasswordResetToken(ctx context.Context, resetToken string) error
+	ResetPassword(ctx context.Context, resetToken string, newPassword string) error
+}
+
+var (
+	ErrPasswordResetTokenInvalid = errors.New("password reset token is invalid or expired")
+	ErrPasswordTooShort          = errors.New("password is too short")
+)
+
+type TwoFactorService interface {
+	StartEnrollment(ctx context.Context, userID int) (*TwoFactorEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, userID int, code string) ([]string, error)
+	Disable(ctx context.Context, userID int, userPassword string) error
+	CreateLoginChallenge(ctx context.Context, userID int) string
+	VerifyLoginChallenge(ctx context.Context, challenge string, code string, ipAddress string) (int, error)
+}
+
+type TwoFactorEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+var (
+	ErrTwoFactorEnabled      = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled  = errors.New("two-factor authentication enrollment is not started")
+	ErrInvalidTwoFactorCode  = errors.New("invalid two-factor authentication code")
+	ErrLoginChallengeInvalid = errors.New("login challenge is invalid or expired")
+)
+
+type APITokenService interface {
+	CreateAPIToken(ctx context.Context, userID int, input *CreateAPITokenInput) (string, error)
+	GetUserAPITokens(ctx context.Context, userID int) ([]model.APIToken, error)
+	RevokeAPIToken(ctx context.Context, userID int, tokenID int) error
+	AuthenticateAPIToken(ctx context.Context, apiToken string) (*model.APIToken, error)
+}
+
+type CreateAPITokenInput struct {
+	Name   string
+	Scopes []string
+	// TTL is a lifetime of the token, zero means that token is valid until it's revoked.
+	TTL time.Duration
+}
+
+var (
+	ErrAPITokensNotFound    = errors.New("api tokens not found")
+	ErrAPITokenNotFound     = errors.New("api token not found")
+	ErrAPITokenInvalid      = errors.New("api token is invalid or expired")
+	ErrInvalidAPITokenName  = errors.New("invalid api token name")
+	ErrInvalidAPITokenScope = errors.New("invalid api token scope")
+)
+
+type AccessService interface {
+	Authorize(ctx context.Context, user *model.WebUser, permission model.Permission) error
+	SetWebUserRole(ctx context.Context, actor *model.WebUser, userID int, role model.Role) error
+	BootstrapAdmin(ctx context.Context, email string, userPassword string) error
+}
+
+var (
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrInvalidRole      = errors.New("invalid role")
+	ErrOwnRoleChange    = errors.New("own role can't be changed")
+	ErrAdminExists      = errors.New("admin already exists")
+)
+
+type AdminService interface {
+	GetDashboard(ctx context.Context, actor *model.WebUser) (*AdminDashboard, error)
+	GetWebUsersByPage(ctx context.Context, actor *model.WebUser, page int) ([]model.WebUser, error)
+	GetUsersByPage(ctx context.Context, actor *model.WebUser, page int) ([]model.User, error)
+	SetChannelEnabled(ctx context.Context, actor *model.WebUser, channelID int, enabled bool) error
+	SetChannelHidden(ctx context.Context, actor *model.WebUser, channelID int, hidden bool) error
+	BanWebUser(ctx context.Context, actor *model.WebUser, userID int) error
+	UnbanWebUser(ctx context.Context, actor *model.WebUser, userID int) error
+	DismissIngestionError(ctx context.Context, actor *model.WebUser, id int) error
+	GetQuarantinedMessagesByPage(ctx context.Context, actor *model.WebUser, page int) ([]model.QuarantinedMessage, error)
+	DismissQuarantinedMessage(ctx context.Context, actor *model.WebUser, id int) error
+}
+
+type AdminDashboard struct {
+	Channels        []model.Channel
+	IngestionErrors []model.IngestionError
+}
+
+var ErrOwnBan = errors.New("own account can't be banned")
+
+type ModerationService interface {
+	ReportMessage(ctx context.Context, reporter *model.WebUser, messageID int, reason string) error
+	GetReportsByPage(ctx context.Context, actor *model.WebUser, page int) ([]model.ReportedMessage, error)
+	GetModerationActions(ctx context.Context, actor *model.WebUser) ([]model.ModerationAction, error)
+	SetMessageHidden(ctx context.Context, actor *model.WebUser, messageID int, hidden bool) error
+	SetReplyHidden(ctx context.Context, actor *model.WebUser, replyID int, hidden bool) error
+	DismissReports(ctx context.Context, actor *model.WebUser, messageID int) error
+}
+
+var (
+	ErrInvalidReportReason = errors.New("invalid report reason")
+	ErrReportsNotFound     = errors.New("reports not found")
+)
+
+type IngestionService interface {
+	RecordIngestionError(ctx context.Context, ingestionError *model.IngestionError) error
+	GetIngestionErrors(ctx context.Context, limit int) ([]model.IngestionError, error)
+	GetIngestionError(ctx context.Context, id int) (*model.IngestionError, error)
+	DeleteIngestionError(ctx context.Context, id int) error
+	BufferRecord(ctx context.Context, record *model.BufferedRecord) error
+	ClaimBufferedRecord(ctx context.Context, channelID int) (*model.BufferedRecord, error)
+	QuarantineMessage(ctx context.Context, message *model.QuarantinedMessage) error
+	GetQuarantinedMessage(ctx context.Context, id int) (*model.QuarantinedMessage, error)
+	DeleteQuarantinedMessage(ctx context.Context, id int) error
+}
+
+var (
+	ErrIngestionErrorsNotFound    = errors.New("ingestion errors not found")
+	ErrIngestionErrorNotFound     = errors.New("ingestion error not found")
+	ErrBufferedRecordsNotFound    = errors.New("buffered records not found")
+	ErrQuarantinedMessageNotFound = errors.New("quarantined message not found")
+)
+
+type ClassifierService interface {
+	ClassifyMessage(text string) model.MessageKind
+	ReclassifyMessages(ctx context.Context) (int, error)
+}
+
+type FilterService interface {
+	FilterMessage(channelName string, message *model.DBMessage) *FilterVerdict
+}
+
+// FilterVerdict describes which rule of the filter matched the message.
+type FilterVerdict struct {
+	Rule   string
+	Reason string
+}
+
+type HealthService interface {
+	CheckDatabase(ctx context.Context) (*model.MigrationVersion, error)
+}
+
+var ErrMigrationVersionNotFound = errors.New("migration version not found")
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+type sessionService struct {
+	store  *store.Store
+	logger *logger.Logger
+	ttl    time.Duration
+}
+
+var _ SessionService = (*sessionService)(nil)
+
+func NewSessionService(store *store.Store, logger *logger.Logger, ttl time.Duration) *sessionService {
+	return &sessionService{
+		store:  store,
+		logger: logger,
+		ttl:    ttl,
+	}
+}
+
+// CreateSession starts a new session for the user and returns its token.
+// Only hash of the token is stored, so the token can't be restored from db.
+func (s sessionService) CreateSession(ctx context.Context, userID int, userAgent string, ipAddress string) (string, error) {
+	logger := s.logger.ForContext(ctx)
+
+	// Expired sessions are cleaned up on login, so the table doesn't grow without a separate job.
+	err := s.store.Session.DeleteExpiredSessions(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete expired sessions")
+	}
+
+	sessionToken, err := token.Generate(token.DefaultLength)
+	if err != nil {
+		logger.Error().Err(err).Msg("generate session token")
+		return "", fmt.Errorf("generate session token: %w", err)
+	}
+
+	err = s.store.Session.CreateSession(ctx, &model.Session{
+		ID:        token.Hash(sessionToken),
+		UserID:    userID,
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		ExpiresAt: time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("create session")
+		return "", fmt.Errorf("create session in db: %w", err)
+	}
+
+	logger.Info().Int("user id", userID).Msg("session successfully created")
+	return sessionToken, nil
+}
+
+func (s sessionService) GetSession(ctx context.Context, sessionToken string) (*model.Session, error) {
+	logger := s.logger.ForContext(ctx)
+
+	session, err := s.store.Session.GetSessionByID(ctx, token.Hash(sessionToken))
+	if err != nil {
+		logger.Error().Err(err).Msg("get session by id")
+		return nil, fmt.Errorf("get session by id from db: %w", err)
+	}
+	if session == nil {
+		logger.Info().Msg("session not found")
+		return nil, ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (s sessionService) GetUserSessions(ctx context.Context, userID int) ([]model.Session, error) {
+	logger := s.logger.ForContext(ctx)
+
+	sessions, err := s.store.Session.GetSessionsByUserID(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("get sessions by user id")
+		return nil, fmt.Errorf("get sessions by user id from db: %w", err)
+	}
+	if sessions == nil {
+		logger.Info().Int("user id", userID).Msg("sessions not found")
+		return nil, ErrSessionsNotFound
+	}
+
+	logger.Info().Int("user id", userID).Msg("successfully got user sessions")
+	return sessions, nil
+}
+
+func (s sessionService) DeleteSession(ctx context.Context, sessionToken string) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.store.Session.DeleteSession(ctx, token.Hash(sessionToken))
+	if err != nil {
+		logger.Error().Err(err).Msg("delete session")
+		return fmt.Errorf("delete session from db: %w", err)
+	}
+
+	logger.Info().Msg("session successfully deleted")
+	return nil
+}
+
+// DeleteUserSession deletes session by its id, session of another user is treated as not found.
+func (s sessionService) DeleteUserSession(ctx context.Context, userID int, sessionID string) error {
+	logger := s.logger.ForContext(ctx)
+
+	session, err := s.store.Session.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		logger.Error().Err(err).Msg("get session by id")
+		return fmt.Errorf("get session by id from db: %w", err)
+	}
+	if session == nil || session.UserID != userID {
+		logger.Info().Int("user id", userID).Msg("user session not found")
+		return ErrSessionNotFound
+	}
+
+	err = s.store.Session.DeleteSession(ctx, sessionID)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete session")
+		return fmt.Errorf("delete session from db: %w", err)
+	}
+
+	logger.Info().Int("user id", userID).Msg("user session successfully deleted")
+	return nil
+}
+
+func (s sessionService) DeleteUserSessions(ctx context.Context, userID int) error {
+	logger := s.logger.ForContext(ctx)
+
+	err := s.store.Session.DeleteSessionsByUserID(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete sessions by user id")
+		return fmt.Errorf("delete sessions by user id from db: %w", err)
+	}
+
+	logger.Info().Int("user id", userID).Msg("user sessions successfully deleted")
+	return nil
+}
@@ -0,0 +1,327 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+const sessionTTL = time.Hour
+
+func TestSessionService_CreateSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(sessionRepo *mocks.SessionRepo)
+		expectedError error
+	}{
+		{
+			name: "CreateSession successful",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("DeleteExpiredSessions", mock.Anything).Return(nil)
+				sessionRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(session *model.Session) bool {
+					return session.UserID == 1 && session.UserAgent == "agent" && session.IPAddress == "127.0.0.1" &&
+						time.Until(session.ExpiresAt) > sessionTTL-time.Minute
+				})).Return(nil)
+			},
+		},
+		{
+			name: "CreateSession successful when expired sessions are not deleted",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("DeleteExpiredSessions", mock.Anything).Return(fmt.Errorf("some store error"))
+				sessionRepo.On("CreateSession", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "CreateSession failed with some store error",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("DeleteExpiredSessions", mock.Anything).Return(nil)
+				sessionRepo.On("CreateSession", mock.Anything, mock.Anything).Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("create session in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessionRepo := &mocks.SessionRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			sessionService := service.NewSessionService(&store.Store{Session: sessionRepo}, logger, sessionTTL)
+			tt.mock(sessionRepo)
+
+			got, err := sessionService.CreateSession(context.Background(), 1, "agent", "127.0.0.1")
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Empty(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, got)
+				sessionRepo.AssertCalled(t, "CreateSession", mock.Anything, mock.MatchedBy(func(session *model.Session) bool {
+					return session.ID == token.Hash(got)
+				}))
+			}
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionService_GetSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(sessionRepo *mocks.SessionRepo)
+		want          *model.Session
+		expectedError error
+	}{
+		{
+			name: "GetSession successful",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionByID", mock.Anything, token.Hash("token")).
+					Return(&model.Session{ID: token.Hash("token"), UserID: 1}, nil)
+			},
+			want: &model.Session{ID: token.Hash("token"), UserID: 1},
+		},
+		{
+			name: "GetSession failed with not found session",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionByID", mock.Anything, token.Hash("token")).Return(nil, nil)
+			},
+			expectedError: service.ErrSessionNotFound,
+		},
+		{
+			name: "GetSession failed with some store error",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionByID", mock.Anything, token.Hash("token")).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("get session by id from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessionRepo := &mocks.SessionRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			sessionService := service.NewSessionService(&store.Store{Session: sessionRepo}, logger, sessionTTL)
+			tt.mock(sessionRepo)
+
+			got, err := sessionService.GetSession(context.Background(), "token")
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionService_GetUserSessions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(sessionRepo *mocks.SessionRepo)
+		want          []model.Session
+		expectedError error
+	}{
+		{
+			name: "GetUserSessions successful",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionsByUserID", mock.Anything, 1).
+					Return([]model.Session{{ID: "hash1", UserID: 1}, {ID: "hash2", UserID: 1}}, nil)
+			},
+			want: []model.Session{{ID: "hash1", UserID: 1}, {ID: "hash2", UserID: 1}},
+		},
+		{
+			name: "GetUserSessions failed with not found sessions",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionsByUserID", mock.Anything, 1).Return(nil, nil)
+			},
+			expectedError: service.ErrSessionsNotFound,
+		},
+		{
+			name: "GetUserSessions failed with some store error",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionsByUserID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("get sessions by user id from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessionRepo := &mocks.SessionRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			sessionService := service.NewSessionService(&store.Store{Session: sessionRepo}, logger, sessionTTL)
+			tt.mock(sessionRepo)
+
+			got, err := sessionService.GetUserSessions(context.Background(), 1)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionService_DeleteSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(sessionRepo *mocks.SessionRepo)
+		expectedError error
+	}{
+		{
+			name: "DeleteSession successful",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("DeleteSession", mock.Anything, token.Hash("token")).Return(nil)
+			},
+		},
+		{
+			name: "DeleteSession failed with some store error",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("DeleteSession", mock.Anything, token.Hash("token")).Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("delete session from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessionRepo := &mocks.SessionRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			sessionService := service.NewSessionService(&store.Store{Session: sessionRepo}, logger, sessionTTL)
+			tt.mock(sessionRepo)
+
+			err := sessionService.DeleteSession(context.Background(), "token")
+			assert.Equal(t, tt.expectedError, err)
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionService_DeleteUserSession(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(sessionRepo *mocks.SessionRepo)
+		expectedError error
+	}{
+		{
+			name: "DeleteUserSession successful",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionByID", mock.Anything, "hash").Return(&model.Session{ID: "hash", UserID: 1}, nil)
+				sessionRepo.On("DeleteSession", mock.Anything, "hash").Return(nil)
+			},
+		},
+		{
+			name: "DeleteUserSession failed with session of another user",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionByID", mock.Anything, "hash").Return(&model.Session{ID: "hash", UserID: 2}, nil)
+			},
+			expectedError: service.ErrSessionNotFound,
+		},
+		{
+			name: "DeleteUserSession failed with not found session",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionByID", mock.Anything, "hash").Return(nil, nil)
+			},
+			expectedError: service.ErrSessionNotFound,
+		},
+		{
+			name: "DeleteUserSession failed with some store error",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("GetSessionByID", mock.Anything, "hash").Return(&model.Session{ID: "hash", UserID: 1}, nil)
+				sessionRepo.On("DeleteSession", mock.Anything, "hash").Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("delete session from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessionRepo := &mocks.SessionRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			sessionService := service.NewSessionService(&store.Store{Session: sessionRepo}, logger, sessionTTL)
+			tt.mock(sessionRepo)
+
+			err := sessionService.DeleteUserSession(context.Background(), 1, "hash")
+			assert.Equal(t, tt.expectedError, err)
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionService_DeleteUserSessions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(sessionRepo *mocks.SessionRepo)
+		expectedError error
+	}{
+		{
+			name: "DeleteUserSessions successful",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("DeleteSessionsByUserID", mock.Anything, 1).Return(nil)
+			},
+		},
+		{
+			name: "DeleteUserSessions failed with some store error",
+			mock: func(sessionRepo *mocks.SessionRepo) {
+				sessionRepo.On("DeleteSessionsByUserID", mock.Anything, 1).Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("delete sessions by user id from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sessionRepo := &mocks.SessionRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			sessionService := service.NewSessionService(&store.Store{Session: sessionRepo}, logger, sessionTTL)
+			tt.mock(sessionRepo)
+
+			err := sessionService.DeleteUserSessions(context.Background(), 1)
+			assert.Equal(t, tt.expectedError, err)
+
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+const (
+	// apiTokenPrefix makes tokens recognizable, e.g. by secret scanners.
+	apiTokenPrefix = "sbt_"
+
+	maxAPITokenNameLength = 100
+)
+
+type apiTokenService struct {
+	store  *store.Store
+	logger *logger.Logger
+}
+
+var _ APITokenService = (*apiTokenService)(nil)
+
+func NewAPITokenService(store *store.Store, logger *logger.Logger) *apiTokenService {
+	return &apiTokenService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// CreateAPIToken creates personal access token and returns it, the token can't be shown again.
+func (s apiTokenService) CreateAPIToken(ctx context.Context, userID int, input *CreateAPITokenInput) (string, error) {
+	logger := s.logger.ForContext(ctx)
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" || len(name) > maxAPITokenNameLength {
+		logger.Info().Int("user id", userID).Msg("invalid api token name")
+		return "", ErrInvalidAPITokenName
+	}
+
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		logger.Info().Strs("scopes", input.Scopes).Msg("invalid api token scopes")
+		return "", err
+	}
+
+	apiToken, err := token.Generate(token.DefaultLength)
+	if err != nil {
+		logger.Error().Err(err).Msg("generate api token")
+		return "", fmt.Errorf("generate api token: %w", err)
+	}
+
+	apiToken = apiTokenPrefix + apiToken
+
+	var expiresAt *time.Time
+	if input.TTL > 0 {
+		expiration := time.Now().Add(input.TTL)
+		expiresAt = &expiration
+	}
+
+	err = s.store.APIToken.CreateAPIToken(ctx, &model.APIToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: token.Hash(apiToken),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("create api token")
+		return "", fmt.Errorf("create api token in db: %w", err)
+	}
+
+	logger.Info().Int("user id", userID).Str("scopes", scopes).Msg("api token successfully created")
+	return apiToken, nil
+}
+
+func (s apiTokenService) GetUserAPITokens(ctx context.Context, userID int) ([]model.APIToken, error) {
+	logger := s.logger.ForContext(ctx)
+
+	apiTokens, err := s.store.APIToken.GetAPITokensByUserID(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("get api tokens by user id")
+		return nil, fmt.Errorf("get api tokens by user id from db: %w", err)
+	}
+	if apiTokens == nil {
+		logger.Info().Int("user id", userID).Msg("api tokens not found")
+		return nil, ErrAPITokensNotFound
+	}
+
+	return apiTokens, nil
+}
+
+func (s apiTokenService) RevokeAPIToken(ctx context.Context, userID int, tokenID int) error {
+	logger := s.logger.ForContext(ctx)
+
+	deleted, err := s.store.APIToken.DeleteUserAPIToken(ctx, userID, tokenID)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete user api token")
+		return fmt.Errorf("delete user api token from db: %w", err)
+	}
+	if !deleted {
+		logger.Info().Int("user id", userID).Int("token id", tokenID).Msg("user api token not found")
+		return ErrAPITokenNotFound
+	}
+
+	logger.Info().Int("user id", userID).Int("token id", tokenID).Msg("api token successfully revoked")
+	return nil
+}
+
+// AuthenticateAPIToken returns token which isn't expired or revoked and records that it's used.
+func (s apiTokenService) AuthenticateAPIToken(ctx context.Context, apiToken string) (*model.APIToken, error) {
+	logger := s.logger.ForContext(ctx)
+
+	if !strings.HasPrefix(apiToken, apiTokenPrefix) {
+		logger.Info().Msg("api token without prefix")
+		return nil, ErrAPITokenInvalid
+	}
+
+	storedToken, err := s.store.APIToken.GetAPITokenByHash(ctx, token.Hash(apiToken))
+	if err != nil {
+		logger.Error().Err(err).Msg("get api token by hash")
+		return nil, fmt.Errorf("get api token by hash from db: %w", err)
+	}
+	if storedToken == nil {
+		logger.Info().Msg("api token not found")
+		return nil, ErrAPITokenInvalid
+	}
+
+	err = s.store.APIToken.UpdateAPITokenLastUsed(ctx, storedToken.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("update api token last used")
+	}
+
+	return storedToken, nil
+}
+
+// normalizeScopes validates scopes and joins them without duplicates.
+func normalizeScopes(scopes []string) (string, error) {
+	if len(scopes) == 0 {
+		return "", ErrInvalidAPITokenScope
+	}
+
+	result := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+
+	for _, scope := range scopes {
+		switch scope {
+		case model.APITokenScopeRead, model.APITokenScopeWrite:
+		default:
+			return "", ErrInvalidAPITokenScope
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	return strings.Join(result, " "), nil
+}
@@ -0,0 +1,220 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+func TestAPITokenService_CreateAPIToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(apiTokenRepo *mocks.APITokenRepo)
+		input         *service.CreateAPITokenInput
+		expectedError error
+	}{
+		{
+			name: "CreateAPIToken successful",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("CreateAPIToken", mock.Anything, mock.MatchedBy(func(apiToken *model.APIToken) bool {
+					return apiToken.UserID == 1 && apiToken.Name == "ci" && apiToken.Scopes == "read write" &&
+						apiToken.ExpiresAt != nil && len(apiToken.TokenHash) == 64
+				})).Return(nil)
+			},
+			input: &service.CreateAPITokenInput{Name: " ci ", Scopes: []string{"read", "write", "read"}, TTL: time.Hour},
+		},
+		{
+			name: "CreateAPIToken successful without expiration",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("CreateAPIToken", mock.Anything, mock.MatchedBy(func(apiToken *model.APIToken) bool {
+					return apiToken.ExpiresAt == nil
+				})).Return(nil)
+			},
+			input: &service.CreateAPITokenInput{Name: "ci", Scopes: []string{"read"}},
+		},
+		{
+			name:          "CreateAPIToken failed with empty name",
+			mock:          func(apiTokenRepo *mocks.APITokenRepo) {},
+			input:         &service.CreateAPITokenInput{Name: " ", Scopes: []string{"read"}},
+			expectedError: service.ErrInvalidAPITokenName,
+		},
+		{
+			name:          "CreateAPIToken failed with unknown scope",
+			mock:          func(apiTokenRepo *mocks.APITokenRepo) {},
+			input:         &service.CreateAPITokenInput{Name: "ci", Scopes: []string{"read", "admin"}},
+			expectedError: service.ErrInvalidAPITokenScope,
+		},
+		{
+			name:          "CreateAPIToken failed without scopes",
+			mock:          func(apiTokenRepo *mocks.APITokenRepo) {},
+			input:         &service.CreateAPITokenInput{Name: "ci"},
+			expectedError: service.ErrInvalidAPITokenScope,
+		},
+		{
+			name: "CreateAPIToken failed with some store error",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("CreateAPIToken", mock.Anything, mock.Anything).Return(fmt.Errorf("some store error"))
+			},
+			input:         &service.CreateAPITokenInput{Name: "ci", Scopes: []string{"read"}},
+			expectedError: fmt.Errorf("create api token in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiTokenRepo := &mocks.APITokenRepo{}
+			tt.mock(apiTokenRepo)
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			apiTokenService := service.NewAPITokenService(&store.Store{APIToken: apiTokenRepo}, logger)
+
+			got, err := apiTokenService.CreateAPIToken(context.Background(), 1, tt.input)
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.True(t, strings.HasPrefix(got, "sbt_"))
+			}
+
+			apiTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPITokenService_RevokeAPIToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(apiTokenRepo *mocks.APITokenRepo)
+		expectedError error
+	}{
+		{
+			name: "RevokeAPIToken successful",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("DeleteUserAPIToken", mock.Anything, 1, 2).Return(true, nil)
+			},
+		},
+		{
+			name: "RevokeAPIToken failed with token of another user",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("DeleteUserAPIToken", mock.Anything, 1, 2).Return(false, nil)
+			},
+			expectedError: service.ErrAPITokenNotFound,
+		},
+		{
+			name: "RevokeAPIToken failed with some store error",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("DeleteUserAPIToken", mock.Anything, 1, 2).Return(false, fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("delete user api token from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiTokenRepo := &mocks.APITokenRepo{}
+			tt.mock(apiTokenRepo)
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			apiTokenService := service.NewAPITokenService(&store.Store{APIToken: apiTokenRepo}, logger)
+
+			err := apiTokenService.RevokeAPIToken(context.Background(), 1, 2)
+			assert.Equal(t, tt.expectedError, err)
+
+			apiTokenRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPITokenService_AuthenticateAPIToken(t *testing.T) {
+	t.Parallel()
+
+	stored := &model.APIToken{ID: 2, UserID: 1, Name: "ci", Scopes: "read"}
+
+	tests := []struct {
+		name          string
+		mock          func(apiTokenRepo *mocks.APITokenRepo)
+		input         string
+		want          *model.APIToken
+		expectedError error
+	}{
+		{
+			name: "AuthenticateAPIToken successful",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("GetAPITokenByHash", mock.Anything, token.Hash("sbt_token")).Return(stored, nil)
+				apiTokenRepo.On("UpdateAPITokenLastUsed", mock.Anything, 2).Return(nil)
+			},
+			input: "sbt_token",
+			want:  stored,
+		},
+		{
+			name: "AuthenticateAPIToken successful when update of last used time failed",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("GetAPITokenByHash", mock.Anything, token.Hash("sbt_token")).Return(stored, nil)
+				apiTokenRepo.On("UpdateAPITokenLastUsed", mock.Anything, 2).Return(fmt.Errorf("some store error"))
+			},
+			input: "sbt_token",
+			want:  stored,
+		},
+		{
+			name:          "AuthenticateAPIToken failed with session token",
+			mock:          func(apiTokenRepo *mocks.APITokenRepo) {},
+			input:         "token",
+			expectedError: service.ErrAPITokenInvalid,
+		},
+		{
+			name: "AuthenticateAPIToken failed with revoked or expired token",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("GetAPITokenByHash", mock.Anything, token.Hash("sbt_token")).Return(nil, nil)
+			},
+			input:         "sbt_token",
+			expectedError: service.ErrAPITokenInvalid,
+		},
+		{
+			name: "AuthenticateAPIToken failed with some store error",
+			mock: func(apiTokenRepo *mocks.APITokenRepo) {
+				apiTokenRepo.On("GetAPITokenByHash", mock.Anything, token.Hash("sbt_token")).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			input:         "sbt_token",
+			expectedError: fmt.Errorf("get api token by hash from db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			apiTokenRepo := &mocks.APITokenRepo{}
+			tt.mock(apiTokenRepo)
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			apiTokenService := service.NewAPITokenService(&store.Store{APIToken: apiTokenRepo}, logger)
+
+			got, err := apiTokenService.AuthenticateAPIToken(context.Background(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			apiTokenRepo.AssertExpectations(t)
+		})
+	}
+}
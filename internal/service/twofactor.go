@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/password"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+	"github.com/VladPetriv/scanner_backend/pkg/totp"
+)
+
+const (
+	// totpIssuer is shown by authenticator apps next to the account.
+	totpIssuer = "Telegram Overflow"
+
+	// loginChallengeTTL is a time given to enter the code after the password is checked.
+	loginChallengeTTL = 5 * time.Minute
+
+	// loginChallengePrefix separates login challenges from other signed tokens.
+	loginChallengePrefix = "2fa:"
+
+	recoveryCodesCount = 10
+
+	// recoveryCodeLength is a number of characters in the recovery code, it's shown split into halves.
+	recoveryCodeLength = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type twoFactorService struct {
+	store               *store.Store
+	logger              *logger.Logger
+	signer              *token.Signer
+	loginAttemptService LoginAttemptService
+}
+
+var _ TwoFactorService = (*twoFactorService)(nil)
+
+func NewTwoFactorService(
+	store *store.Store, logger *logger.Logger, signer *token.Signer, loginAttemptService LoginAttemptService,
+) *twoFactorService {
+	return &twoFactorService{
+		store:               store,
+		logger:              logger,
+		signer:              signer,
+		loginAttemptService: loginAttemptService,
+	}
+}
+
+// StartEnrollment generates new secret for the user, it's not required on login until enrollment is confirmed.
+func (s twoFactorService) StartEnrollment(ctx context.Context, userID int) (*TwoFactorEnrollment, error) {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.getWebUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		logger.Info().Int("user id", userID).Msg("two-factor authentication is already enabled")
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		logger.Error().Err(err).Msg("generate totp secret")
+		return nil, fmt.Errorf("generate totp secret: %w", err)
+	}
+
+	err = s.store.WebUser.UpdateWebUserTOTP(ctx, userID, secret, false)
+	if err != nil {
+		logger.Error().Err(err).Msg("update web user totp")
+		return nil, fmt.Errorf("update web user totp in db: %w", err)
+	}
+
+	logger.Info().Int("user id", userID).Msg("two-factor authentication enrollment successfully started")
+	return &TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables two-factor authentication when the code from the app is correct.
+// Recovery codes are returned only once, just their hashes are stored.
+func (s twoFactorService) ConfirmEnrollment(ctx context.Context, userID int, code string) ([]string, error) {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.getWebUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		logger.Info().Int("user id", userID).Msg("two-factor authentication is already enabled")
+		return nil, ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		logger.Info().Int("user id", userID).Msg("two-factor authentication enrollment is not started")
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	valid, err := s.checkTOTPCode(ctx, user, normalizeCode(code))
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		logger.Error().Err(err).Msg("generate recovery codes")
+		return nil, fmt.Errorf("generate recovery codes: %w", err)
+	}
+
+	err = s.store.RecoveryCode.ReplaceRecoveryCodes(ctx, userID, hashes)
+	if err != nil {
+		logger.Error().Err(err).Msg("replace recovery codes")
+		return nil, fmt.Errorf("replace recovery codes in db: %w", err)
+	}
+
+	err = s.store.WebUser.UpdateWebUserTOTP(ctx, userID, user.TOTPSecret, true)
+	if err != nil {
+		logger.Error().Err(err).Msg("update web user totp")
+		return nil, fmt.Errorf("update web user totp in db: %w", err)
+	}
+
+	logger.Info().Int("user id", userID).Msg("two-factor authentication successfully enabled")
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off, the password is required in case the session is hijacked.
+func (s twoFactorService) Disable(ctx context.Context, userID int, userPassword string) error {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.getWebUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !password.ComparePassword(userPassword, user.Password) {
+		logger.Info().Int("user id", userID).Msg("incorrect password")
+		return ErrIncorrectPassword
+	}
+
+	err = s.store.WebUser.UpdateWebUserTOTP(ctx, userID, "", false)
+	if err != nil {
+		logger.Error().Err(err).Msg("update web user totp")
+		return fmt.Errorf("update web user totp in db: %w", err)
+	}
+
+	err = s.store.RecoveryCode.DeleteRecoveryCodes(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("delete recovery codes")
+		return fmt.Errorf("delete recovery codes from db: %w", err)
+	}
+
+	logger.Info().Int("user id", userID).Msg("two-factor authentication successfully disabled")
+	return nil
+}
+
+// CreateLoginChallenge returns short-lived token which proves that the user has entered the correct password.
+func (s twoFactorService) CreateLoginChallenge(_ context.Context, userID int) string {
+	return s.signer.Sign(loginChallengePrefix+strconv.Itoa(userID), time.Now().Add(loginChallengeTTL))
+}
+
+// VerifyLoginChallenge checks the code from the app or one of the recovery codes and returns id of the user.
+// Wrong codes are counted as failed logins, so they can't be guessed without limit.
+func (s twoFactorService) VerifyLoginChallenge(
+	ctx context.Context, challenge string, code string, ipAddress string,
+) (int, error) {
+	logger := s.logger.ForContext(ctx)
+
+	data, err := s.signer.Verify(challenge, time.Now())
+	if err != nil || !strings.HasPrefix(data, loginChallengePrefix) {
+		logger.Info().Err(err).Msg("invalid login challenge")
+		return 0, ErrLoginChallengeInvalid
+	}
+
+	userID, err := strconv.Atoi(strings.TrimPrefix(data, loginChallengePrefix))
+	if err != nil {
+		logger.Info().Err(err).Msg("invalid user id in login challenge")
+		return 0, ErrLoginChallengeInvalid
+	}
+
+	user, err := s.getWebUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrWebUserNotFound) {
+			return 0, ErrLoginChallengeInvalid
+		}
+
+		return 0, err
+	}
+	if !user.TOTPEnabled {
+		logger.Info().Int("user id", userID).Msg("two-factor authentication is disabled")
+		return 0, ErrLoginChallengeInvalid
+	}
+
+	err = s.loginAttemptService.CheckLoginAttempt(ctx, user.Email, ipAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	valid, err := s.checkCode(ctx, user, normalizeCode(code))
+	if err != nil {
+		return 0, err
+	}
+	if !valid {
+		err = s.loginAttemptService.RecordLoginFailure(ctx, user.Email, ipAddress)
+		if err != nil {
+			logger.Error().Err(err).Msg("record login failure")
+		}
+
+		return 0, ErrInvalidTwoFactorCode
+	}
+
+	err = s.loginAttemptService.ResetLoginFailures(ctx, user.Email)
+	if err != nil {
+		logger.Error().Err(err).Msg("reset login failures")
+	}
+
+	return userID, nil
+}
+
+// checkCode validates code from the app, codes of other length are checked as recovery ones.
+func (s twoFactorService) checkCode(ctx context.Context, user *model.WebUser, code string) (bool, error) {
+	logger := s.logger.ForContext(ctx)
+
+	if len(code) == totp.Digits {
+		return s.checkTOTPCode(ctx, user, code)
+	}
+
+	used, err := s.store.RecoveryCode.UseRecoveryCode(ctx, user.ID, token.Hash(code))
+	if err != nil {
+		logger.Error().Err(err).Msg("use recovery code")
+		return false, fmt.Errorf("use recovery code in db: %w", err)
+	}
+	if !used {
+		logger.Info().Int("user id", user.ID).Msg("invalid recovery code")
+		return false, nil
+	}
+
+	logger.Info().Int("user id", user.ID).Msg("recovery code successfully used")
+	return true, nil
+}
+
+// checkTOTPCode validates code from the app, the code is accepted only once, so an intercepted one can't be replayed.
+func (s twoFactorService) checkTOTPCode(ctx context.Context, user *model.WebUser, code string) (bool, error) {
+	logger := s.logger.ForContext(ctx)
+
+	counter, ok := totp.ValidateCounter(user.TOTPSecret, code, time.Now())
+	if !ok {
+		logger.Info().Int("user id", user.ID).Msg("invalid totp code")
+		return false, nil
+	}
+
+	updated, err := s.store.WebUser.UpdateWebUserTOTPCounter(ctx, user.ID, int64(counter))
+	if err != nil {
+		logger.Error().Err(err).Msg("update web user totp counter")
+		return false, fmt.Errorf("update web user totp counter in db: %w", err)
+	}
+	if !updated {
+		logger.Info().Int("user id", user.ID).Msg("totp code is already used")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (s twoFactorService) getWebUser(ctx context.Context, userID int) (*model.WebUser, error) {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.store.WebUser.GetWebUserByID(ctx, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("get web user by id")
+		return nil, fmt.Errorf("get web user by id from db: %w", err)
+	}
+	if user == nil {
+		logger.Info().Int("user id", userID).Msg("web user by id not found")
+		return nil, ErrWebUserNotFound
+	}
+
+	return user, nil
+}
+
+// normalizeCode removes separators which users copy together with the code.
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// generateRecoveryCodes returns recovery codes formatted for the user and hashes of their normalized form.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+
+	data := make([]byte, recoveryCodeLength)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		if _, err := rand.Read(data); err != nil {
+			return nil, nil, fmt.Errorf("read random bytes: %w", err)
+		}
+
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(data)[:recoveryCodeLength])
+
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, token.Hash(code))
+	}
+
+	return codes, hashes, nil
+}
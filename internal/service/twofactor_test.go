@@ -0,0 +1,359 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+	"github.com/VladPetriv/scanner_backend/pkg/totp"
+)
+
+const totpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func newTwoFactorService(
+	webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo, loginFailureRepo *mocks.LoginFailureRepo,
+) service.TwoFactorService {
+	logger := logger.Get(&config.Config{LogLevel: "info"})
+
+	store := &store.Store{WebUser: webUserRepo, RecoveryCode: recoveryCodeRepo, LoginFailure: loginFailureRepo}
+
+	return service.NewTwoFactorService(
+		store, logger, token.NewSigner([]byte("secret")), service.NewLoginAttemptService(store, logger, loginLimits),
+	)
+}
+
+func currentCode(t *testing.T) string {
+	t.Helper()
+
+	code, err := totp.Code(totpSecret, time.Now())
+	require.NoError(t, err)
+
+	return code
+}
+
+func TestTwoFactorService_StartEnrollment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo)
+		expectedError error
+	}{
+		{
+			name: "StartEnrollment successful",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(&model.WebUser{ID: 1, Email: "test@test.com"}, nil)
+				webUserRepo.On("UpdateWebUserTOTP", mock.Anything, 1, mock.Anything, false).Return(nil)
+			},
+		},
+		{
+			name: "StartEnrollment failed with enabled two-factor authentication",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).
+					Return(&model.WebUser{ID: 1, Email: "test@test.com", TOTPEnabled: true}, nil)
+			},
+			expectedError: service.ErrTwoFactorEnabled,
+		},
+		{
+			name: "StartEnrollment failed with some store error",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(&model.WebUser{ID: 1, Email: "test@test.com"}, nil)
+				webUserRepo.On("UpdateWebUserTOTP", mock.Anything, 1, mock.Anything, false).
+					Return(fmt.Errorf("some store error"))
+			},
+			expectedError: fmt.Errorf("update web user totp in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+			tt.mock(webUserRepo)
+
+			got, err := newTwoFactorService(webUserRepo, &mocks.RecoveryCodeRepo{}, &mocks.LoginFailureRepo{}).
+				StartEnrollment(context.Background(), 1)
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.Len(t, got.Secret, 32)
+				assert.True(t, strings.HasPrefix(got.ProvisioningURI, "otpauth://totp/"))
+				assert.Contains(t, got.ProvisioningURI, "secret="+got.Secret)
+			}
+
+			webUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTwoFactorService_ConfirmEnrollment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo)
+		code          func(t *testing.T) string
+		expectedError error
+	}{
+		{
+			name: "ConfirmEnrollment successful",
+			mock: func(webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(&model.WebUser{ID: 1, TOTPSecret: totpSecret}, nil)
+				webUserRepo.On("UpdateWebUserTOTPCounter", mock.Anything, 1, mock.Anything).Return(true, nil)
+				recoveryCodeRepo.On("ReplaceRecoveryCodes", mock.Anything, 1, mock.MatchedBy(func(hashes []string) bool {
+					return len(hashes) == 10
+				})).Return(nil)
+				webUserRepo.On("UpdateWebUserTOTP", mock.Anything, 1, totpSecret, true).Return(nil)
+			},
+			code: currentCode,
+		},
+		{
+			name: "ConfirmEnrollment failed with invalid code",
+			mock: func(webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(&model.WebUser{ID: 1, TOTPSecret: totpSecret}, nil)
+			},
+			code:          func(t *testing.T) string { return "abcdef" },
+			expectedError: service.ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "ConfirmEnrollment failed with not started enrollment",
+			mock: func(webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(&model.WebUser{ID: 1}, nil)
+			},
+			code:          currentCode,
+			expectedError: service.ErrTwoFactorNotEnrolled,
+		},
+		{
+			name: "ConfirmEnrollment failed with some store error",
+			mock: func(webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(&model.WebUser{ID: 1, TOTPSecret: totpSecret}, nil)
+				webUserRepo.On("UpdateWebUserTOTPCounter", mock.Anything, 1, mock.Anything).Return(true, nil)
+				recoveryCodeRepo.On("ReplaceRecoveryCodes", mock.Anything, 1, mock.Anything).
+					Return(fmt.Errorf("some store error"))
+			},
+			code:          currentCode,
+			expectedError: fmt.Errorf("replace recovery codes in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+			recoveryCodeRepo := &mocks.RecoveryCodeRepo{}
+			tt.mock(webUserRepo, recoveryCodeRepo)
+
+			got, err := newTwoFactorService(webUserRepo, recoveryCodeRepo, &mocks.LoginFailureRepo{}).
+				ConfirmEnrollment(context.Background(), 1, tt.code(t))
+			assert.Equal(t, tt.expectedError, err)
+
+			if tt.expectedError == nil {
+				assert.Len(t, got, 10)
+				assert.Regexp(t, "^[a-z2-7]{5}-[a-z2-7]{5}$", got[0])
+			}
+
+			webUserRepo.AssertExpectations(t)
+			recoveryCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	t.Parallel()
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := &model.WebUser{ID: 1, Password: string(hashedPassword), TOTPSecret: totpSecret, TOTPEnabled: true}
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo)
+		input         string
+		expectedError error
+	}{
+		{
+			name: "Disable successful",
+			mock: func(webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(user, nil)
+				webUserRepo.On("UpdateWebUserTOTP", mock.Anything, 1, "", false).Return(nil)
+				recoveryCodeRepo.On("DeleteRecoveryCodes", mock.Anything, 1).Return(nil)
+			},
+			input: "password",
+		},
+		{
+			name: "Disable failed with incorrect password",
+			mock: func(webUserRepo *mocks.WebUserRepo, recoveryCodeRepo *mocks.RecoveryCodeRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(user, nil)
+			},
+			input:         "incorrect",
+			expectedError: service.ErrIncorrectPassword,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+			recoveryCodeRepo := &mocks.RecoveryCodeRepo{}
+			tt.mock(webUserRepo, recoveryCodeRepo)
+
+			twoFactorService := newTwoFactorService(webUserRepo, recoveryCodeRepo, &mocks.LoginFailureRepo{})
+
+			err := twoFactorService.Disable(context.Background(), 1, tt.input)
+			assert.Equal(t, tt.expectedError, err)
+
+			webUserRepo.AssertExpectations(t)
+			recoveryCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTwoFactorService_VerifyLoginChallenge(t *testing.T) {
+	t.Parallel()
+
+	enabledUser := &model.WebUser{ID: 1, Email: "test@test.com", TOTPSecret: totpSecret, TOTPEnabled: true}
+
+	tests := []struct {
+		name          string
+		mock          func(repos twoFactorRepos)
+		challenge     func(s service.TwoFactorService) string
+		code          func(t *testing.T) string
+		want          int
+		expectedError error
+	}{
+		{
+			name: "VerifyLoginChallenge successful with totp code",
+			mock: func(repos twoFactorRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 1).Return(enabledUser, nil)
+				expectLoginAttempt(repos.loginFailure)
+				repos.webUser.On("UpdateWebUserTOTPCounter", mock.Anything, 1, mock.Anything).Return(true, nil)
+				repos.loginFailure.On("DeleteLoginFailuresByEmail", mock.Anything, "test@test.com").Return(nil)
+			},
+			code: currentCode,
+			want: 1,
+		},
+		{
+			name: "VerifyLoginChallenge failed with replayed totp code",
+			mock: func(repos twoFactorRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 1).Return(enabledUser, nil)
+				expectLoginAttempt(repos.loginFailure)
+				repos.webUser.On("UpdateWebUserTOTPCounter", mock.Anything, 1, mock.Anything).Return(false, nil)
+				repos.loginFailure.On("DeleteLoginFailuresBefore", mock.Anything, mock.Anything).Return(nil)
+				repos.loginFailure.On("CreateLoginFailure", mock.Anything, mock.Anything).Return(nil)
+			},
+			code:          currentCode,
+			expectedError: service.ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "VerifyLoginChallenge successful with recovery code",
+			mock: func(repos twoFactorRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 1).Return(enabledUser, nil)
+				expectLoginAttempt(repos.loginFailure)
+				repos.recoveryCode.On("UseRecoveryCode", mock.Anything, 1, token.Hash("abcdefghij")).Return(true, nil)
+				repos.loginFailure.On("DeleteLoginFailuresByEmail", mock.Anything, "test@test.com").Return(nil)
+			},
+			code: func(t *testing.T) string { return "ABCDE-FGHIJ" },
+			want: 1,
+		},
+		{
+			name: "VerifyLoginChallenge failed with used recovery code",
+			mock: func(repos twoFactorRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 1).Return(enabledUser, nil)
+				expectLoginAttempt(repos.loginFailure)
+				repos.recoveryCode.On("UseRecoveryCode", mock.Anything, 1, token.Hash("abcdefghij")).Return(false, nil)
+				repos.loginFailure.On("DeleteLoginFailuresBefore", mock.Anything, mock.Anything).Return(nil)
+				repos.loginFailure.On("CreateLoginFailure", mock.Anything, mock.Anything).Return(nil)
+			},
+			code:          func(t *testing.T) string { return "abcde-fghij" },
+			expectedError: service.ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "VerifyLoginChallenge failed with locked out email",
+			mock: func(repos twoFactorRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 1).Return(enabledUser, nil)
+				repos.loginFailure.On("GetLoginFailuresByEmail", mock.Anything, "test@test.com", mock.Anything).
+					Return(&model.LoginFailureStats{Count: 5, LastAt: time.Now().UTC()}, nil)
+			},
+			code:          currentCode,
+			expectedError: service.ErrTooManyLoginAttempts,
+		},
+		{
+			name: "VerifyLoginChallenge failed with disabled two-factor authentication",
+			mock: func(repos twoFactorRepos) {
+				repos.webUser.On("GetWebUserByID", mock.Anything, 1).Return(&model.WebUser{ID: 1}, nil)
+			},
+			code:          currentCode,
+			expectedError: service.ErrLoginChallengeInvalid,
+		},
+		{
+			name: "VerifyLoginChallenge failed with forged challenge",
+			mock: func(repos twoFactorRepos) {},
+			challenge: func(s service.TwoFactorService) string {
+				return token.NewSigner([]byte("another")).Sign("2fa:1", time.Now().Add(time.Minute))
+			},
+			code:          currentCode,
+			expectedError: service.ErrLoginChallengeInvalid,
+		},
+		{
+			name: "VerifyLoginChallenge failed with token of email verification",
+			mock: func(repos twoFactorRepos) {},
+			challenge: func(s service.TwoFactorService) string {
+				return token.NewSigner([]byte("secret")).Sign("1:test@test.com", time.Now().Add(time.Minute))
+			},
+			code:          currentCode,
+			expectedError: service.ErrLoginChallengeInvalid,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+			recoveryCodeRepo := &mocks.RecoveryCodeRepo{}
+			loginFailureRepo := &mocks.LoginFailureRepo{}
+			tt.mock(twoFactorRepos{
+				webUser: webUserRepo, recoveryCode: recoveryCodeRepo, loginFailure: loginFailureRepo,
+			})
+
+			twoFactorService := newTwoFactorService(webUserRepo, recoveryCodeRepo, loginFailureRepo)
+
+			challenge := twoFactorService.CreateLoginChallenge(context.Background(), 1)
+			if tt.challenge != nil {
+				challenge = tt.challenge(twoFactorService)
+			}
+
+			got, err := twoFactorService.VerifyLoginChallenge(context.Background(), challenge, tt.code(t), "127.0.0.1")
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			webUserRepo.AssertExpectations(t)
+			recoveryCodeRepo.AssertExpectations(t)
+			loginFailureRepo.AssertExpectations(t)
+		})
+	}
+}
+
+type twoFactorRepos struct {
+	webUser      *mocks.WebUserRepo
+	recoveryCode *mocks.RecoveryCodeRepo
+	loginFailure *mocks.LoginFailureRepo
+}
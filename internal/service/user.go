@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -25,33 +26,87 @@ func NewUserService(store *store.Store, logger *logger.Logger, message MessageSe
 	}
 }
 
-func (s userService) CreateUser(user *model.User) (int, error) {
-	logger := s.logger
+// CreateUser creates Telegram user or refreshes profile of the existing one and returns user id.
+// Changed users are upserted by the store, which resolves usernames taken by other Telegram users.
+func (s userService) CreateUser(ctx context.Context, user *model.User) (int, error) {
+	logger := s.logger.ForContext(ctx)
 
-	candidate, err := s.store.User.GetUserByUsername(user.Username)
+	candidate, err := s.getUserCandidate(ctx, user)
 	if err != nil {
-		logger.Error().Err(err).Msg("get user by username")
-		return 0, fmt.Errorf("get user by username from db: %w", err)
+		return 0, err
 	}
-	if candidate != nil {
+
+	if candidate != nil && !isUserChanged(candidate, user) {
 		logger.Info().Msg("user found, don't create new user")
 		return candidate.ID, nil
 	}
 
-	id, err := s.store.User.CreateUser(user)
+	id, err := s.store.User.CreateUser(ctx, user)
 	if err != nil {
 		logger.Error().Err(err).Msg("create user")
-		return id, fmt.Errorf("create user in db: %w", err)
+		return 0, fmt.Errorf("create user in db: %w", err)
 	}
 
-	logger.Info().Int("userID", id).Msg("user successfully created")
+	logger.Info().Int("userID", id).Msg("user successfully saved")
 	return id, nil
 }
 
-func (s userService) ProcessUserPage(userID int) (*LoadUserOutput, error) {
-	logger := s.logger
+// getUserCandidate looks for existing user by Telegram id and falls back to username,
+// user found by username is a candidate only when it doesn't belong to another Telegram id.
+func (s userService) getUserCandidate(ctx context.Context, user *model.User) (*model.User, error) {
+	logger := s.logger.ForContext(ctx)
 
-	user, err := s.store.User.GetUserByID(userID)
+	if user.TgID != nil {
+		candidate, err := s.store.User.GetUserByTgID(ctx, *user.TgID)
+		if err != nil {
+			logger.Error().Err(err).Msg("get user by telegram id")
+			return nil, fmt.Errorf("get user by telegram id from db: %w", err)
+		}
+		if candidate != nil {
+			return candidate, nil
+		}
+	}
+
+	candidate, err := s.store.User.GetUserByUsername(ctx, user.Username)
+	if err != nil {
+		logger.Error().Err(err).Msg("get user by username")
+		return nil, fmt.Errorf("get user by username from db: %w", err)
+	}
+	if candidate != nil && user.TgID != nil && candidate.TgID != nil {
+		logger.Info().Str("username", user.Username).Msg("username belongs to another telegram user")
+		return nil, nil
+	}
+
+	return candidate, nil
+}
+
+func isUserChanged(candidate *model.User, user *model.User) bool {
+	if user.TgID != nil && (candidate.TgID == nil || *candidate.TgID != *user.TgID) {
+		return true
+	}
+
+	return candidate.Username != user.Username ||
+		candidate.FullName != user.FullName ||
+		candidate.ImageURL != user.ImageURL
+}
+
+func (s userService) CreateUsers(ctx context.Context, users []model.User) (map[string]int, error) {
+	logger := s.logger.ForContext(ctx)
+
+	ids, err := s.store.User.CreateUsers(ctx, users)
+	if err != nil {
+		logger.Error().Err(err).Msg("create users")
+		return nil, fmt.Errorf("create users in db: %w", err)
+	}
+
+	logger.Info().Int("users count", len(ids)).Msg("users successfully created")
+	return ids, nil
+}
+
+func (s userService) ProcessUserPage(ctx context.Context, userID int) (*LoadUserOutput, error) {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.store.User.GetUserByID(ctx, userID)
 	if err != nil {
 		logger.Error().Err(err).Msg("get user by id")
 		return nil, fmt.Errorf("get user by id from db: %w", err)
@@ -61,12 +116,19 @@ func (s userService) ProcessUserPage(userID int) (*LoadUserOutput, error) {
 		return nil, ErrUserNotFound
 	}
 
-	messages, err := s.message.GetFullMessagesByUserID(user.ID)
+	history, err := s.store.User.GetUserHistory(ctx, user.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("get user history")
+		return nil, fmt.Errorf("get user history from db: %w", err)
+	}
+
+	messages, err := s.message.GetFullMessagesByUserID(ctx, user.ID)
 	if err != nil {
 		if errors.Is(err, ErrMessagesNotFound) {
 			logger.Info().Int("user id", user.ID).Msg("messages by user id not found")
 			return &LoadUserOutput{
-				TgUser: user,
+				TgUser:  user,
+				History: history,
 			}, nil
 		}
 
@@ -76,6 +138,7 @@ func (s userService) ProcessUserPage(userID int) (*LoadUserOutput, error) {
 
 	return &LoadUserOutput{
 		TgUser:        user,
+		History:       history,
 		Messages:      messages,
 		MessagesCount: len(messages),
 	}, nil
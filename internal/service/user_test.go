@@ -1,10 +1,12 @@
 package service_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 	"github.com/VladPetriv/scanner_backend/internal/service"
@@ -18,6 +20,8 @@ func TestUserService_CreateUser(t *testing.T) {
 	t.Parallel()
 
 	userInput := &model.User{Username: "test", FullName: "test test", ImageURL: "test.jpg"}
+	tgID := int64(100)
+	otherTgID := int64(200)
 
 	tests := []struct {
 		name          string
@@ -29,8 +33,8 @@ func TestUserService_CreateUser(t *testing.T) {
 		{
 			name: "CreateUser successful",
 			mock: func(userRepo *mocks.UserRepo) {
-				userRepo.On("GetUserByUsername", userInput.Username).Return(nil, nil)
-				userRepo.On("CreateUser", userInput).Return(1, nil)
+				userRepo.On("GetUserByUsername", mock.Anything, userInput.Username).Return(nil, nil)
+				userRepo.On("CreateUser", mock.Anything, userInput).Return(1, nil)
 			},
 			input: userInput,
 			want:  1,
@@ -38,15 +42,73 @@ func TestUserService_CreateUser(t *testing.T) {
 		{
 			name: "CreateUser successful with existed user",
 			mock: func(userRepo *mocks.UserRepo) {
-				userRepo.On("GetUserByUsername", userInput.Username).Return(userInput, nil)
+				userRepo.On("GetUserByUsername", mock.Anything, userInput.Username).Return(userInput, nil)
 			},
 			input: userInput,
 			want:  userInput.ID,
 		},
+		{
+			name: "CreateUser successful with updated profile of existed user",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("GetUserByUsername", mock.Anything, "test").
+					Return(&model.User{ID: 2, Username: "test", FullName: "old", ImageURL: "old.jpg"}, nil)
+				userRepo.On("CreateUser", mock.Anything, &model.User{Username: "test", FullName: "new", ImageURL: "new.jpg"}).
+					Return(2, nil)
+			},
+			input: &model.User{Username: "test", FullName: "new", ImageURL: "new.jpg"},
+			want:  2,
+		},
+		{
+			name: "CreateUser successful with renamed user found by telegram id",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("GetUserByTgID", mock.Anything, tgID).
+					Return(&model.User{ID: 3, TgID: &tgID, Username: "old", FullName: "test", ImageURL: "test.jpg"}, nil)
+				userRepo.On("CreateUser", mock.Anything, &model.User{TgID: &tgID, Username: "new", FullName: "test", ImageURL: "test.jpg"}).
+					Return(3, nil)
+			},
+			input: &model.User{TgID: &tgID, Username: "new", FullName: "test", ImageURL: "test.jpg"},
+			want:  3,
+		},
+		{
+			name: "CreateUser successful with rename onto username taken by another user",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("GetUserByTgID", mock.Anything, tgID).
+					Return(&model.User{ID: 3, TgID: &tgID, Username: "old", FullName: "test", ImageURL: "test.jpg"}, nil)
+				userRepo.On("CreateUser", mock.Anything, &model.User{TgID: &tgID, Username: "taken", FullName: "test", ImageURL: "test.jpg"}).
+					Return(3, nil)
+			},
+			input: &model.User{TgID: &tgID, Username: "taken", FullName: "test", ImageURL: "test.jpg"},
+			want:  3,
+		},
+		{
+			name: "CreateUser successful with username reused by another telegram id",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("GetUserByTgID", mock.Anything, tgID).Return(nil, nil)
+				userRepo.On("GetUserByUsername", mock.Anything, "test").
+					Return(&model.User{ID: 2, TgID: &otherTgID, Username: "test", FullName: "test", ImageURL: "test.jpg"}, nil)
+				userRepo.On("CreateUser", mock.Anything, &model.User{TgID: &tgID, Username: "test", FullName: "test", ImageURL: "test.jpg"}).
+					Return(4, nil)
+			},
+			input: &model.User{TgID: &tgID, Username: "test", FullName: "test", ImageURL: "test.jpg"},
+			want:  4,
+		},
+		{
+			name: "CreateUser failed with error when save changed user",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("GetUserByUsername", mock.Anything, "test").
+					Return(&model.User{ID: 2, Username: "test", FullName: "old", ImageURL: "old.jpg"}, nil)
+				userRepo.On("CreateUser", mock.Anything, &model.User{Username: "test", FullName: "new", ImageURL: "new.jpg"}).
+					Return(0, fmt.Errorf("some store error"))
+			},
+			input: &model.User{Username: "test", FullName: "new", ImageURL: "new.jpg"},
+			expectedError: fmt.Errorf(
+				"create user in db: %w", fmt.Errorf("some store error"),
+			),
+		},
 		{
 			name: "CreateUser failed with error when get user by username",
 			mock: func(userRepo *mocks.UserRepo) {
-				userRepo.On("GetUserByUsername", userInput.Username).
+				userRepo.On("GetUserByUsername", mock.Anything, userInput.Username).
 					Return(nil, fmt.Errorf("some store error"))
 			},
 			input: userInput,
@@ -57,8 +119,8 @@ func TestUserService_CreateUser(t *testing.T) {
 		{
 			name: "CreateUser failed with error when create user",
 			mock: func(userRepo *mocks.UserRepo) {
-				userRepo.On("GetUserByUsername", userInput.Username).Return(nil, nil)
-				userRepo.On("CreateUser", userInput).Return(0, fmt.Errorf("some store error"))
+				userRepo.On("GetUserByUsername", mock.Anything, userInput.Username).Return(nil, nil)
+				userRepo.On("CreateUser", mock.Anything, userInput).Return(0, fmt.Errorf("some store error"))
 			},
 			input: userInput,
 			expectedError: fmt.Errorf(
@@ -81,7 +143,7 @@ func TestUserService_CreateUser(t *testing.T) {
 			userService := service.NewUserService(&store.Store{User: userRepo}, log, messageService)
 			tt.mock(userRepo)
 
-			got, err := userService.CreateUser(tt.input)
+			got, err := userService.CreateUser(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -92,6 +154,60 @@ func TestUserService_CreateUser(t *testing.T) {
 	}
 }
 
+func TestUserService_CreateUsers(t *testing.T) {
+	t.Parallel()
+
+	usersInput := []model.User{
+		{Username: "test1", FullName: "test test1", ImageURL: "test1.jpg"},
+		{Username: "test2", FullName: "test test2", ImageURL: "test2.jpg"},
+	}
+
+	tests := []struct {
+		name          string
+		mock          func(userRepo *mocks.UserRepo)
+		input         []model.User
+		want          map[string]int
+		expectedError error
+	}{
+		{
+			name: "CreateUsers successful",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("CreateUsers", mock.Anything, usersInput).Return(map[string]int{"test1": 1, "test2": 2}, nil)
+			},
+			input: usersInput,
+			want:  map[string]int{"test1": 1, "test2": 2},
+		},
+		{
+			name: "CreateUsers failed with some store error",
+			mock: func(userRepo *mocks.UserRepo) {
+				userRepo.On("CreateUsers", mock.Anything, usersInput).Return(nil, fmt.Errorf("some store error"))
+			},
+			input: usersInput,
+			expectedError: fmt.Errorf(
+				"create users in db: %w", fmt.Errorf("some store error"),
+			),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			userRepo := &mocks.UserRepo{}
+			log := logger.Get(&config.Config{LogLevel: "info"})
+
+			userService := service.NewUserService(&store.Store{User: userRepo}, log, nil)
+			tt.mock(userRepo)
+
+			got, err := userService.CreateUsers(context.Background(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUserService_ProcessUserPage(t *testing.T) {
 	t.Parallel()
 
@@ -105,14 +221,17 @@ func TestUserService_ProcessUserPage(t *testing.T) {
 		{
 			name: "ProcessUserPage successful",
 			mock: func(userRepo *mocks.UserRepo, messageRepo *mocks.MessageRepo) {
-				userRepo.On("GetUserByID", 1).Return(&model.User{
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{
 					ID:       1,
 					Username: "test",
 					FullName: "test test",
 					ImageURL: "test.jpg",
 				}, nil)
+				userRepo.On("GetUserHistory", mock.Anything, 1).Return([]model.UserHistory{
+					{ID: 1, UserID: 1, Username: "old", FullName: "old test"},
+				}, nil)
 
-				messageRepo.On("GetFullMessagesByUserID", 1).Return([]model.FullMessage{
+				messageRepo.On("GetFullMessagesByUserID", mock.Anything, 1).Return([]model.FullMessage{
 					{ID: 1, Title: "test", UserID: 1},
 					{ID: 2, Title: "test2", UserID: 1},
 				}, nil)
@@ -125,6 +244,9 @@ func TestUserService_ProcessUserPage(t *testing.T) {
 					FullName: "test test",
 					ImageURL: "test.jpg",
 				},
+				History: []model.UserHistory{
+					{ID: 1, UserID: 1, Username: "old", FullName: "old test"},
+				},
 				Messages: []model.FullMessage{
 					{ID: 1, Title: "test", UserID: 1},
 					{ID: 2, Title: "test2", UserID: 1},
@@ -135,7 +257,7 @@ func TestUserService_ProcessUserPage(t *testing.T) {
 		{
 			name: "ProcessUserPage failed with not found user",
 			mock: func(userRepo *mocks.UserRepo, messageRepo *mocks.MessageRepo) {
-				userRepo.On("GetUserByID", 1).Return(nil, nil)
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(nil, nil)
 			},
 			input:         1,
 			expectedError: service.ErrUserNotFound,
@@ -143,7 +265,7 @@ func TestUserService_ProcessUserPage(t *testing.T) {
 		{
 			name: "ProcessUserPage failed with not found user",
 			mock: func(userRepo *mocks.UserRepo, messageRepo *mocks.MessageRepo) {
-				userRepo.On("GetUserByID", 1).Return(nil, nil)
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(nil, nil)
 			},
 			input:         1,
 			expectedError: service.ErrUserNotFound,
@@ -151,8 +273,9 @@ func TestUserService_ProcessUserPage(t *testing.T) {
 		{
 			name: "ProcessUserPage failed with not found user",
 			mock: func(userRepo *mocks.UserRepo, messageRepo *mocks.MessageRepo) {
-				userRepo.On("GetUserByID", 1).Return(&model.User{ID: 1}, nil)
-				messageRepo.On("GetFullMessagesByUserID", 1).Return(nil, nil)
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1}, nil)
+				userRepo.On("GetUserHistory", mock.Anything, 1).Return(nil, nil)
+				messageRepo.On("GetFullMessagesByUserID", mock.Anything, 1).Return(nil, nil)
 			},
 			input: 1,
 			want: &service.LoadUserOutput{
@@ -162,16 +285,26 @@ func TestUserService_ProcessUserPage(t *testing.T) {
 		{
 			name: "ProcessUserPage failed with some store error while get user by id",
 			mock: func(userRepo *mocks.UserRepo, messageRepo *mocks.MessageRepo) {
-				userRepo.On("GetUserByID", 1).Return(nil, fmt.Errorf("some store error"))
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			input:         1,
 			expectedError: fmt.Errorf("get user by id from db: %w", fmt.Errorf("some store error")),
 		},
+		{
+			name: "ProcessUserPage failed with some store error while get user history",
+			mock: func(userRepo *mocks.UserRepo, messageRepo *mocks.MessageRepo) {
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1}, nil)
+				userRepo.On("GetUserHistory", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
+			},
+			input:         1,
+			expectedError: fmt.Errorf("get user history from db: %w", fmt.Errorf("some store error")),
+		},
 		{
 			name: "ProcessUserPage failed with some store error while get messages user by id",
 			mock: func(userRepo *mocks.UserRepo, messageRepo *mocks.MessageRepo) {
-				userRepo.On("GetUserByID", 1).Return(&model.User{ID: 1}, nil)
-				messageRepo.On("GetFullMessagesByUserID", 1).Return(nil, fmt.Errorf("some store error"))
+				userRepo.On("GetUserByID", mock.Anything, 1).Return(&model.User{ID: 1}, nil)
+				userRepo.On("GetUserHistory", mock.Anything, 1).Return(nil, nil)
+				messageRepo.On("GetFullMessagesByUserID", mock.Anything, 1).Return(nil, fmt.Errorf("some store error"))
 			},
 			input: 1,
 			expectedError: fmt.Errorf(
@@ -197,7 +330,7 @@ func TestUserService_ProcessUserPage(t *testing.T) {
 			userService := service.NewUserService(&store.Store{User: userRepo}, log, messageService)
 			tt.mock(userRepo, messageRepo)
 
-			got, err := userService.ProcessUserPage(tt.input)
+			got, err := userService.ProcessUserPage(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/mailer"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+type verificationService struct {
+	store   *store.Store
+	logger  *logger.Logger
+	mailer  mailer.Mailer
+	signer  *token.Signer
+	baseURL string
+	ttl     time.Duration
+}
+
+var _ VerificationService = (*verificationService)(nil)
+
+func NewVerificationService(
+	store *store.Store, logger *logger.Logger, mailer mailer.Mailer, signer *token.Signer, baseURL string, ttl time.Duration,
+) *verificationService {
+	return &verificationService{
+		store:   store,
+		logger:  logger,
+		mailer:  mailer,
+		signer:  signer,
+		baseURL: baseURL,
+		ttl:     ttl,
+	}
+}
+
+// SendVerification emails signed verification link to the user.
+// The link carries user id and email, so it stops working when the email is changed.
+func (s verificationService) SendVerification(ctx context.Context, email string) error {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.store.WebUser.GetWebUserByEmail(ctx, email)
+	if err != nil {
+		logger.Error().Err(err).Msg("get web user by email")
+		return fmt.Errorf("get web user by email from db: %w", err)
+	}
+	if user == nil {
+		logger.Info().Str("user email", email).Msg("web user by email not found")
+		return ErrWebUserNotFound
+	}
+	if user.EmailVerified {
+		logger.Info().Int("user id", user.ID).Msg("email is already verified")
+		return ErrEmailAlreadyVerified
+	}
+
+	verificationToken := s.signer.Sign(fmt.Sprintf("%d:%s", user.ID, user.Email), time.Now().Add(s.ttl))
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email",
+		Body: fmt.Sprintf(
+			"Follow the link to confirm your email, it's valid for %s:\n\n%s/auth/verify-email?token=%s\n\n"+
+				"If you didn't create an account, just ignore this email.",
+			s.ttl, s.baseURL, url.QueryEscape(verificationToken),
+		),
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("send verification email")
+		return fmt.Errorf("send verification email: %w", err)
+	}
+
+	logger.Info().Int("user id", user.ID).Msg("verification email successfully sent")
+	return nil
+}
+
+func (s verificationService) VerifyEmail(ctx context.Context, verificationToken string) error {
+	logger := s.logger.ForContext(ctx)
+
+	data, err := s.signer.Verify(verificationToken, time.Now())
+	if err != nil {
+		if errors.Is(err, token.ErrInvalidSignature) || errors.Is(err, token.ErrTokenExpired) {
+			logger.Info().Err(err).Msg("invalid verification token")
+			return ErrVerificationTokenInvalid
+		}
+
+		logger.Error().Err(err).Msg("verify verification token")
+		return fmt.Errorf("verify verification token: %w", err)
+	}
+
+	rawID, email, _ := strings.Cut(data, ":")
+
+	userID, err := strconv.Atoi(rawID)
+	if err != nil {
+		logger.Info().Err(err).Msg("invalid user id in verification token")
+		return ErrVerificationTokenInvalid
+	}
+
+	verified, err := s.store.WebUser.VerifyWebUserEmail(ctx, userID, email)
+	if err != nil {
+		logger.Error().Err(err).Msg("verify web user email")
+		return fmt.Errorf("verify web user email in db: %w", err)
+	}
+	if !verified {
+		logger.Info().Int("user id", userID).Msg("web user with verified email not found")
+		return ErrVerificationTokenInvalid
+	}
+
+	logger.Info().Int("user id", userID).Msg("email successfully verified")
+	return nil
+}
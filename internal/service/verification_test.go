@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/service"
+	"github.com/VladPetriv/scanner_backend/internal/store"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+func TestVerificationService_SendVerification(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo)
+		mailerError   error
+		expectedMails int
+		expectedError error
+	}{
+		{
+			name: "SendVerification successful",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com"}, nil)
+			},
+			expectedMails: 1,
+		},
+		{
+			name: "SendVerification failed with already verified email",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com", EmailVerified: true}, nil)
+			},
+			expectedError: service.ErrEmailAlreadyVerified,
+		},
+		{
+			name: "SendVerification failed with not found user",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, nil)
+			},
+			expectedError: service.ErrWebUserNotFound,
+		},
+		{
+			name: "SendVerification failed with mailer error",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
+					Return(&model.WebUser{ID: 1, Email: "test@test.com"}, nil)
+			},
+			mailerError:   fmt.Errorf("some mailer error"),
+			expectedError: fmt.Errorf("send verification email: %w", fmt.Errorf("some mailer error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+			mailer := &fakeMailer{err: tt.mailerError}
+			signer := token.NewSigner([]byte("secret"))
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			verificationService := service.NewVerificationService(
+				&store.Store{WebUser: webUserRepo}, logger, mailer, signer, "http://localhost:8080", time.Hour,
+			)
+			tt.mock(webUserRepo)
+
+			err := verificationService.SendVerification(context.Background(), "test@test.com")
+			assert.Equal(t, tt.expectedError, err)
+			assert.Len(t, mailer.messages, tt.expectedMails)
+
+			if tt.expectedMails > 0 {
+				link := mailer.messages[0].Body[strings.Index(mailer.messages[0].Body, "http://"):]
+				link = strings.Fields(link)[0]
+
+				parsed, err := url.Parse(link)
+				assert.NoError(t, err)
+				assert.Equal(t, "/auth/verify-email", parsed.Path)
+
+				data, err := signer.Verify(parsed.Query().Get("token"), time.Now())
+				assert.NoError(t, err)
+				assert.Equal(t, "1:test@test.com", data)
+			}
+
+			webUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVerificationService_VerifyEmail(t *testing.T) {
+	t.Parallel()
+
+	signer := token.NewSigner([]byte("secret"))
+	validToken := signer.Sign("1:test@test.com", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo)
+		input         string
+		expectedError error
+	}{
+		{
+			name: "VerifyEmail successful",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("VerifyWebUserEmail", mock.Anything, 1, "test@test.com").Return(true, nil)
+			},
+			input: validToken,
+		},
+		{
+			name: "VerifyEmail failed with changed email",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("VerifyWebUserEmail", mock.Anything, 1, "test@test.com").Return(false, nil)
+			},
+			input:         validToken,
+			expectedError: service.ErrVerificationTokenInvalid,
+		},
+		{
+			name:          "VerifyEmail failed with expired token",
+			mock:          func(webUserRepo *mocks.WebUserRepo) {},
+			input:         signer.Sign("1:test@test.com", time.Now().Add(-time.Hour)),
+			expectedError: service.ErrVerificationTokenInvalid,
+		},
+		{
+			name:          "VerifyEmail failed with token signed by another key",
+			mock:          func(webUserRepo *mocks.WebUserRepo) {},
+			input:         token.NewSigner([]byte("another")).Sign("1:test@test.com", time.Now().Add(time.Hour)),
+			expectedError: service.ErrVerificationTokenInvalid,
+		},
+		{
+			name: "VerifyEmail failed with some store error",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("VerifyWebUserEmail", mock.Anything, 1, "test@test.com").
+					Return(false, fmt.Errorf("some store error"))
+			},
+			input:         validToken,
+			expectedError: fmt.Errorf("verify web user email in db: %w", fmt.Errorf("some store error")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			verificationService := service.NewVerificationService(
+				&store.Store{WebUser: webUserRepo}, logger, &fakeMailer{}, signer, "http://localhost:8080", time.Hour,
+			)
+			tt.mock(webUserRepo)
+
+			err := verificationService.VerifyEmail(context.Background(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+
+			webUserRepo.AssertExpectations(t)
+		})
+	}
+}
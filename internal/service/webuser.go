@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
@@ -22,10 +23,10 @@ func NewWebUserService(store *store.Store, logger *logger.Logger) *webUserServic
 	}
 }
 
-func (s webUserService) CreateWebUser(user *model.WebUser) error {
-	logger := s.logger
+func (s webUserService) CreateWebUser(ctx context.Context, user *model.WebUser) error {
+	logger := s.logger.ForContext(ctx)
 
-	err := s.store.WebUser.CreateWebUser(user)
+	err := s.store.WebUser.CreateWebUser(ctx, user)
 	if err != nil {
 		logger.Error().Err(err).Msg("create web user")
 		return fmt.Errorf("create web user in db: %w", err)
@@ -35,10 +36,10 @@ func (s webUserService) CreateWebUser(user *model.WebUser) error {
 	return nil
 }
 
-func (s webUserService) GetWebUserByEmail(email string) (*model.WebUser, error) {
-	logger := s.logger
+func (s webUserService) GetWebUserByEmail(ctx context.Context, email string) (*model.WebUser, error) {
+	logger := s.logger.ForContext(ctx)
 
-	user, err := s.store.WebUser.GetWebUserByEmail(email)
+	user, err := s.store.WebUser.GetWebUserByEmail(ctx, email)
 	if err != nil {
 		logger.Error().Err(err).Msg("get web user by email")
 		return nil, fmt.Errorf("get web user by email from db: %w", err)
@@ -51,3 +52,20 @@ func (s webUserService) GetWebUserByEmail(email string) (*model.WebUser, error)
 	logger.Info().Interface("web user", user).Msg("successfully got web user")
 	return user, nil
 }
+
+func (s webUserService) GetWebUserByID(ctx context.Context, id int) (*model.WebUser, error) {
+	logger := s.logger.ForContext(ctx)
+
+	user, err := s.store.WebUser.GetWebUserByID(ctx, id)
+	if err != nil {
+		logger.Error().Err(err).Msg("get web user by id")
+		return nil, fmt.Errorf("get web user by id from db: %w", err)
+	}
+	if user == nil {
+		logger.Info().Int("user id", id).Msg("web user by id not found")
+		return nil, ErrWebUserNotFound
+	}
+
+	logger.Info().Interface("web user", user).Msg("successfully got web user")
+	return user, nil
+}
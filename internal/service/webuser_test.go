@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/VladPetriv/scanner_backend/pkg/config"
 	"github.com/VladPetriv/scanner_backend/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_CreateWebUser(t *testing.T) {
@@ -25,14 +27,14 @@ func Test_CreateWebUser(t *testing.T) {
 		{
 			name: "CreateWebUser successful",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("CreateWebUser", &model.WebUser{Email: "test@test.com", Password: "test"}).Return(nil)
+				webUserRepo.On("CreateWebUser", mock.Anything, &model.WebUser{Email: "test@test.com", Password: "test"}).Return(nil)
 			},
 			input: &model.WebUser{Email: "test@test.com", Password: "test"},
 		},
 		{
 			name: "CreteWebUser failed with some store error",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("CreateWebUser", &model.WebUser{
+				webUserRepo.On("CreateWebUser", mock.Anything, &model.WebUser{
 					Email:    "test@test.com",
 					Password: "test"},
 				).Return(fmt.Errorf("some store error"))
@@ -55,7 +57,7 @@ func Test_CreateWebUser(t *testing.T) {
 			webUserService := service.NewWebUserService(&store.Store{WebUser: webUserRepo}, logger)
 			tt.mock(webUserRepo)
 
-			err := webUserService.CreateWebUser(tt.input)
+			err := webUserService.CreateWebUser(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 
 			webUserRepo.AssertExpectations(t)
@@ -76,7 +78,7 @@ func Test_GetWebUserByEmail(t *testing.T) {
 		{
 			name: "GetWebUserByEmail successful",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(&model.WebUser{
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(&model.WebUser{
 					ID:       1,
 					Email:    "test@test.com",
 					Password: "test",
@@ -88,7 +90,7 @@ func Test_GetWebUserByEmail(t *testing.T) {
 		{
 			name: "GetWebUserByEmail failed with not found user",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").Return(nil, nil)
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").Return(nil, nil)
 			},
 			input:         "test@test.com",
 			expectedError: service.ErrWebUserNotFound,
@@ -96,7 +98,7 @@ func Test_GetWebUserByEmail(t *testing.T) {
 		{
 			name: "GetWebUserByEmail failed with some store error",
 			mock: func(webUserRepo *mocks.WebUserRepo) {
-				webUserRepo.On("GetWebUserByEmail", "test@test.com").
+				webUserRepo.On("GetWebUserByEmail", mock.Anything, "test@test.com").
 					Return(nil, fmt.Errorf("some store error"))
 			},
 			input: "test@test.com",
@@ -119,7 +121,72 @@ func Test_GetWebUserByEmail(t *testing.T) {
 			webUserService := service.NewWebUserService(&store.Store{WebUser: webUserRepo}, logger)
 			tt.mock(webUserRepo)
 
-			got, err := webUserService.GetWebUserByEmail(tt.input)
+			got, err := webUserService.GetWebUserByEmail(context.Background(), tt.input)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+
+			webUserRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_GetWebUserByID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		mock          func(webUserRepo *mocks.WebUserRepo)
+		input         int
+		want          *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "GetWebUserByID successful",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(&model.WebUser{
+					ID:       1,
+					Email:    "test@test.com",
+					Password: "test",
+				}, nil)
+			},
+			input: 1,
+			want:  &model.WebUser{ID: 1, Email: "test@test.com", Password: "test"},
+		},
+		{
+			name: "GetWebUserByID failed with not found user",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).Return(nil, nil)
+			},
+			input:         1,
+			expectedError: service.ErrWebUserNotFound,
+		},
+		{
+			name: "GetWebUserByID failed with some store error",
+			mock: func(webUserRepo *mocks.WebUserRepo) {
+				webUserRepo.On("GetWebUserByID", mock.Anything, 1).
+					Return(nil, fmt.Errorf("some store error"))
+			},
+			input: 1,
+			expectedError: fmt.Errorf(
+				"get web user by id from db: %w",
+				fmt.Errorf("some store error"),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			webUserRepo := &mocks.WebUserRepo{}
+
+			logger := logger.Get(&config.Config{LogLevel: "info"})
+			webUserService := service.NewWebUserService(&store.Store{WebUser: webUserRepo}, logger)
+			tt.mock(webUserRepo)
+
+			got, err := webUserService.GetWebUserByID(context.Background(), tt.input)
 			assert.Equal(t, tt.expectedError, err)
 			assert.Equal(t, tt.want, got)
 
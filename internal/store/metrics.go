@@ -0,0 +1,64 @@
+package store
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+	"github.com/VladPetriv/scanner_backend/pkg/metrics"
+)
+
+const (
+	reconnectSucceeded = "succeeded"
+	reconnectFailed    = "failed"
+)
+
+type storeMetrics struct {
+	up         prometheus.Gauge
+	reconnects *prometheus.CounterVec
+}
+
+func newStoreMetrics(registerer prometheus.Registerer, db *pg.DB) *storeMetrics {
+	m := &storeMetrics{
+		up: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "db",
+			Name:      "up",
+			Help:      "Whether the last database connection check succeeded.",
+		}),
+		reconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "db",
+			Name:      "reconnects_total",
+			Help:      "Number of database reconnection attempts by outcome.",
+		}, []string{"outcome"}),
+	}
+
+	m.up.Set(1)
+
+	if registerer == nil {
+		return m
+	}
+
+	registerer.MustRegister(
+		m.up,
+		m.reconnects,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "db",
+			Name:      "open_connections",
+			Help:      "Number of established connections of the live pool.",
+		}, func() float64 {
+			return float64(db.Stats().OpenConnections)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "db",
+			Name:      "in_use_connections",
+			Help:      "Number of connections of the live pool currently in use.",
+		}, func() float64 {
+			return float64(db.Stats().InUse)
+		}),
+	)
+
+	return m
+}
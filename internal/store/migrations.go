@@ -3,44 +3,110 @@ package store
 import (
 	"errors"
 	"fmt"
+	"io/fs"
+	"path"
 
 	"github.com/golang-migrate/migrate"
-	// postgres and file module are required for running migration.
+	// postgres module is required for running migration.
 	_ "github.com/golang-migrate/migrate/database/postgres"
-	_ "github.com/golang-migrate/migrate/source/file"
+	bindata "github.com/golang-migrate/migrate/source/go_bindata"
 
+	"github.com/VladPetriv/scanner_backend/db"
 	"github.com/VladPetriv/scanner_backend/pkg/config"
 )
 
-func runMigrations(cfg *config.Config) error {
-	var connectionString string
+// Migrator applies migrations embedded into the binary.
+type Migrator struct {
+	migrate *migrate.Migrate
+}
+
+func NewMigrator(cfg *config.Config) (*Migrator, error) {
+	entries, err := fs.ReadDir(db.Migrations, db.MigrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("read embedded migrations: %w", err)
+	}
 
-	if cfg.MigrationsPath == "" {
-		return nil
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
 	}
 
-	if cfg.DatabaseURL == "" {
-		connectionString = fmt.Sprintf(
-			"postgresql://%s/%s?user=%s&password=%s&sslmode=disable",
-			cfg.PgHost, cfg.PgDB, cfg.PgUser, cfg.PgDB,
-		)
-	} else {
-		connectionString = cfg.DatabaseURL
+	source, err := bindata.WithInstance(bindata.Resource(names, func(name string) ([]byte, error) {
+		return db.Migrations.ReadFile(path.Join(db.MigrationsDir, name))
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("create migrations source: %w", err)
 	}
 
-	m, err := migrate.New(
-		cfg.MigrationsPath,
-		connectionString,
-	)
+	m, err := migrate.NewWithSourceInstance("go-bindata", source, cfg.PostgresURL())
 	if err != nil {
-		return fmt.Errorf("create migrations error: %w", err)
+		return nil, fmt.Errorf("create migrations error: %w", err)
+	}
+
+	return &Migrator{migrate: m}, nil
+}
+
+// Up applies all migrations which are not applied yet.
+func (m *Migrator) Up() error {
+	if err := m.migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("up migrations error: %w", err)
 	}
 
-	if err := m.Up(); err != nil {
-		if !errors.Is(err, migrate.ErrNoChange) {
-			return fmt.Errorf("up migrations error: %w", err)
+	return nil
+}
+
+// Down rolls back the given number of applied migrations.
+func (m *Migrator) Down(steps int) error {
+	if err := m.migrate.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("down migrations error: %w", err)
+	}
+
+	return nil
+}
+
+// Version returns version of the last applied migration, zero version means that nothing is applied.
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.migrate.Version()
+	if err != nil {
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return 0, false, nil
 		}
+
+		return 0, false, fmt.Errorf("get migration version: %w", err)
+	}
+
+	return version, dirty, nil
+}
+
+// Force sets the version without running migrations, it's used to recover from a failed migration.
+func (m *Migrator) Force(version int) error {
+	if err := m.migrate.Force(version); err != nil {
+		return fmt.Errorf("force migration version: %w", err)
 	}
 
 	return nil
 }
+
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.migrate.Close()
+	if sourceErr != nil {
+		return fmt.Errorf("close migrations source: %w", sourceErr)
+	}
+
+	if dbErr != nil {
+		return fmt.Errorf("close migrations database: %w", dbErr)
+	}
+
+	return nil
+}
+
+func runMigrations(cfg *config.Config) error {
+	migrator, err := NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+
+	defer migrator.Close() //nolint:errcheck // migration error is more relevant
+
+	return migrator.Up()
+}
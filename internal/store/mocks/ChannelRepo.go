@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	model "github.com/VladPetriv/scanner_backend/internal/model"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type ChannelRepo struct {
 	mock.Mock
 }
 
-// CreateChannel provides a mock function with given fields: channel
-func (_m *ChannelRepo) CreateChannel(channel *model.DBChannel) error {
-	ret := _m.Called(channel)
+// CreateChannel provides a mock function with given fields: ctx, channel
+func (_m *ChannelRepo) CreateChannel(ctx context.Context, channel *model.DBChannel) error {
+	ret := _m.Called(ctx, channel)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.DBChannel) error); ok {
-		r0 = rf(channel)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DBChannel) error); ok {
+		r0 = rf(ctx, channel)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,25 +28,51 @@ func (_m *ChannelRepo) CreateChannel(channel *model.DBChannel) error {
 	return r0
 }
 
-// GetChannelByName provides a mock function with given fields: name
-func (_m *ChannelRepo) GetChannelByName(name string) (*model.Channel, error) {
-	ret := _m.Called(name)
+// GetAllChannels provides a mock function with given fields: ctx
+func (_m *ChannelRepo) GetAllChannels(ctx context.Context) ([]model.Channel, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []model.Channel
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.Channel, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.Channel); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Channel)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetChannelByName provides a mock function with given fields: ctx, name
+func (_m *ChannelRepo) GetChannelByName(ctx context.Context, name string) (*model.Channel, error) {
+	ret := _m.Called(ctx, name)
 
 	var r0 *model.Channel
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*model.Channel, error)); ok {
-		return rf(name)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Channel, error)); ok {
+		return rf(ctx, name)
 	}
-	if rf, ok := ret.Get(0).(func(string) *model.Channel); ok {
-		r0 = rf(name)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Channel); ok {
+		r0 = rf(ctx, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Channel)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(name)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -52,25 +80,25 @@ func (_m *ChannelRepo) GetChannelByName(name string) (*model.Channel, error) {
 	return r0, r1
 }
 
-// GetChannelStats provides a mock function with given fields: channelID
-func (_m *ChannelRepo) GetChannelStats(channelID int) (*model.Stat, error) {
-	ret := _m.Called(channelID)
+// GetChannelStats provides a mock function with given fields: ctx, channelID
+func (_m *ChannelRepo) GetChannelStats(ctx context.Context, channelID int) (*model.Stat, error) {
+	ret := _m.Called(ctx, channelID)
 
 	var r0 *model.Stat
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*model.Stat, error)); ok {
-		return rf(channelID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.Stat, error)); ok {
+		return rf(ctx, channelID)
 	}
-	if rf, ok := ret.Get(0).(func(int) *model.Stat); ok {
-		r0 = rf(channelID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.Stat); ok {
+		r0 = rf(ctx, channelID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Stat)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(channelID)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, channelID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -78,25 +106,25 @@ func (_m *ChannelRepo) GetChannelStats(channelID int) (*model.Stat, error) {
 	return r0, r1
 }
 
-// GetChannels provides a mock function with given fields:
-func (_m *ChannelRepo) GetChannels() ([]model.Channel, error) {
-	ret := _m.Called()
+// GetChannels provides a mock function with given fields: ctx
+func (_m *ChannelRepo) GetChannels(ctx context.Context) ([]model.Channel, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []model.Channel
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]model.Channel, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.Channel, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func() []model.Channel); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []model.Channel); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Channel)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -104,25 +132,87 @@ func (_m *ChannelRepo) GetChannels() ([]model.Channel, error) {
 	return r0, r1
 }
 
-// GetChannelsByPage provides a mock function with given fields: page
-func (_m *ChannelRepo) GetChannelsByPage(page int) ([]model.Channel, error) {
-	ret := _m.Called(page)
+// GetChannelsByPage provides a mock function with given fields: ctx, page
+func (_m *ChannelRepo) GetChannelsByPage(ctx context.Context, page int) ([]model.Channel, error) {
+	ret := _m.Called(ctx, page)
 
 	var r0 []model.Channel
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]model.Channel, error)); ok {
-		return rf(page)
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.Channel, error)); ok {
+		return rf(ctx, page)
 	}
-	if rf, ok := ret.Get(0).(func(int) []model.Channel); ok {
-		r0 = rf(page)
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.Channel); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Channel)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(page)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateChannel provides a mock function with given fields: ctx, channel
+func (_m *ChannelRepo) UpdateChannel(ctx context.Context, channel *model.DBChannel) error {
+	ret := _m.Called(ctx, channel)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DBChannel) error); ok {
+		r0 = rf(ctx, channel)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateChannelEnabled provides a mock function with given fields: ctx, id, enabled
+func (_m *ChannelRepo) UpdateChannelEnabled(ctx context.Context, id int, enabled bool) (bool, error) {
+	ret := _m.Called(ctx, id, enabled)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) (bool, error)); ok {
+		return rf(ctx, id, enabled)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) bool); ok {
+		r0 = rf(ctx, id, enabled)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool) error); ok {
+		r1 = rf(ctx, id, enabled)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateChannelHidden provides a mock function with given fields: ctx, id, hidden
+func (_m *ChannelRepo) UpdateChannelHidden(ctx context.Context, id int, hidden bool) (bool, error) {
+	ret := _m.Called(ctx, id, hidden)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) (bool, error)); ok {
+		return rf(ctx, id, hidden)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) bool); ok {
+		r0 = rf(ctx, id, hidden)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool) error); ok {
+		r1 = rf(ctx, id, hidden)
 	} else {
 		r1 = ret.Error(1)
 	}
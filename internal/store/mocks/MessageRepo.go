@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	model "github.com/VladPetriv/scanner_backend/internal/model"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,23 +14,23 @@ type MessageRepo struct {
 	mock.Mock
 }
 
-// CreateMessage provides a mock function with given fields: message
-func (_m *MessageRepo) CreateMessage(message *model.DBMessage) (int, error) {
-	ret := _m.Called(message)
+// CreateMessage provides a mock function with given fields: ctx, message
+func (_m *MessageRepo) CreateMessage(ctx context.Context, message *model.DBMessage) (int, error) {
+	ret := _m.Called(ctx, message)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.DBMessage) (int, error)); ok {
-		return rf(message)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DBMessage) (int, error)); ok {
+		return rf(ctx, message)
 	}
-	if rf, ok := ret.Get(0).(func(*model.DBMessage) int); ok {
-		r0 = rf(message)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DBMessage) int); ok {
+		r0 = rf(ctx, message)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.DBMessage) error); ok {
-		r1 = rf(message)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.DBMessage) error); ok {
+		r1 = rf(ctx, message)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -36,25 +38,25 @@ func (_m *MessageRepo) CreateMessage(message *model.DBMessage) (int, error) {
 	return r0, r1
 }
 
-// GetFullMessageByID provides a mock function with given fields: id
-func (_m *MessageRepo) GetFullMessageByID(id int) (*model.FullMessage, error) {
-	ret := _m.Called(id)
+// GetFullMessageByID provides a mock function with given fields: ctx, id
+func (_m *MessageRepo) GetFullMessageByID(ctx context.Context, id int) (*model.FullMessage, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *model.FullMessage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*model.FullMessage, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.FullMessage, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *model.FullMessage); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.FullMessage); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.FullMessage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,25 +64,25 @@ func (_m *MessageRepo) GetFullMessageByID(id int) (*model.FullMessage, error) {
 	return r0, r1
 }
 
-// GetFullMessagesByChannelIDAndPage provides a mock function with given fields: id, page
-func (_m *MessageRepo) GetFullMessagesByChannelIDAndPage(id int, page int) ([]model.FullMessage, error) {
-	ret := _m.Called(id, page)
+// GetFullMessagesByChannelIDAndPage provides a mock function with given fields: ctx, id, page
+func (_m *MessageRepo) GetFullMessagesByChannelIDAndPage(ctx context.Context, id int, page int) ([]model.FullMessage, error) {
+	ret := _m.Called(ctx, id, page)
 
 	var r0 []model.FullMessage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) ([]model.FullMessage, error)); ok {
-		return rf(id, page)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]model.FullMessage, error)); ok {
+		return rf(ctx, id, page)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) []model.FullMessage); ok {
-		r0 = rf(id, page)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []model.FullMessage); ok {
+		r0 = rf(ctx, id, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.FullMessage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(id, page)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, id, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -88,25 +90,25 @@ func (_m *MessageRepo) GetFullMessagesByChannelIDAndPage(id int, page int) ([]mo
 	return r0, r1
 }
 
-// GetFullMessagesByPage provides a mock function with given fields: page
-func (_m *MessageRepo) GetFullMessagesByPage(page int) ([]model.FullMessage, error) {
-	ret := _m.Called(page)
+// GetFullMessagesByKindAndPage provides a mock function with given fields: ctx, kind, page
+func (_m *MessageRepo) GetFullMessagesByKindAndPage(ctx context.Context, kind model.MessageKind, page int) ([]model.FullMessage, error) {
+	ret := _m.Called(ctx, kind, page)
 
 	var r0 []model.FullMessage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]model.FullMessage, error)); ok {
-		return rf(page)
+	if rf, ok := ret.Get(0).(func(context.Context, model.MessageKind, int) ([]model.FullMessage, error)); ok {
+		return rf(ctx, kind, page)
 	}
-	if rf, ok := ret.Get(0).(func(int) []model.FullMessage); ok {
-		r0 = rf(page)
+	if rf, ok := ret.Get(0).(func(context.Context, model.MessageKind, int) []model.FullMessage); ok {
+		r0 = rf(ctx, kind, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.FullMessage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(page)
+	if rf, ok := ret.Get(1).(func(context.Context, model.MessageKind, int) error); ok {
+		r1 = rf(ctx, kind, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -114,25 +116,25 @@ func (_m *MessageRepo) GetFullMessagesByPage(page int) ([]model.FullMessage, err
 	return r0, r1
 }
 
-// GetFullMessagesByUserID provides a mock function with given fields: id
-func (_m *MessageRepo) GetFullMessagesByUserID(id int) ([]model.FullMessage, error) {
-	ret := _m.Called(id)
+// GetFullMessagesByPage provides a mock function with given fields: ctx, page
+func (_m *MessageRepo) GetFullMessagesByPage(ctx context.Context, page int) ([]model.FullMessage, error) {
+	ret := _m.Called(ctx, page)
 
 	var r0 []model.FullMessage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]model.FullMessage, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.FullMessage, error)); ok {
+		return rf(ctx, page)
 	}
-	if rf, ok := ret.Get(0).(func(int) []model.FullMessage); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.FullMessage); ok {
+		r0 = rf(ctx, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.FullMessage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, page)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -140,25 +142,101 @@ func (_m *MessageRepo) GetFullMessagesByUserID(id int) ([]model.FullMessage, err
 	return r0, r1
 }
 
-// GetMessageByTitle provides a mock function with given fields: title
-func (_m *MessageRepo) GetMessageByTitle(title string) (*model.DBMessage, error) {
-	ret := _m.Called(title)
+// GetFullMessagesByUserID provides a mock function with given fields: ctx, id
+func (_m *MessageRepo) GetFullMessagesByUserID(ctx context.Context, id int) ([]model.FullMessage, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []model.FullMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.FullMessage, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.FullMessage); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.FullMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessageByTitle provides a mock function with given fields: ctx, title
+func (_m *MessageRepo) GetMessageByTitle(ctx context.Context, title string) (*model.DBMessage, error) {
+	ret := _m.Called(ctx, title)
 
 	var r0 *model.DBMessage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*model.DBMessage, error)); ok {
-		return rf(title)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.DBMessage, error)); ok {
+		return rf(ctx, title)
 	}
-	if rf, ok := ret.Get(0).(func(string) *model.DBMessage); ok {
-		r0 = rf(title)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.DBMessage); ok {
+		r0 = rf(ctx, title)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.DBMessage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(title)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, title)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessagesAfterID provides a mock function with given fields: ctx, afterID, limit
+func (_m *MessageRepo) GetMessagesAfterID(ctx context.Context, afterID int, limit int) ([]model.DBMessage, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	var r0 []model.DBMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]model.DBMessage, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []model.DBMessage); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.DBMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessagesCount provides a mock function with given fields: ctx
+func (_m *MessageRepo) GetMessagesCount(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -166,23 +244,23 @@ func (_m *MessageRepo) GetMessageByTitle(title string) (*model.DBMessage, error)
 	return r0, r1
 }
 
-// GetMessagesCount provides a mock function with given fields:
-func (_m *MessageRepo) GetMessagesCount() (int, error) {
-	ret := _m.Called()
+// GetMessagesCountByChannelID provides a mock function with given fields: ctx, id
+func (_m *MessageRepo) GetMessagesCountByChannelID(ctx context.Context, id int) (int, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (int, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, int) (int, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func() int); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -190,23 +268,71 @@ func (_m *MessageRepo) GetMessagesCount() (int, error) {
 	return r0, r1
 }
 
-// GetMessagesCountByChannelID provides a mock function with given fields: id
-func (_m *MessageRepo) GetMessagesCountByChannelID(id int) (int, error) {
-	ret := _m.Called(id)
+// GetMessagesCountByKind provides a mock function with given fields: ctx, kind
+func (_m *MessageRepo) GetMessagesCountByKind(ctx context.Context, kind model.MessageKind) (int, error) {
+	ret := _m.Called(ctx, kind)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (int, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, model.MessageKind) (int, error)); ok {
+		return rf(ctx, kind)
 	}
-	if rf, ok := ret.Get(0).(func(int) int); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, model.MessageKind) int); ok {
+		r0 = rf(ctx, kind)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, model.MessageKind) error); ok {
+		r1 = rf(ctx, kind)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateMessageHidden provides a mock function with given fields: ctx, id, hidden
+func (_m *MessageRepo) UpdateMessageHidden(ctx context.Context, id int, hidden bool) (bool, error) {
+	ret := _m.Called(ctx, id, hidden)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) (bool, error)); ok {
+		return rf(ctx, id, hidden)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) bool); ok {
+		r0 = rf(ctx, id, hidden)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool) error); ok {
+		r1 = rf(ctx, id, hidden)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateMessageKind provides a mock function with given fields: ctx, id, kind
+func (_m *MessageRepo) UpdateMessageKind(ctx context.Context, id int, kind model.MessageKind) (bool, error) {
+	ret := _m.Called(ctx, id, kind)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.MessageKind) (bool, error)); ok {
+		return rf(ctx, id, kind)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.MessageKind) bool); ok {
+		r0 = rf(ctx, id, kind)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, model.MessageKind) error); ok {
+		r1 = rf(ctx, id, kind)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	model "github.com/VladPetriv/scanner_backend/internal/model"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,27 @@ type ReplyRepo struct {
 	mock.Mock
 }
 
-// CreateReply provides a mock function with given fields: reply
-func (_m *ReplyRepo) CreateReply(reply *model.DBReply) error {
-	ret := _m.Called(reply)
+// CreateReplies provides a mock function with given fields: ctx, replies
+func (_m *ReplyRepo) CreateReplies(ctx context.Context, replies []model.DBReply) error {
+	ret := _m.Called(ctx, replies)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.DBReply) error); ok {
+		r0 = rf(ctx, replies)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateReply provides a mock function with given fields: ctx, reply
+func (_m *ReplyRepo) CreateReply(ctx context.Context, reply *model.DBReply) error {
+	ret := _m.Called(ctx, reply)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.DBReply) error); ok {
-		r0 = rf(reply)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.DBReply) error); ok {
+		r0 = rf(ctx, reply)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,25 +42,49 @@ func (_m *ReplyRepo) CreateReply(reply *model.DBReply) error {
 	return r0
 }
 
-// GetFullRepliesByMessageID provides a mock function with given fields: id
-func (_m *ReplyRepo) GetFullRepliesByMessageID(id int) ([]model.FullReply, error) {
-	ret := _m.Called(id)
+// GetFullRepliesByMessageID provides a mock function with given fields: ctx, id
+func (_m *ReplyRepo) GetFullRepliesByMessageID(ctx context.Context, id int) ([]model.FullReply, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 []model.FullReply
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]model.FullReply, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.FullReply, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) []model.FullReply); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.FullReply); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.FullReply)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateReplyHidden provides a mock function with given fields: ctx, id, hidden
+func (_m *ReplyRepo) UpdateReplyHidden(ctx context.Context, id int, hidden bool) (bool, error) {
+	ret := _m.Called(ctx, id, hidden)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) (bool, error)); ok {
+		return rf(ctx, id, hidden)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) bool); ok {
+		r0 = rf(ctx, id, hidden)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, bool) error); ok {
+		r1 = rf(ctx, id, hidden)
 	} else {
 		r1 = ret.Error(1)
 	}
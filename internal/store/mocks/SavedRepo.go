@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	model "github.com/VladPetriv/scanner_backend/internal/model"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type SavedRepo struct {
 	mock.Mock
 }
 
-// CreateSavedMessage provides a mock function with given fields: saved
-func (_m *SavedRepo) CreateSavedMessage(saved *model.Saved) error {
-	ret := _m.Called(saved)
+// CreateSavedMessage provides a mock function with given fields: ctx, saved
+func (_m *SavedRepo) CreateSavedMessage(ctx context.Context, saved *model.Saved) error {
+	ret := _m.Called(ctx, saved)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Saved) error); ok {
-		r0 = rf(saved)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Saved) error); ok {
+		r0 = rf(ctx, saved)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +28,13 @@ func (_m *SavedRepo) CreateSavedMessage(saved *model.Saved) error {
 	return r0
 }
 
-// DeleteSavedMessage provides a mock function with given fields: id
-func (_m *SavedRepo) DeleteSavedMessage(id int) error {
-	ret := _m.Called(id)
+// DeleteSavedMessage provides a mock function with given fields: ctx, id
+func (_m *SavedRepo) DeleteSavedMessage(ctx context.Context, id int) error {
+	ret := _m.Called(ctx, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -40,25 +42,25 @@ func (_m *SavedRepo) DeleteSavedMessage(id int) error {
 	return r0
 }
 
-// GetSavedMessageByID provides a mock function with given fields: id
-func (_m *SavedRepo) GetSavedMessageByID(id int) (*model.Saved, error) {
-	ret := _m.Called(id)
+// GetSavedMessageByID provides a mock function with given fields: ctx, id
+func (_m *SavedRepo) GetSavedMessageByID(ctx context.Context, id int) (*model.Saved, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *model.Saved
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*model.Saved, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.Saved, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *model.Saved); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.Saved); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.Saved)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -66,25 +68,25 @@ func (_m *SavedRepo) GetSavedMessageByID(id int) (*model.Saved, error) {
 	return r0, r1
 }
 
-// GetSavedMessages provides a mock function with given fields: userID
-func (_m *SavedRepo) GetSavedMessages(userID int) ([]model.Saved, error) {
-	ret := _m.Called(userID)
+// GetSavedMessages provides a mock function with given fields: ctx, userID
+func (_m *SavedRepo) GetSavedMessages(ctx context.Context, userID int) ([]model.Saved, error) {
+	ret := _m.Called(ctx, userID)
 
 	var r0 []model.Saved
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) ([]model.Saved, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.Saved, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(int) []model.Saved); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.Saved); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Saved)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	model "github.com/VladPetriv/scanner_backend/internal/model"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,23 +14,49 @@ type UserRepo struct {
 	mock.Mock
 }
 
-// CreateUser provides a mock function with given fields: user
-func (_m *UserRepo) CreateUser(user *model.User) (int, error) {
-	ret := _m.Called(user)
+// CreateUser provides a mock function with given fields: ctx, user
+func (_m *UserRepo) CreateUser(ctx context.Context, user *model.User) (int, error) {
+	ret := _m.Called(ctx, user)
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.User) (int, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User) (int, error)); ok {
+		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(*model.User) int); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.User) int); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.User) error); ok {
+		r1 = rf(ctx, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateUsers provides a mock function with given fields: ctx, users
+func (_m *UserRepo) CreateUsers(ctx context.Context, users []model.User) (map[string]int, error) {
+	ret := _m.Called(ctx, users)
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []model.User) (map[string]int, error)); ok {
+		return rf(ctx, users)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []model.User) map[string]int); ok {
+		r0 = rf(ctx, users)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []model.User) error); ok {
+		r1 = rf(ctx, users)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -36,25 +64,25 @@ func (_m *UserRepo) CreateUser(user *model.User) (int, error) {
 	return r0, r1
 }
 
-// GetUserByID provides a mock function with given fields: id
-func (_m *UserRepo) GetUserByID(id int) (*model.User, error) {
-	ret := _m.Called(id)
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserRepo) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *model.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int) (*model.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.User, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int) *model.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,25 +90,103 @@ func (_m *UserRepo) GetUserByID(id int) (*model.User, error) {
 	return r0, r1
 }
 
-// GetUserByUsername provides a mock function with given fields: username
-func (_m *UserRepo) GetUserByUsername(username string) (*model.User, error) {
-	ret := _m.Called(username)
+// GetUserByTgID provides a mock function with given fields: ctx, tgID
+func (_m *UserRepo) GetUserByTgID(ctx context.Context, tgID int64) (*model.User, error) {
+	ret := _m.Called(ctx, tgID)
 
 	var r0 *model.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*model.User, error)); ok {
-		return rf(username)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*model.User, error)); ok {
+		return rf(ctx, tgID)
 	}
-	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
-		r0 = rf(username)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *model.User); ok {
+		r0 = rf(ctx, tgID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(username)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, tgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByUsername provides a mock function with given fields: ctx, username
+func (_m *UserRepo) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserHistory provides a mock function with given fields: ctx, userID
+func (_m *UserRepo) GetUserHistory(ctx context.Context, userID int) ([]model.UserHistory, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []model.UserHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.UserHistory, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.UserHistory); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.UserHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsersByPage provides a mock function with given fields: ctx, page
+func (_m *UserRepo) GetUsersByPage(ctx context.Context, page int) ([]model.User, error) {
+	ret := _m.Called(ctx, page)
+
+	var r0 []model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.User, error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.User); ok {
+		r0 = rf(ctx, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, page)
 	} else {
 		r1 = ret.Error(1)
 	}
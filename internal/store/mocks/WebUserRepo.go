@@ -3,8 +3,12 @@
 package mocks
 
 import (
+	context "context"
+
 	model "github.com/VladPetriv/scanner_backend/internal/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // WebUserRepo is an autogenerated mock type for the WebUserRepo type
@@ -12,13 +16,37 @@ type WebUserRepo struct {
 	mock.Mock
 }
 
-// CreateWebUser provides a mock function with given fields: user
-func (_m *WebUserRepo) CreateWebUser(user *model.WebUser) error {
-	ret := _m.Called(user)
+// CountWebUsersByRole provides a mock function with given fields: ctx, role
+func (_m *WebUserRepo) CountWebUsersByRole(ctx context.Context, role model.Role) (int, error) {
+	ret := _m.Called(ctx, role)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Role) (int, error)); ok {
+		return rf(ctx, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Role) int); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Role) error); ok {
+		r1 = rf(ctx, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebUser provides a mock function with given fields: ctx, user
+func (_m *WebUserRepo) CreateWebUser(ctx context.Context, user *model.WebUser) error {
+	ret := _m.Called(ctx, user)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.WebUser) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WebUser) error); ok {
+		r0 = rf(ctx, user)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,25 +54,181 @@ func (_m *WebUserRepo) CreateWebUser(user *model.WebUser) error {
 	return r0
 }
 
-// GetWebUserByEmail provides a mock function with given fields: email
-func (_m *WebUserRepo) GetWebUserByEmail(email string) (*model.WebUser, error) {
-	ret := _m.Called(email)
+// GetWebUserByEmail provides a mock function with given fields: ctx, email
+func (_m *WebUserRepo) GetWebUserByEmail(ctx context.Context, email string) (*model.WebUser, error) {
+	ret := _m.Called(ctx, email)
 
 	var r0 *model.WebUser
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*model.WebUser, error)); ok {
-		return rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.WebUser, error)); ok {
+		return rf(ctx, email)
 	}
-	if rf, ok := ret.Get(0).(func(string) *model.WebUser); ok {
-		r0 = rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.WebUser); ok {
+		r0 = rf(ctx, email)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.WebUser)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(email)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebUserByID provides a mock function with given fields: ctx, id
+func (_m *WebUserRepo) GetWebUserByID(ctx context.Context, id int) (*model.WebUser, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.WebUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (*model.WebUser, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) *model.WebUser); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebUsersByPage provides a mock function with given fields: ctx, page
+func (_m *WebUserRepo) GetWebUsersByPage(ctx context.Context, page int) ([]model.WebUser, error) {
+	ret := _m.Called(ctx, page)
+
+	var r0 []model.WebUser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.WebUser, error)); ok {
+		return rf(ctx, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.WebUser); ok {
+		r0 = rf(ctx, page)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WebUser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebUserBannedAt provides a mock function with given fields: ctx, id, bannedAt
+func (_m *WebUserRepo) UpdateWebUserBannedAt(ctx context.Context, id int, bannedAt *time.Time) error {
+	ret := _m.Called(ctx, id, bannedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *time.Time) error); ok {
+		r0 = rf(ctx, id, bannedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWebUserPassword provides a mock function with given fields: ctx, id, password
+func (_m *WebUserRepo) UpdateWebUserPassword(ctx context.Context, id int, password string) error {
+	ret := _m.Called(ctx, id, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWebUserRole provides a mock function with given fields: ctx, id, role
+func (_m *WebUserRepo) UpdateWebUserRole(ctx context.Context, id int, role model.Role) error {
+	ret := _m.Called(ctx, id, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.Role) error); ok {
+		r0 = rf(ctx, id, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWebUserTOTP provides a mock function with given fields: ctx, id, secret, enabled
+func (_m *WebUserRepo) UpdateWebUserTOTP(ctx context.Context, id int, secret string, enabled bool) error {
+	ret := _m.Called(ctx, id, secret, enabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, bool) error); ok {
+		r0 = rf(ctx, id, secret, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWebUserTOTPCounter provides a mock function with given fields: ctx, id, counter
+func (_m *WebUserRepo) UpdateWebUserTOTPCounter(ctx context.Context, id int, counter int64) (bool, error) {
+	ret := _m.Called(ctx, id, counter)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) (bool, error)); ok {
+		return rf(ctx, id, counter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int64) bool); ok {
+		r0 = rf(ctx, id, counter)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int64) error); ok {
+		r1 = rf(ctx, id, counter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyWebUserEmail provides a mock function with given fields: ctx, id, email
+func (_m *WebUserRepo) VerifyWebUserEmail(ctx context.Context, id int, email string) (bool, error) {
+	ret := _m.Called(ctx, id, email)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (bool, error)); ok {
+		return rf(ctx, id, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) bool); ok {
+		r0 = rf(ctx, id, email)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, id, email)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
@@ -15,8 +16,11 @@ func NewChannelRepo(db *DB) *ChannelPgRepo {
 	return &ChannelPgRepo{db}
 }
 
-func (repo ChannelPgRepo) CreateChannel(channel *model.DBChannel) error {
-	_, err := repo.db.Exec(`
+func (repo ChannelPgRepo) CreateChannel(ctx context.Context, channel *model.DBChannel) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, `
 		INSERT INTO channel(name, title, image_url) VALUES ($1, $2, $3);`,
 		channel.Name, channel.Title, channel.ImageURL,
 	)
@@ -27,10 +31,33 @@ func (repo ChannelPgRepo) CreateChannel(channel *model.DBChannel) error {
 	return nil
 }
 
-func (repo ChannelPgRepo) GetChannels() ([]model.Channel, error) {
+// UpdateChannel updates channel title and image and keeps previous values in channel history.
+func (repo ChannelPgRepo) UpdateChannel(ctx context.Context, channel *model.DBChannel) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, `
+		WITH previous AS (
+			INSERT INTO channel_history(channel_id, title, image_url) 
+			SELECT id, title, image_url FROM channel WHERE id = $1
+		)
+		UPDATE channel SET title = $2, image_url = $3 WHERE id = $1;`,
+		channel.ID, channel.Title, channel.ImageURL,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo ChannelPgRepo) GetChannels(ctx context.Context) ([]model.Channel, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var channels []model.Channel
 
-	err := repo.db.Select(&channels, "SELECT * FROM channel;")
+	err := repo.db.SelectContext(ctx, &channels, "SELECT * FROM channel WHERE hidden = FALSE;")
 	if err != nil {
 		return nil, err
 	}
@@ -42,10 +69,14 @@ func (repo ChannelPgRepo) GetChannels() ([]model.Channel, error) {
 	return channels, nil
 }
 
-func (repo ChannelPgRepo) GetChannelsByPage(page int) ([]model.Channel, error) {
+// GetAllChannels returns hidden channels as well, it's used for administration only.
+func (repo ChannelPgRepo) GetAllChannels(ctx context.Context) ([]model.Channel, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var channels []model.Channel
 
-	err := repo.db.Select(&channels, "SELECT * FROM channel LIMIT 10 OFFSET $1;", page)
+	err := repo.db.SelectContext(ctx, &channels, "SELECT * FROM channel ORDER BY id;")
 	if err != nil {
 		return nil, err
 	}
@@ -57,10 +88,31 @@ func (repo ChannelPgRepo) GetChannelsByPage(page int) ([]model.Channel, error) {
 	return channels, nil
 }
 
-func (repo ChannelPgRepo) GetChannelByName(name string) (*model.Channel, error) {
+func (repo ChannelPgRepo) GetChannelsByPage(ctx context.Context, page int) ([]model.Channel, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var channels []model.Channel
+
+	err := repo.db.SelectContext(ctx, &channels, "SELECT * FROM channel WHERE hidden = FALSE LIMIT 10 OFFSET $1;", page)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	return channels, nil
+}
+
+func (repo ChannelPgRepo) GetChannelByName(ctx context.Context, name string) (*model.Channel, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var channel model.Channel
 
-	err := repo.db.Get(&channel, "SELECT * FROM channel WHERE name = $1;", name)
+	err := repo.db.GetContext(ctx, &channel, "SELECT * FROM channel WHERE name = $1;", name)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -72,7 +124,10 @@ func (repo ChannelPgRepo) GetChannelByName(name string) (*model.Channel, error)
 	return &channel, nil
 }
 
-func (repo ChannelPgRepo) GetChannelStats(channelID int) (*model.Stat, error) {
+func (repo ChannelPgRepo) GetChannelStats(ctx context.Context, channelID int) (*model.Stat, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var sum int
 
 	stat := &model.Stat{}
@@ -80,10 +135,11 @@ func (repo ChannelPgRepo) GetChannelStats(channelID int) (*model.Stat, error) {
 	messageCount := make([]int, 0)
 	replyCount := make([]int, 0)
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(
+		ctx,
 		`SELECT m.id, COUNT(r.id) 
-		 FROM channel c LEFT JOIN message m ON m.channel_id = c.id 
-		 LEFT JOIN reply r ON r.message_id = m.id 
+		 FROM channel c LEFT JOIN message m ON m.channel_id = c.id AND m.hidden = FALSE 
+		 LEFT JOIN reply r ON r.message_id = m.id AND r.hidden = FALSE 
 		 WHERE c.id = $1 GROUP BY m.id;`,
 		channelID,
 	)
@@ -116,3 +172,39 @@ func (repo ChannelPgRepo) GetChannelStats(channelID int) (*model.Stat, error) {
 
 	return stat, nil
 }
+
+// UpdateChannelEnabled enables or disables ingestion of the channel messages.
+func (repo ChannelPgRepo) UpdateChannelEnabled(ctx context.Context, id int, enabled bool) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, "UPDATE channel SET enabled = $1 WHERE id = $2;", enabled, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// UpdateChannelHidden hides or shows the channel with its messages.
+func (repo ChannelPgRepo) UpdateChannelHidden(ctx context.Context, id int, hidden bool) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, "UPDATE channel SET hidden = $1 WHERE id = $2;", hidden, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -1,6 +1,7 @@
 package pg_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -21,7 +22,7 @@ func Test_CreateChannel(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewChannelRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -53,7 +54,69 @@ func Test_CreateChannel(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err = r.CreateChannel(tt.input)
+			err = r.CreateChannel(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		defer db.Close()
+	})
+}
+
+func Test_UpdateChannel(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
+
+	query := `
+		WITH previous AS (
+			INSERT INTO channel_history(channel_id, title, image_url) 
+			SELECT id, title, image_url FROM channel WHERE id = $1
+		)
+		UPDATE channel SET title = $2, image_url = $3 WHERE id = $1;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         *model.DBChannel
+		expectedError error
+	}{
+		{
+			name: "UpdateChannel successful",
+			mock: func() {
+				mock.ExpectExec(query).
+					WithArgs(1, "test T", "test.jpg").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: &model.DBChannel{ID: 1, Name: "test", Title: "test T", ImageURL: "test.jpg"},
+		},
+		{
+			name: "UpdateChannel failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).
+					WithArgs(1, "test T", "test.jpg").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         &model.DBChannel{ID: 1, Name: "test", Title: "test T", ImageURL: "test.jpg"},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err = r.UpdateChannel(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -78,7 +141,7 @@ func Test_GetChannels(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewChannelRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -93,7 +156,7 @@ func Test_GetChannels(t *testing.T) {
 					AddRow(1, "test1", "test1", "test1.jpg").
 					AddRow(2, "test2", "test2", "test2.jpg")
 
-				mock.ExpectQuery("SELECT * FROM channel;").
+				mock.ExpectQuery("SELECT * FROM channel WHERE hidden = FALSE;").
 					WillReturnRows(rows)
 			},
 			want: []model.Channel{
@@ -106,7 +169,7 @@ func Test_GetChannels(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "name", "title", "image_url"})
 
-				mock.ExpectQuery("SELECT * FROM channel;").
+				mock.ExpectQuery("SELECT * FROM channel WHERE hidden = FALSE;").
 					WillReturnRows(rows)
 			},
 			expectedError: nil,
@@ -114,7 +177,7 @@ func Test_GetChannels(t *testing.T) {
 		{
 			name: "GetChannels failed with some sql error",
 			mock: func() {
-				mock.ExpectQuery("SELECT * FROM channel;").
+				mock.ExpectQuery("SELECT * FROM channel WHERE hidden = FALSE;").
 					WillReturnError(fmt.Errorf("some sql error"))
 			},
 			expectedError: fmt.Errorf("some sql error"),
@@ -124,7 +187,7 @@ func Test_GetChannels(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetChannels()
+			got, err := r.GetChannels(context.Background())
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -150,7 +213,7 @@ func Test_GetChannelsByPage(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewChannelRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -166,7 +229,7 @@ func Test_GetChannelsByPage(t *testing.T) {
 					AddRow(1, "test1", "test1", "test1.jpg").
 					AddRow(2, "test2", "test2", "test2.jpg")
 
-				mock.ExpectQuery("SELECT * FROM channel LIMIT 10 OFFSET $1;").
+				mock.ExpectQuery("SELECT * FROM channel WHERE hidden = FALSE LIMIT 10 OFFSET $1;").
 					WithArgs(1).WillReturnRows(rows)
 			},
 			want: []model.Channel{
@@ -180,7 +243,7 @@ func Test_GetChannelsByPage(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"id", "name", "title", "image_url"})
 
-				mock.ExpectQuery("SELECT * FROM channel LIMIT 10 OFFSET $1;").
+				mock.ExpectQuery("SELECT * FROM channel WHERE hidden = FALSE LIMIT 10 OFFSET $1;").
 					WithArgs(1).WillReturnRows(rows)
 			},
 			input:         1,
@@ -189,7 +252,7 @@ func Test_GetChannelsByPage(t *testing.T) {
 		{
 			name: "Error: [some sql error]",
 			mock: func() {
-				mock.ExpectQuery("SELECT * FROM channel LIMIT 10 OFFSET $1;").
+				mock.ExpectQuery("SELECT * FROM channel WHERE hidden = FALSE LIMIT 10 OFFSET $1;").
 					WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
 			},
 			input:         1,
@@ -201,7 +264,7 @@ func Test_GetChannelsByPage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetChannelsByPage(tt.input)
+			got, err := r.GetChannelsByPage(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -227,7 +290,7 @@ func Test_GetChannelByName(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewChannelRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -274,7 +337,7 @@ func Test_GetChannelByName(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetChannelByName(tt.input)
+			got, err := r.GetChannelByName(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError, err)
@@ -300,7 +363,7 @@ func Test_GetChannelStats(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewChannelRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -318,8 +381,8 @@ func Test_GetChannelStats(t *testing.T) {
 
 				mock.ExpectQuery(
 					`SELECT m.id, COUNT(r.id) 
-					FROM channel c LEFT JOIN message m ON m.channel_id = c.id 
-					LEFT JOIN reply r ON r.message_id = m.id 
+					FROM channel c LEFT JOIN message m ON m.channel_id = c.id AND m.hidden = FALSE 
+					LEFT JOIN reply r ON r.message_id = m.id AND r.hidden = FALSE 
 					WHERE c.id = $1 GROUP BY m.id;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
@@ -335,8 +398,8 @@ func Test_GetChannelStats(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id", "count"})
 
 				mock.ExpectQuery(`SELECT m.id, COUNT(r.id) 
-					FROM channel c LEFT JOIN message m ON m.channel_id = c.id 
-					LEFT JOIN reply r ON r.message_id = m.id 
+					FROM channel c LEFT JOIN message m ON m.channel_id = c.id AND m.hidden = FALSE 
+					LEFT JOIN reply r ON r.message_id = m.id AND r.hidden = FALSE 
 					WHERE c.id = $1 GROUP BY m.id;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
@@ -350,8 +413,8 @@ func Test_GetChannelStats(t *testing.T) {
 			name: "GetChannelStats failed with some sql error",
 			mock: func() {
 				mock.ExpectQuery(`SELECT m.id, COUNT(r.id) 
-					FROM channel c LEFT JOIN message m ON m.channel_id = c.id 
-					LEFT JOIN reply r ON r.message_id = m.id 
+					FROM channel c LEFT JOIN message m ON m.channel_id = c.id AND m.hidden = FALSE 
+					LEFT JOIN reply r ON r.message_id = m.id AND r.hidden = FALSE 
 					WHERE c.id = $1 GROUP BY m.id;`,
 				).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
 			},
@@ -363,7 +426,7 @@ func Test_GetChannelStats(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetChannelStats(tt.input)
+			got, err := r.GetChannelStats(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -376,3 +439,193 @@ func Test_GetChannelStats(t *testing.T) {
 		})
 	}
 }
+
+func Test_UpdateChannelEnabled(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE channel SET enabled = $1 WHERE id = $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "UpdateChannelEnabled successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(false, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "UpdateChannelEnabled failed with not found channel",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(false, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "UpdateChannelEnabled failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(false, 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateChannelEnabled(context.Background(), 1, false)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetAllChannels(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "SELECT * FROM channel ORDER BY id;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.Channel
+		expectedError error
+	}{
+		{
+			name: "GetAllChannels successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "name", "title", "image_url", "hidden"}).
+					AddRow(1, "test1", "test1", "test1.jpg", false).
+					AddRow(2, "test2", "test2", "test2.jpg", true)
+
+				mock.ExpectQuery(query).WillReturnRows(rows)
+			},
+			want: []model.Channel{
+				{ID: 1, Name: "test1", Title: "test1", ImageURL: "test1.jpg"},
+				{ID: 2, Name: "test2", Title: "test2", ImageURL: "test2.jpg", Hidden: true},
+			},
+		},
+		{
+			name: "GetAllChannels failed with not found channels",
+			mock: func() {
+				mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "title", "image_url"}))
+			},
+		},
+		{
+			name: "GetAllChannels failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetAllChannels(context.Background())
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UpdateChannelHidden(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE channel SET hidden = $1 WHERE id = $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "UpdateChannelHidden successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "UpdateChannelHidden failed with not found channel",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "UpdateChannelHidden failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateChannelHidden(context.Background(), 1, true)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
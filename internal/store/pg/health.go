@@ -0,0 +1,43 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+type HealthRepo struct {
+	db *DB
+}
+
+func NewHealthRepo(db *DB) *HealthRepo {
+	return &HealthRepo{db: db}
+}
+
+func (repo HealthRepo) Ping(ctx context.Context) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	return repo.db.PingContext(ctx)
+}
+
+// GetMigrationVersion returns version of the schema recorded by golang-migrate.
+func (repo HealthRepo) GetMigrationVersion(ctx context.Context) (*model.MigrationVersion, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var version model.MigrationVersion
+
+	err := repo.db.GetContext(ctx, &version, "SELECT version, dirty FROM schema_migrations LIMIT 1;")
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &version, nil
+}
@@ -0,0 +1,79 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+func Test_GetMigrationVersion(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewHealthRepo(pg.NewDB(sqlxDB, 0))
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.MigrationVersion
+		expectedError error
+	}{
+		{
+			name: "GetMigrationVersion successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"version", "dirty"}).AddRow(3, false)
+
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1;").WillReturnRows(rows)
+			},
+			want: &model.MigrationVersion{Version: 3},
+		},
+		{
+			name: "GetMigrationVersion failed with not applied migrations",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"version", "dirty"})
+
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1;").WillReturnRows(rows)
+			},
+		},
+		{
+			name: "GetMigrationVersion failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1;").
+					WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetMigrationVersion(context.Background())
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
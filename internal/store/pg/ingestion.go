@@ -0,0 +1,244 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+type IngestionErrorRepo struct {
+	db *DB
+}
+
+func NewIngestionErrorRepo(db *DB) *IngestionErrorRepo {
+	return &IngestionErrorRepo{db: db}
+}
+
+func (repo IngestionErrorRepo) CreateIngestionError(ctx context.Context, ingestionError *model.IngestionError) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
+		`INSERT INTO ingestion_error(topic, partition, record_offset, content_type, reason, value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+		ingestionError.Topic, ingestionError.Partition, ingestionError.Offset, ingestionError.ContentType,
+		ingestionError.Reason, ingestionError.Value, ingestionError.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetIngestionErrors returns the latest ingestion errors, newest first.
+func (repo IngestionErrorRepo) GetIngestionErrors(ctx context.Context, limit int) ([]model.IngestionError, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var ingestionErrors []model.IngestionError
+
+	err := repo.db.SelectContext(
+		ctx, &ingestionErrors, "SELECT * FROM ingestion_error ORDER BY created_at DESC, id DESC LIMIT $1;", limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ingestionErrors) == 0 {
+		return nil, nil
+	}
+
+	return ingestionErrors, nil
+}
+
+func (repo IngestionErrorRepo) GetIngestionErrorByID(ctx context.Context, id int) (*model.IngestionError, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var ingestionError model.IngestionError
+
+	err := repo.db.GetContext(ctx, &ingestionError, "SELECT * FROM ingestion_error WHERE id = $1;", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &ingestionError, nil
+}
+
+func (repo IngestionErrorRepo) DeleteIngestionError(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, "DELETE FROM ingestion_error WHERE id = $1;", id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+type BufferedRecordRepo struct {
+	db *DB
+}
+
+func NewBufferedRecordRepo(db *DB) *BufferedRecordRepo {
+	return &BufferedRecordRepo{db: db}
+}
+
+func (repo BufferedRecordRepo) CreateBufferedRecord(ctx context.Context, record *model.BufferedRecord) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
+		`INSERT INTO buffered_record(channel_id, topic, partition, record_offset, content_type, value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`,
+		record.ChannelID, record.Topic, record.Partition, record.Offset, record.ContentType,
+		record.Value, record.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimBufferedRecord deletes the oldest buffered record of the channel and returns it, so records are replayed
+// in order. Records claimed by concurrent transactions are skipped, so a record is never returned twice.
+func (repo BufferedRecordRepo) ClaimBufferedRecord(ctx context.Context, channelID int) (*model.BufferedRecord, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var record model.BufferedRecord
+
+	err := repo.db.GetContext(
+		ctx,
+		&record,
+		`DELETE FROM buffered_record WHERE id = (
+			SELECT id FROM buffered_record WHERE channel_id = $1 ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED
+		) RETURNING *;`,
+		channelID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+type QuarantineRepo struct {
+	db *DB
+}
+
+func NewQuarantineRepo(db *DB) *QuarantineRepo {
+	return &QuarantineRepo{db: db}
+}
+
+func (repo QuarantineRepo) CreateQuarantinedMessage(ctx context.Context, message *model.QuarantinedMessage) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
+		`INSERT INTO quarantined_message(
+			channel_id, title, rule, reason, topic, partition, record_offset, content_type, value, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`,
+		message.ChannelID, message.Title, message.Rule, message.Reason, message.Topic, message.Partition,
+		message.Offset, message.ContentType, message.Value, message.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetQuarantinedMessagesByPage returns the quarantined messages with names of their channels, newest first.
+func (repo QuarantineRepo) GetQuarantinedMessagesByPage(
+	ctx context.Context, page int,
+) ([]model.QuarantinedMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var messages []model.QuarantinedMessage
+
+	err := repo.db.SelectContext(
+		ctx,
+		&messages,
+		`SELECT q.*, c.name AS channel_name
+		FROM quarantined_message q
+		LEFT JOIN channel c ON c.id = q.channel_id
+		ORDER BY q.created_at DESC, q.id DESC
+		LIMIT 10 OFFSET $1;`,
+		page,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	return messages, nil
+}
+
+func (repo QuarantineRepo) GetQuarantinedMessageByID(ctx context.Context, id int) (*model.QuarantinedMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var message model.QuarantinedMessage
+
+	err := repo.db.GetContext(
+		ctx,
+		&message,
+		`SELECT q.*, c.name AS channel_name
+		FROM quarantined_message q
+		LEFT JOIN channel c ON c.id = q.channel_id
+		WHERE q.id = $1;`,
+		id,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+func (repo QuarantineRepo) DeleteQuarantinedMessage(ctx context.Context, id int) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, "DELETE FROM quarantined_message WHERE id = $1;", id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
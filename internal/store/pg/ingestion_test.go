@@ -0,0 +1,700 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+var ingestionErrorColumns = []string{
+	"id", "topic", "partition", "record_offset", "content_type", "reason", "value", "created_at",
+}
+
+func Test_CreateIngestionError(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewIngestionErrorRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	input := &model.IngestionError{
+		Topic: "messages", Partition: 0, Offset: 10, Reason: "invalid", Value: []byte("{}"), CreatedAt: createdAt,
+	}
+
+	query := `INSERT INTO ingestion_error(topic, partition, record_offset, content_type, reason, value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "CreateIngestionError successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("messages", 0, 10, "", "invalid", []byte("{}"), createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "CreateIngestionError failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("messages", 0, 10, "", "invalid", []byte("{}"), createdAt).
+					WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateIngestionError(context.Background(), input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetIngestionErrors(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewIngestionErrorRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := "SELECT * FROM ingestion_error ORDER BY created_at DESC, id DESC LIMIT $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.IngestionError
+		expectedError error
+	}{
+		{
+			name: "GetIngestionErrors successful",
+			mock: func() {
+				rows := sqlmock.NewRows(ingestionErrorColumns).
+					AddRow(1, "messages", 0, 10, "", "invalid", []byte("{}"), createdAt)
+
+				mock.ExpectQuery(query).WithArgs(20).WillReturnRows(rows)
+			},
+			want: []model.IngestionError{
+				{ID: 1, Topic: "messages", Offset: 10, Reason: "invalid", Value: []byte("{}"), CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "GetIngestionErrors failed with not found errors",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(20).WillReturnRows(sqlmock.NewRows(ingestionErrorColumns))
+			},
+		},
+		{
+			name: "GetIngestionErrors failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(20).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetIngestionErrors(context.Background(), 20)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetIngestionErrorByID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewIngestionErrorRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := "SELECT * FROM ingestion_error WHERE id = $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.IngestionError
+		expectedError error
+	}{
+		{
+			name: "GetIngestionErrorByID successful",
+			mock: func() {
+				rows := sqlmock.NewRows(ingestionErrorColumns).
+					AddRow(1, "groups", 0, 5, "protobuf", "invalid", []byte("data"), createdAt)
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: &model.IngestionError{
+				ID:          1,
+				Topic:       "groups",
+				Offset:      5,
+				ContentType: "protobuf",
+				Reason:      "invalid",
+				Value:       []byte("data"),
+				CreatedAt:   createdAt,
+			},
+		},
+		{
+			name: "GetIngestionErrorByID failed with not found error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows(ingestionErrorColumns))
+			},
+		},
+		{
+			name: "GetIngestionErrorByID failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetIngestionErrorByID(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteIngestionError(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewIngestionErrorRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "DELETE FROM ingestion_error WHERE id = $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "DeleteIngestionError successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "DeleteIngestionError failed with not found error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "DeleteIngestionError failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.DeleteIngestionError(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+var bufferedRecordColumns = []string{
+	"id", "channel_id", "topic", "partition", "record_offset", "content_type", "value", "created_at",
+}
+
+func Test_CreateBufferedRecord(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewBufferedRecordRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	input := &model.BufferedRecord{
+		ChannelID: 1, Topic: "messages", Partition: 0, Offset: 10, Value: []byte("{}"), CreatedAt: createdAt,
+	}
+
+	query := `INSERT INTO buffered_record(channel_id, topic, partition, record_offset, content_type, value, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "CreateBufferedRecord successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "messages", 0, 10, "", []byte("{}"), createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "CreateBufferedRecord failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "messages", 0, 10, "", []byte("{}"), createdAt).
+					WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateBufferedRecord(context.Background(), input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_ClaimBufferedRecord(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewBufferedRecordRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := `DELETE FROM buffered_record WHERE id = (
+			SELECT id FROM buffered_record WHERE channel_id = $1 ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED
+		) RETURNING *;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.BufferedRecord
+		expectedError error
+	}{
+		{
+			name: "ClaimBufferedRecord successful",
+			mock: func() {
+				rows := sqlmock.NewRows(bufferedRecordColumns).
+					AddRow(1, 1, "messages", 0, 10, "", []byte("{}"), createdAt)
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: &model.BufferedRecord{
+				ID: 1, ChannelID: 1, Topic: "messages", Offset: 10, Value: []byte("{}"), CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "ClaimBufferedRecord failed with not found record",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows(bufferedRecordColumns))
+			},
+		},
+		{
+			name: "ClaimBufferedRecord failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.ClaimBufferedRecord(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+var quarantinedMessageColumns = []string{
+	"id", "channel_id", "title", "rule", "reason", "topic", "partition", "record_offset", "content_type", "value",
+	"created_at", "channel_name",
+}
+
+func Test_CreateQuarantinedMessage(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewQuarantineRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	input := &model.QuarantinedMessage{
+		ChannelID: 1, Title: "hi", Rule: "min_length", Reason: "message is shorter than 10 characters",
+		Topic: "messages", Offset: 10, Value: []byte("{}"), CreatedAt: createdAt,
+	}
+
+	query := `INSERT INTO quarantined_message(
+			channel_id, title, rule, reason, topic, partition, record_offset, content_type, value, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "CreateQuarantinedMessage successful",
+			mock: func() {
+				mock.ExpectExec(query).
+					WithArgs(
+						1, "hi", "min_length", "message is shorter than 10 characters", "messages", 0, 10, "",
+						[]byte("{}"), createdAt,
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "CreateQuarantinedMessage failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).
+					WithArgs(
+						1, "hi", "min_length", "message is shorter than 10 characters", "messages", 0, 10, "",
+						[]byte("{}"), createdAt,
+					).
+					WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateQuarantinedMessage(context.Background(), input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetQuarantinedMessagesByPage(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewQuarantineRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := `SELECT q.*, c.name AS channel_name
+		FROM quarantined_message q
+		LEFT JOIN channel c ON c.id = q.channel_id
+		ORDER BY q.created_at DESC, q.id DESC
+		LIMIT 10 OFFSET $1;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.QuarantinedMessage
+		expectedError error
+	}{
+		{
+			name: "GetQuarantinedMessagesByPage successful",
+			mock: func() {
+				rows := sqlmock.NewRows(quarantinedMessageColumns).
+					AddRow(1, 1, "hi", "min_length", "too short", "messages", 0, 10, "", []byte("{}"), createdAt, "go")
+
+				mock.ExpectQuery(query).WithArgs(0).WillReturnRows(rows)
+			},
+			want: []model.QuarantinedMessage{
+				{
+					ID: 1, ChannelID: 1, Title: "hi", Rule: "min_length", Reason: "too short", Topic: "messages",
+					Offset: 10, Value: []byte("{}"), CreatedAt: createdAt, ChannelName: "go",
+				},
+			},
+		},
+		{
+			name: "GetQuarantinedMessagesByPage failed with not found messages",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(0).WillReturnRows(sqlmock.NewRows(quarantinedMessageColumns))
+			},
+		},
+		{
+			name: "GetQuarantinedMessagesByPage failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(0).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetQuarantinedMessagesByPage(context.Background(), 0)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetQuarantinedMessageByID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewQuarantineRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := `SELECT q.*, c.name AS channel_name
+		FROM quarantined_message q
+		LEFT JOIN channel c ON c.id = q.channel_id
+		WHERE q.id = $1;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.QuarantinedMessage
+		expectedError error
+	}{
+		{
+			name: "GetQuarantinedMessageByID successful",
+			mock: func() {
+				rows := sqlmock.NewRows(quarantinedMessageColumns).
+					AddRow(1, 1, "hi", "min_length", "too short", "messages", 0, 10, "", []byte("{}"), createdAt, "go")
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: &model.QuarantinedMessage{
+				ID: 1, ChannelID: 1, Title: "hi", Rule: "min_length", Reason: "too short", Topic: "messages",
+				Offset: 10, Value: []byte("{}"), CreatedAt: createdAt, ChannelName: "go",
+			},
+		},
+		{
+			name: "GetQuarantinedMessageByID failed with not found message",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows(quarantinedMessageColumns))
+			},
+		},
+		{
+			name: "GetQuarantinedMessageByID failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetQuarantinedMessageByID(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteQuarantinedMessage(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewQuarantineRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "DELETE FROM quarantined_message WHERE id = $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "DeleteQuarantinedMessage successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "DeleteQuarantinedMessage failed with not found message",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "DeleteQuarantinedMessage failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.DeleteQuarantinedMessage(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
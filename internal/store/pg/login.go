@@ -0,0 +1,96 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+type LoginFailureRepo struct {
+	db *DB
+}
+
+func NewLoginFailureRepo(db *DB) *LoginFailureRepo {
+	return &LoginFailureRepo{db: db}
+}
+
+func (repo LoginFailureRepo) CreateLoginFailure(ctx context.Context, failure *model.LoginFailure) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
+		"INSERT INTO login_failure(email, ip_address, created_at) VALUES ($1, $2, $3);",
+		failure.Email, failure.IPAddress, failure.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLoginFailuresByEmail returns number and time of the last failed attempts for the email since the given time.
+func (repo LoginFailureRepo) GetLoginFailuresByEmail(
+	ctx context.Context, email string, since time.Time,
+) (*model.LoginFailureStats, error) {
+	return repo.getLoginFailures(
+		ctx,
+		`SELECT COUNT(*) AS count, COALESCE(MAX(created_at), TO_TIMESTAMP(0)) AS last_at
+		FROM login_failure WHERE email = $1 AND created_at > $2;`,
+		email, since,
+	)
+}
+
+// GetLoginFailuresByIP returns number and time of the last failed attempts from the address since the given time.
+func (repo LoginFailureRepo) GetLoginFailuresByIP(
+	ctx context.Context, ipAddress string, since time.Time,
+) (*model.LoginFailureStats, error) {
+	return repo.getLoginFailures(
+		ctx,
+		`SELECT COUNT(*) AS count, COALESCE(MAX(created_at), TO_TIMESTAMP(0)) AS last_at
+		FROM login_failure WHERE ip_address = $1 AND created_at > $2;`,
+		ipAddress, since,
+	)
+}
+
+func (repo LoginFailureRepo) getLoginFailures(
+	ctx context.Context, query string, args ...interface{},
+) (*model.LoginFailureStats, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var stats model.LoginFailureStats
+
+	err := repo.db.GetContext(ctx, &stats, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+func (repo LoginFailureRepo) DeleteLoginFailuresByEmail(ctx context.Context, email string) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM login_failure WHERE email = $1;", email)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo LoginFailureRepo) DeleteLoginFailuresBefore(ctx context.Context, before time.Time) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM login_failure WHERE created_at < $1;", before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
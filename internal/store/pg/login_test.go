@@ -0,0 +1,302 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+func Test_CreateLoginFailure(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewLoginFailureRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := "INSERT INTO login_failure(email, ip_address, created_at) VALUES ($1, $2, $3);"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         *model.LoginFailure
+		expectedError error
+	}{
+		{
+			name: "CreateLoginFailure successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("test@test.com", "127.0.0.1", createdAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			input: &model.LoginFailure{Email: "test@test.com", IPAddress: "127.0.0.1", CreatedAt: createdAt},
+		},
+		{
+			name: "CreateLoginFailure failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("test@test.com", "127.0.0.1", createdAt).
+					WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         &model.LoginFailure{Email: "test@test.com", IPAddress: "127.0.0.1", CreatedAt: createdAt},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateLoginFailure(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetLoginFailuresByEmail(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewLoginFailureRepo(pg.NewDB(sqlxDB, 0))
+
+	since := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	lastAt := since.Add(time.Minute)
+
+	query := `SELECT COUNT(*) AS count, COALESCE(MAX(created_at), TO_TIMESTAMP(0)) AS last_at
+		FROM login_failure WHERE email = $1 AND created_at > $2;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.LoginFailureStats
+		expectedError error
+	}{
+		{
+			name: "GetLoginFailuresByEmail successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"count", "last_at"}).AddRow(3, lastAt)
+
+				mock.ExpectQuery(query).WithArgs("test@test.com", since).WillReturnRows(rows)
+			},
+			want: &model.LoginFailureStats{Count: 3, LastAt: lastAt},
+		},
+		{
+			name: "GetLoginFailuresByEmail failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("test@test.com", since).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetLoginFailuresByEmail(context.Background(), "test@test.com", since)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetLoginFailuresByIP(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewLoginFailureRepo(pg.NewDB(sqlxDB, 0))
+
+	since := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := `SELECT COUNT(*) AS count, COALESCE(MAX(created_at), TO_TIMESTAMP(0)) AS last_at
+		FROM login_failure WHERE ip_address = $1 AND created_at > $2;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.LoginFailureStats
+		expectedError error
+	}{
+		{
+			name: "GetLoginFailuresByIP successful without failures",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"count", "last_at"}).AddRow(0, time.Unix(0, 0).UTC())
+
+				mock.ExpectQuery(query).WithArgs("127.0.0.1", since).WillReturnRows(rows)
+			},
+			want: &model.LoginFailureStats{Count: 0, LastAt: time.Unix(0, 0).UTC()},
+		},
+		{
+			name: "GetLoginFailuresByIP failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("127.0.0.1", since).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetLoginFailuresByIP(context.Background(), "127.0.0.1", since)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteLoginFailuresByEmail(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewLoginFailureRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "DELETE FROM login_failure WHERE email = $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "DeleteLoginFailuresByEmail successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("test@test.com").WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+		},
+		{
+			name: "DeleteLoginFailuresByEmail failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("test@test.com").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.DeleteLoginFailuresByEmail(context.Background(), "test@test.com")
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteLoginFailuresBefore(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewLoginFailureRepo(pg.NewDB(sqlxDB, 0))
+
+	before := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := "DELETE FROM login_failure WHERE created_at < $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "DeleteLoginFailuresBefore successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+		},
+		{
+			name: "DeleteLoginFailuresBefore failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(before).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.DeleteLoginFailuresBefore(context.Background(), before)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
@@ -15,13 +16,17 @@ func NewMessageRepo(db *DB) *MessageRepo {
 	return &MessageRepo{db: db}
 }
 
-func (repo MessageRepo) CreateMessage(message *model.DBMessage) (int, error) {
+func (repo MessageRepo) CreateMessage(ctx context.Context, message *model.DBMessage) (int, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var id int
 
-	row := repo.db.QueryRow(`
-		INSERT INTO message(channel_id, user_id, title, message_url, image_url) VALUES ($1, $2, $3, $4, $5) RETURNING id;`,
+	row := repo.db.QueryRowContext(ctx, `
+		INSERT INTO message(channel_id, user_id, title, message_url, image_url, kind)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
 		message.ChannelID, message.UserID, message.Title,
-		message.MessageURL, message.ImageURL,
+		message.MessageURL, message.ImageURL, message.Kind,
 	)
 	if err := row.Scan(&id); err != nil {
 		return 0, err
@@ -30,10 +35,18 @@ func (repo MessageRepo) CreateMessage(message *model.DBMessage) (int, error) {
 	return id, nil
 }
 
-func (repo MessageRepo) GetMessagesCount() (int, error) {
+func (repo MessageRepo) GetMessagesCount(ctx context.Context) (int, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var count int
 
-	err := repo.db.Get(&count, "SELECT COUNT(*) FROM message;")
+	err := repo.db.GetContext(
+		ctx,
+		&count,
+		`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+		 WHERE c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
+	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return 0, nil
@@ -45,12 +58,17 @@ func (repo MessageRepo) GetMessagesCount() (int, error) {
 	return count, nil
 }
 
-func (repo MessageRepo) GetMessagesCountByChannelID(channelID int) (int, error) {
+func (repo MessageRepo) GetMessagesCountByChannelID(ctx context.Context, channelID int) (int, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var count int
 
-	err := repo.db.Get(
+	err := repo.db.GetContext(
+		ctx,
 		&count,
-		`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id WHERE m.channel_id = $1;`,
+		`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+		 WHERE m.channel_id = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
 		channelID,
 	)
 	if err != nil {
@@ -64,10 +82,37 @@ func (repo MessageRepo) GetMessagesCountByChannelID(channelID int) (int, error)
 	return count, nil
 }
 
-func (repo MessageRepo) GetMessageByTitle(title string) (*model.DBMessage, error) {
+func (repo MessageRepo) GetMessagesCountByKind(ctx context.Context, kind model.MessageKind) (int, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+
+	err := repo.db.GetContext(
+		ctx,
+		&count,
+		`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+		 WHERE m.kind = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
+		kind,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (repo MessageRepo) GetMessageByTitle(ctx context.Context, title string) (*model.DBMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var message model.DBMessage
 
-	err := repo.db.Get(&message, "SELECT * FROM message WHERE title = $1;", title)
+	err := repo.db.GetContext(ctx, &message, "SELECT * FROM message WHERE title = $1;", title)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -79,18 +124,23 @@ func (repo MessageRepo) GetMessageByTitle(title string) (*model.DBMessage, error
 	return &message, nil
 }
 
-func (repo MessageRepo) GetFullMessagesByPage(page int) ([]model.FullMessage, error) {
+func (repo MessageRepo) GetFullMessagesByPage(ctx context.Context, page int) ([]model.FullMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var messages []model.FullMessage
 
-	err := repo.db.Select(
+	err := repo.db.SelectContext(
+		ctx,
 		&messages,
-		`SELECT m.id, m.title, m.message_url, m.image_url, 
+		`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url, 
 		 u.id AS user_id, u.fullname, u.image_url AS user_image_url, 
-		 (SELECT COUNT(*) FROM reply WHERE message_id = m.id)
+		 (SELECT COUNT(*) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 FROM message m 
 		 LEFT JOIN channel c ON c.id = m.channel_id 
 		 LEFT JOIN tg_user u ON u.id = m.user_id
+		 WHERE c.hidden IS NOT TRUE AND m.hidden = FALSE
 		 ORDER BY m.id DESC NULLS LAST LIMIT 10 OFFSET $1;`,
 		page,
 	)
@@ -105,19 +155,56 @@ func (repo MessageRepo) GetFullMessagesByPage(page int) ([]model.FullMessage, er
 	return messages, nil
 }
 
-func (repo MessageRepo) GetFullMessagesByChannelIDAndPage(channelID, page int) ([]model.FullMessage, error) {
+func (repo MessageRepo) GetFullMessagesByKindAndPage(
+	ctx context.Context, kind model.MessageKind, page int,
+) ([]model.FullMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var messages []model.FullMessage
 
-	err := repo.db.Select(
+	err := repo.db.SelectContext(
+		ctx,
 		&messages,
-		`SELECT m.id, m.title, m.message_url, m.image_url, 
+		`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
+		 c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url,
+		 u.id AS user_id, u.fullname, u.image_url AS user_image_url,
+		 (SELECT COUNT(*) FROM reply WHERE message_id = m.id AND hidden = FALSE)
+		 FROM message m
+		 LEFT JOIN channel c ON c.id = m.channel_id
+		 LEFT JOIN tg_user u ON u.id = m.user_id
+		 WHERE m.kind = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
+		 ORDER BY m.id DESC NULLS LAST LIMIT 10 OFFSET $2;`,
+		kind, page,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	return messages, nil
+}
+
+func (repo MessageRepo) GetFullMessagesByChannelIDAndPage(ctx context.Context, channelID, page int) ([]model.FullMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var messages []model.FullMessage
+
+	err := repo.db.SelectContext(
+		ctx,
+		&messages,
+		`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url, 
 		 u.id AS user_id, u.fullname, u.image_url AS user_image_url, 
-		 (SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 (SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 FROM message m 
 		 LEFT JOIN channel c ON c.id = m.channel_id 
 		 LEFT JOIN tg_user u ON u.id = m.user_id
-	 	 WHERE m.channel_id = $1 
+	 	 WHERE m.channel_id = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
 		 ORDER BY count DESC NULLS LAST LIMIT 10 OFFSET $2;`,
 		channelID, page,
 	)
@@ -132,18 +219,22 @@ func (repo MessageRepo) GetFullMessagesByChannelIDAndPage(channelID, page int) (
 	return messages, nil
 }
 
-func (repo MessageRepo) GetFullMessagesByUserID(id int) ([]model.FullMessage, error) {
+func (repo MessageRepo) GetFullMessagesByUserID(ctx context.Context, id int) ([]model.FullMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var messages []model.FullMessage
 
-	err := repo.db.Select(
+	err := repo.db.SelectContext(
+		ctx,
 		&messages,
-		`SELECT m.id, m.title, m.message_url, m.image_url, 
+		`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 c.id AS channel_id, c.name AS channel_name, c.Title AS channel_title, c.image_url AS channel_image_url, 
-		 (SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 (SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 FROM message m 
 		 LEFT JOIN channel c ON c.id = m.channel_id 
 		 LEFT JOIN tg_user u ON u.id = m.user_id
-		 WHERE m.user_id= $1 
+		 WHERE m.user_id= $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
 		 ORDER BY count DESC NULLS LAST;`,
 		id,
 	)
@@ -158,19 +249,23 @@ func (repo MessageRepo) GetFullMessagesByUserID(id int) ([]model.FullMessage, er
 	return messages, nil
 }
 
-func (repo MessageRepo) GetFullMessageByID(messageID int) (*model.FullMessage, error) {
+func (repo MessageRepo) GetFullMessageByID(ctx context.Context, messageID int) (*model.FullMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var message model.FullMessage
 
-	err := repo.db.Get(
+	err := repo.db.GetContext(
+		ctx,
 		&message,
-		`SELECT m.id, m.title, m.message_url, m.image_url, 
+		`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 c.id AS channel_id, c.name AS channel_name, c.title as channel_title, c.image_url as channel_image_url, 
-		 u.id as user_id, u.fullname, u.image_url as user_image_url, 
-		 (SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 u.id as user_id, u.fullname, u.image_url as user_image_url, m.hidden,
+		 (SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 FROM message m 
 		 LEFT JOIN channel c ON c.id = m.channel_id 
 		 LEFT JOIN tg_user u ON u.id = m.user_id
-		 WHERE m.id = $1;`,
+		 WHERE m.id = $1 AND c.hidden IS NOT TRUE;`,
 		messageID,
 	)
 	if err != nil {
@@ -183,3 +278,60 @@ func (repo MessageRepo) GetFullMessageByID(messageID int) (*model.FullMessage, e
 
 	return &message, nil
 }
+
+// UpdateMessageHidden hides or shows the message, hidden messages are excluded from feeds.
+func (repo MessageRepo) UpdateMessageHidden(ctx context.Context, id int, hidden bool) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, "UPDATE message SET hidden = $1 WHERE id = $2;", hidden, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetMessagesAfterID returns the messages with ID greater than the given one ordered by ID, it's used to walk
+// through all messages in batches.
+func (repo MessageRepo) GetMessagesAfterID(ctx context.Context, afterID int, limit int) ([]model.DBMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var messages []model.DBMessage
+
+	err := repo.db.SelectContext(
+		ctx, &messages, "SELECT * FROM message WHERE id > $1 ORDER BY id LIMIT $2;", afterID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	return messages, nil
+}
+
+func (repo MessageRepo) UpdateMessageKind(ctx context.Context, id int, kind model.MessageKind) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, "UPDATE message SET kind = $1 WHERE id = $2;", kind, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
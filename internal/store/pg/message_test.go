@@ -1,6 +1,7 @@
 package pg_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -21,7 +22,7 @@ func Test_CreateMessage(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -37,18 +38,27 @@ func Test_CreateMessage(t *testing.T) {
 				row := sqlmock.NewRows([]string{"id"}).AddRow(1)
 
 				mock.ExpectQuery(
-					"INSERT INTO message(channel_id, user_id, title, message_url, image_url) VALUES ($1, $2, $3, $4, $5) RETURNING id;", //nolint:lll
-				).WithArgs(1, 1, "test", "test.url", "test.jpg").WillReturnRows(row)
+					`INSERT INTO message(channel_id, user_id, title, message_url, image_url, kind)
+					VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
+				).WithArgs(1, 1, "test", "test.url", "test.jpg", "question").WillReturnRows(row)
 			},
-			input: &model.DBMessage{ChannelID: 1, UserID: 1, Title: "test", MessageURL: "test.url", ImageURL: "test.jpg"},
-			want:  1,
+			input: &model.DBMessage{
+				ChannelID:  1,
+				UserID:     1,
+				Title:      "test",
+				MessageURL: "test.url",
+				ImageURL:   "test.jpg",
+				Kind:       model.MessageKindQuestion,
+			},
+			want: 1,
 		},
 		{
 			name: "CreateMessage failed with some sql error",
 			mock: func() {
 				mock.ExpectQuery(
-					"INSERT INTO message(channel_id, user_id, title, message_url, image_url) VALUES ($1, $2, $3, $4, $5) RETURNING id;", //nolint:lll
-				).WithArgs(1, 1, "test", "test.url", "test.jpg").WillReturnError(fmt.Errorf("some sql error"))
+					`INSERT INTO message(channel_id, user_id, title, message_url, image_url, kind)
+					VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
+				).WithArgs(1, 1, "test", "test.url", "test.jpg", "other").WillReturnError(fmt.Errorf("some sql error"))
 			},
 			input: &model.DBMessage{
 				ChannelID:  1,
@@ -56,6 +66,7 @@ func Test_CreateMessage(t *testing.T) {
 				Title:      "test",
 				MessageURL: "test.url",
 				ImageURL:   "test.jpg",
+				Kind:       model.MessageKindOther,
 			},
 			wantErr:       true,
 			expectedError: fmt.Errorf("some sql error"),
@@ -65,7 +76,7 @@ func Test_CreateMessage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.CreateMessage(tt.input)
+			got, err := r.CreateMessage(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -91,7 +102,7 @@ func Test_GetMessagesCount(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -106,7 +117,10 @@ func Test_GetMessagesCount(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"count"}).
 					AddRow(10)
 
-				mock.ExpectQuery("SELECT COUNT(*) FROM message;").
+				mock.ExpectQuery(
+					`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+					WHERE c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
+				).
 					WillReturnRows(rows)
 			},
 			want: 10,
@@ -116,7 +130,10 @@ func Test_GetMessagesCount(t *testing.T) {
 			mock: func() {
 				rows := sqlmock.NewRows([]string{"count"})
 
-				mock.ExpectQuery("SELECT COUNT(*) FROM message;").
+				mock.ExpectQuery(
+					`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+					WHERE c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
+				).
 					WillReturnRows(rows)
 			},
 			expectedError: nil,
@@ -124,7 +141,10 @@ func Test_GetMessagesCount(t *testing.T) {
 		{
 			name: "GetMessagesCount failed with some sql error",
 			mock: func() {
-				mock.ExpectQuery("SELECT COUNT(*) FROM message;").
+				mock.ExpectQuery(
+					`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+					WHERE c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
+				).
 					WillReturnError(fmt.Errorf("some sql error"))
 			},
 			expectedError: fmt.Errorf("some sql error"),
@@ -134,7 +154,7 @@ func Test_GetMessagesCount(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetMessagesCount()
+			got, err := r.GetMessagesCount(context.Background())
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -160,7 +180,7 @@ func Test_GetMessagesCountByChannelID(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -176,7 +196,8 @@ func Test_GetMessagesCountByChannelID(t *testing.T) {
 					AddRow(2)
 
 				mock.ExpectQuery(
-					"SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id WHERE m.channel_id = $1;",
+					`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+					WHERE m.channel_id = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
 			input: 1,
@@ -188,7 +209,8 @@ func Test_GetMessagesCountByChannelID(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id"})
 
 				mock.ExpectQuery(
-					"SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id WHERE m.channel_id = $1;",
+					`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+					WHERE m.channel_id = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
 			input:         1,
@@ -198,7 +220,8 @@ func Test_GetMessagesCountByChannelID(t *testing.T) {
 			name: "GetMessagesCountByChannelID failed with some sql error",
 			mock: func() {
 				mock.ExpectQuery(
-					"SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id WHERE m.channel_id = $1;",
+					`SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+					WHERE m.channel_id = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE;`,
 				).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
 			},
 			input:         1,
@@ -208,7 +231,7 @@ func Test_GetMessagesCountByChannelID(t *testing.T) {
 	for _, tt := range tests {
 		tt.mock()
 
-		got, err := r.GetMessagesCountByChannelID(tt.input)
+		got, err := r.GetMessagesCountByChannelID(context.Background(), tt.input)
 		if tt.expectedError != nil {
 			assert.Error(t, err)
 			assert.EqualValues(t, tt.expectedError, err)
@@ -232,7 +255,7 @@ func Test_GetMessageByTitle(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -280,7 +303,7 @@ func Test_GetMessageByTitle(t *testing.T) {
 	for _, tt := range tests {
 		tt.mock()
 
-		got, err := r.GetMessageByTitle(tt.input)
+		got, err := r.GetMessageByTitle(context.Background(), tt.input)
 		if tt.expectedError != nil {
 			assert.Error(t, err)
 			assert.EqualValues(t, tt.expectedError, err)
@@ -305,7 +328,7 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -329,13 +352,14 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 					AddRow(2, "test2", "test2.com", "test2.jpg", 2, "test2", "test2.jpg", 2, "test2", "test2.jpg", 3)
 
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 					c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url, 
 					u.id AS user_id, u.fullname, u.image_url AS user_image_url, 
-					(SELECT COUNT(*) FROM reply WHERE message_id = m.id)
+					(SELECT COUNT(*) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 					FROM message m 
 					LEFT JOIN channel c ON c.id = m.channel_id 
 					LEFT JOIN tg_user u ON u.id = m.user_id
+					WHERE c.hidden IS NOT TRUE AND m.hidden = FALSE
 					ORDER BY m.id DESC NULLS LAST LIMIT 10 OFFSET $1;`,
 				).WithArgs(10).WillReturnRows(rows)
 			},
@@ -368,13 +392,14 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 				)
 
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 					c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url, 
 					u.id AS user_id, u.fullname, u.image_url AS user_image_url, 
-					(SELECT COUNT(*) FROM reply WHERE message_id = m.id)
+					(SELECT COUNT(*) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 					FROM message m 
 					LEFT JOIN channel c ON c.id = m.channel_id 
 					LEFT JOIN tg_user u ON u.id = m.user_id
+					WHERE c.hidden IS NOT TRUE AND m.hidden = FALSE
 					ORDER BY m.id DESC NULLS LAST LIMIT 10 OFFSET $1;`,
 				).WithArgs(10).WillReturnRows(rows)
 			},
@@ -385,13 +410,14 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 			name: "GetFullMessagesByPage failed with some sql error",
 			mock: func() {
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 					c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url, 
 					u.id AS user_id, u.fullname, u.image_url AS user_image_url, 
-					(SELECT COUNT(*) FROM reply WHERE message_id = m.id)
+					(SELECT COUNT(*) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 					FROM message m 
 					LEFT JOIN channel c ON c.id = m.channel_id 
 					LEFT JOIN tg_user u ON u.id = m.user_id
+					WHERE c.hidden IS NOT TRUE AND m.hidden = FALSE
 					ORDER BY m.id DESC NULLS LAST LIMIT 10 OFFSET $1;`,
 				).WithArgs(10).WillReturnError(fmt.Errorf("some sql error"))
 			},
@@ -403,7 +429,7 @@ func Test_GetFullMessagesByPage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetFullMessagesByPage(tt.input)
+			got, err := r.GetFullMessagesByPage(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -429,7 +455,7 @@ func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -452,14 +478,14 @@ func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 					AddRow(2, "test2", "test2.com", "test2.jpg", 1, "test", "test2.jpg", 2, "test2", "test2.jpg", 2)
 
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url, 
 		 			u.id AS user_id, u.fullname, u.image_url AS user_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-	 	 			WHERE m.channel_id = $1 
+	 	 			WHERE m.channel_id = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
 		 			ORDER BY count DESC NULLS LAST LIMIT 10 OFFSET $2;`,
 				).WithArgs(1, 10).WillReturnRows(rows)
 			},
@@ -489,14 +515,14 @@ func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 				})
 
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url, 
 		 			u.id AS user_id, u.fullname, u.image_url AS user_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-	 	 			WHERE m.channel_id = $1 
+	 	 			WHERE m.channel_id = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
 		 			ORDER BY count DESC NULLS LAST LIMIT 10 OFFSET $2;`,
 				).WithArgs(1, 10).WillReturnRows(rows)
 			},
@@ -508,14 +534,14 @@ func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 			name: "GetFullMessagesByChannelIDAndPage failed with some sql error",
 			mock: func() {
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url, 
 		 			u.id AS user_id, u.fullname, u.image_url AS user_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-	 	 			WHERE m.channel_id = $1 
+	 	 			WHERE m.channel_id = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
 		 			ORDER BY count DESC NULLS LAST LIMIT 10 OFFSET $2;`,
 				).WithArgs(1, 10).WillReturnError(fmt.Errorf("some sql error"))
 			},
@@ -528,7 +554,7 @@ func Test_GetFullMessagesByChannelIDAndPage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetFullMessagesByChannelIDAndPage(tt.channelID, tt.page)
+			got, err := r.GetFullMessagesByChannelIDAndPage(context.Background(), tt.channelID, tt.page)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -556,7 +582,7 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -577,13 +603,13 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 					AddRow(2, "test2", "test2.com", "test2.jpg", 2, "test2", "test2", "test2.jpg", 2)
 
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.Title AS channel_title, c.image_url AS channel_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-		 			WHERE m.user_id= $1 
+		 			WHERE m.user_id= $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
 					ORDER BY count DESC NULLS LAST;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
@@ -611,13 +637,13 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 				})
 
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.Title AS channel_title, c.image_url AS channel_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-		 			WHERE m.user_id= $1 
+		 			WHERE m.user_id= $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
 					ORDER BY count DESC NULLS LAST;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
@@ -628,13 +654,13 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 			name: "GetFullMessagesByUserID failed with some sql error",
 			mock: func() {
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.Title AS channel_title, c.image_url AS channel_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-		 			WHERE m.user_id= $1 
+		 			WHERE m.user_id= $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
 					ORDER BY count DESC NULLS LAST;`,
 				).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
 			},
@@ -646,7 +672,7 @@ func Test_GetFullMessagesByUserID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetFullMessagesByUserID(tt.input)
+			got, err := r.GetFullMessagesByUserID(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -672,7 +698,7 @@ func Test_GetFullMessageByID(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewMessageRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -687,20 +713,20 @@ func Test_GetFullMessageByID(t *testing.T) {
 				rows := sqlmock.NewRows([]string{
 					"id", "title", "message_url", "image_url",
 					"channel_id", "channel_name", "channel_title", "channel_image_url",
-					"user_id", "fullname", "user_image_url",
+					"user_id", "fullname", "user_image_url", "hidden",
 					"count",
 				}).
-					AddRow(1, "test1", "test.com", "test.jpg", 1, "test", "test1", "test1.jpg", 1, "test1 test", "test1.jpg", 2)
+					AddRow(1, "test1", "test.com", "test.jpg", 1, "test", "test1", "test1.jpg", 1, "test1 test", "test1.jpg", true, 2)
 
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.title as channel_title, c.image_url as channel_image_url, 
-		 			u.id as user_id, u.fullname, u.image_url as user_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			u.id as user_id, u.fullname, u.image_url as user_image_url, m.hidden,
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-		 			WHERE m.id = $1;`,
+		 			WHERE m.id = $1 AND c.hidden IS NOT TRUE;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
 			input: 1,
@@ -708,7 +734,7 @@ func Test_GetFullMessageByID(t *testing.T) {
 				ID: 1, Title: "test1", MessageURL: "test.com", ImageURL: "test.jpg",
 				ChannelID: 1, ChannelName: "test", ChannelTitle: "test1", ChannelImageURL: "test1.jpg",
 				UserID: 1, FullName: "test1 test", UserImageURL: "test1.jpg",
-				RepliesCount: 2,
+				RepliesCount: 2, Hidden: true,
 			},
 		},
 		{
@@ -722,31 +748,54 @@ func Test_GetFullMessageByID(t *testing.T) {
 				})
 
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.title as channel_title, c.image_url as channel_image_url, 
-		 			u.id as user_id, u.fullname, u.image_url as user_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			u.id as user_id, u.fullname, u.image_url as user_image_url, m.hidden,
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-		 			WHERE m.id = $1;`,
+		 			WHERE m.id = $1 AND c.hidden IS NOT TRUE;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
 			input:         1,
 			expectedError: nil,
 		},
+		{
+			name: "GetFullMessageByID failed with message in hidden channel",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{
+					"id", "title", "message_url", "image_url",
+					"channel_id", "channel_name", "channel_title", "channel_image_url",
+					"user_id", "fullname", "user_image_url", "hidden",
+					"count",
+				})
+
+				mock.ExpectQuery(
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
+		 			c.id AS channel_id, c.name AS channel_name, c.title as channel_title, c.image_url as channel_image_url,
+		 			u.id as user_id, u.fullname, u.image_url as user_image_url, m.hidden,
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
+		 			FROM message m
+		 			LEFT JOIN channel c ON c.id = m.channel_id
+		 			LEFT JOIN tg_user u ON u.id = m.user_id
+		 			WHERE m.id = $1 AND c.hidden IS NOT TRUE;`,
+				).WithArgs(2).WillReturnRows(rows)
+			},
+			input: 2,
+		},
 		{
 			name: "GetFullMessageByID failed with some sql error",
 			mock: func() {
 				mock.ExpectQuery(
-					`SELECT m.id, m.title, m.message_url, m.image_url, 
+					`SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
 		 			c.id AS channel_id, c.name AS channel_name, c.title as channel_title, c.image_url as channel_image_url, 
-		 			u.id as user_id, u.fullname, u.image_url as user_image_url, 
-		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id)
+		 			u.id as user_id, u.fullname, u.image_url as user_image_url, m.hidden,
+		 			(SELECT COUNT(id) FROM reply WHERE message_id = m.id AND hidden = FALSE)
 		 			FROM message m 
 		 			LEFT JOIN channel c ON c.id = m.channel_id 
 		 			LEFT JOIN tg_user u ON u.id = m.user_id
-		 			WHERE m.id = $1;`,
+		 			WHERE m.id = $1 AND c.hidden IS NOT TRUE;`,
 				).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
 			},
 			input:         1,
@@ -758,7 +807,344 @@ func Test_GetFullMessageByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetFullMessageByID(tt.input)
+			got, err := r.GetFullMessageByID(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UpdateMessageHidden(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE message SET hidden = $1 WHERE id = $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "UpdateMessageHidden successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "UpdateMessageHidden failed with not found message",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "UpdateMessageHidden failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateMessageHidden(context.Background(), 1, true)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetMessagesCountByKind(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
+
+	query := `SELECT COUNT(*) FROM message m LEFT JOIN channel c ON c.id = m.channel_id
+		WHERE m.kind = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          int
+		expectedError error
+	}{
+		{
+			name: "GetMessagesCountByKind successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(2)
+
+				mock.ExpectQuery(query).WithArgs("question").WillReturnRows(rows)
+			},
+			want: 2,
+		},
+		{
+			name: "GetMessagesCountByKind failed with not found messages count",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("question").WillReturnRows(sqlmock.NewRows([]string{"count"}))
+			},
+		},
+		{
+			name: "GetMessagesCountByKind failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("question").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetMessagesCountByKind(context.Background(), model.MessageKindQuestion)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetFullMessagesByKindAndPage(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
+
+	columns := []string{
+		"id", "title", "message_url", "image_url", "kind", "channel_id", "channel_name",
+		"channel_image_url", "user_id", "fullname", "user_image_url", "count",
+	}
+
+	query := `SELECT m.id, m.title, m.message_url, m.image_url, m.kind,
+		c.id AS channel_id, c.name AS channel_name, c.image_url AS channel_image_url,
+		u.id AS user_id, u.fullname, u.image_url AS user_image_url,
+		(SELECT COUNT(*) FROM reply WHERE message_id = m.id AND hidden = FALSE)
+		FROM message m
+		LEFT JOIN channel c ON c.id = m.channel_id
+		LEFT JOIN tg_user u ON u.id = m.user_id
+		WHERE m.kind = $1 AND c.hidden IS NOT TRUE AND m.hidden = FALSE
+		ORDER BY m.id DESC NULLS LAST LIMIT 10 OFFSET $2;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.FullMessage
+		expectedError error
+	}{
+		{
+			name: "GetFullMessagesByKindAndPage successful",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, "why?", "test1.com", "test1.jpg", "question", 1, "test1", "test1.jpg", 1, "test1", "test1.jpg", 1)
+
+				mock.ExpectQuery(query).WithArgs("question", 10).WillReturnRows(rows)
+			},
+			want: []model.FullMessage{
+				{
+					ID: 1, Title: "why?", MessageURL: "test1.com", ImageURL: "test1.jpg", Kind: model.MessageKindQuestion,
+					ChannelID: 1, ChannelName: "test1", ChannelImageURL: "test1.jpg",
+					UserID: 1, FullName: "test1", UserImageURL: "test1.jpg",
+					RepliesCount: 1,
+				},
+			},
+		},
+		{
+			name: "GetFullMessagesByKindAndPage failed with not found messages",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("question", 10).WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name: "GetFullMessagesByKindAndPage failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("question", 10).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetFullMessagesByKindAndPage(context.Background(), model.MessageKindQuestion, 10)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetMessagesAfterID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
+
+	columns := []string{"id", "channel_id", "user_id", "title", "message_url", "image_url", "hidden", "kind"}
+
+	query := "SELECT * FROM message WHERE id > $1 ORDER BY id LIMIT $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.DBMessage
+		expectedError error
+	}{
+		{
+			name: "GetMessagesAfterID successful",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).AddRow(2, 1, 1, "why?", "test.com", "", false, "other")
+
+				mock.ExpectQuery(query).WithArgs(1, 100).WillReturnRows(rows)
+			},
+			want: []model.DBMessage{
+				{ID: 2, ChannelID: 1, UserID: 1, Title: "why?", MessageURL: "test.com", Kind: model.MessageKindOther},
+			},
+		},
+		{
+			name: "GetMessagesAfterID failed with not found messages",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1, 100).WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name: "GetMessagesAfterID failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1, 100).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetMessagesAfterID(context.Background(), 1, 100)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UpdateMessageKind(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Errorf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewMessageRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE message SET kind = $1 WHERE id = $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "UpdateMessageKind successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("question", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "UpdateMessageKind failed with not found message",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("question", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "UpdateMessageKind failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("question", 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateMessageKind(context.Background(), 1, model.MessageKindQuestion)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -0,0 +1,142 @@
+package pg
+
+import (
+	"context"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+type ReportRepo struct {
+	db *DB
+}
+
+func NewReportRepo(db *DB) *ReportRepo {
+	return &ReportRepo{db: db}
+}
+
+// CreateReport saves the report, repeated report of the message by the same user is ignored while it's open.
+func (repo ReportRepo) CreateReport(ctx context.Context, report *model.Report) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
+		`INSERT INTO report(web_user_id, message_id, reason, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (web_user_id, message_id) WHERE resolved_at IS NULL DO NOTHING;`,
+		report.WebUserID, report.MessageID, report.Reason, report.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetOpenReportsByPage returns open reports with the reported messages, oldest first.
+func (repo ReportRepo) GetOpenReportsByPage(ctx context.Context, page int) ([]model.ReportedMessage, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var reports []model.ReportedMessage
+
+	err := repo.db.SelectContext(
+		ctx,
+		&reports,
+		`SELECT r.id, r.web_user_id, r.message_id, r.reason, r.created_at, r.resolved_at,
+		 m.title AS message_title, m.hidden AS message_hidden, c.name AS channel_name, w.email AS reporter_email
+		 FROM report r
+		 LEFT JOIN message m ON m.id = r.message_id
+		 LEFT JOIN channel c ON c.id = m.channel_id
+		 LEFT JOIN web_user w ON w.id = r.web_user_id
+		 WHERE r.resolved_at IS NULL
+		 ORDER BY r.created_at, r.id LIMIT 10 OFFSET $1;`,
+		page,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	return reports, nil
+}
+
+// ResolveReportsByMessageID resolves all open reports of the message and reports whether there were any.
+func (repo ReportRepo) ResolveReportsByMessageID(
+	ctx context.Context, messageID int, resolvedAt time.Time,
+) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(
+		ctx,
+		"UPDATE report SET resolved_at = $1 WHERE message_id = $2 AND resolved_at IS NULL;",
+		resolvedAt, messageID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+type ModerationActionRepo struct {
+	db *DB
+}
+
+func NewModerationActionRepo(db *DB) *ModerationActionRepo {
+	return &ModerationActionRepo{db: db}
+}
+
+func (repo ModerationActionRepo) CreateModerationAction(ctx context.Context, action *model.ModerationAction) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
+		`INSERT INTO moderation_action(moderator_id, target_type, target_id, action, created_at)
+		VALUES ($1, $2, $3, $4, $5);`,
+		action.ModeratorID, action.TargetType, action.TargetID, action.Action, action.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetModerationActions returns the latest actions of the audit trail with emails of the moderators, newest first.
+func (repo ModerationActionRepo) GetModerationActions(ctx context.Context, limit int) ([]model.ModerationAction, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var actions []model.ModerationAction
+
+	err := repo.db.SelectContext(
+		ctx,
+		&actions,
+		`SELECT a.id, a.moderator_id, a.target_type, a.target_id, a.action, a.created_at,
+		 COALESCE(w.email, '') AS moderator_email
+		 FROM moderation_action a
+		 LEFT JOIN web_user w ON w.id = a.moderator_id
+		 ORDER BY a.created_at DESC, a.id DESC LIMIT $1;`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(actions) == 0 {
+		return nil, nil
+	}
+
+	return actions, nil
+}
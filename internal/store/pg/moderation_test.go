@@ -0,0 +1,362 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+func Test_CreateReport(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewReportRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	input := &model.Report{WebUserID: 1, MessageID: 2, Reason: "spam", CreatedAt: createdAt}
+
+	query := `INSERT INTO report(web_user_id, message_id, reason, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (web_user_id, message_id) WHERE resolved_at IS NULL DO NOTHING;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "CreateReport successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, 2, "spam", createdAt).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "CreateReport failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, 2, "spam", createdAt).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateReport(context.Background(), input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetOpenReportsByPage(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewReportRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	columns := []string{
+		"id", "web_user_id", "message_id", "reason", "created_at", "resolved_at",
+		"message_title", "message_hidden", "channel_name", "reporter_email",
+	}
+
+	query := `SELECT r.id, r.web_user_id, r.message_id, r.reason, r.created_at, r.resolved_at,
+		m.title AS message_title, m.hidden AS message_hidden, c.name AS channel_name, w.email AS reporter_email
+		FROM report r
+		LEFT JOIN message m ON m.id = r.message_id
+		LEFT JOIN channel c ON c.id = m.channel_id
+		LEFT JOIN web_user w ON w.id = r.web_user_id
+		WHERE r.resolved_at IS NULL
+		ORDER BY r.created_at, r.id LIMIT 10 OFFSET $1;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.ReportedMessage
+		expectedError error
+	}{
+		{
+			name: "GetOpenReportsByPage successful",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 1, 2, "spam", createdAt, nil, "buy now", false, "test", "test@test.com")
+
+				mock.ExpectQuery(query).WithArgs(0).WillReturnRows(rows)
+			},
+			want: []model.ReportedMessage{
+				{
+					Report:       model.Report{ID: 1, WebUserID: 1, MessageID: 2, Reason: "spam", CreatedAt: createdAt},
+					MessageTitle: "buy now", ChannelName: "test", ReporterEmail: "test@test.com",
+				},
+			},
+		},
+		{
+			name: "GetOpenReportsByPage failed with not found reports",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(0).WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name: "GetOpenReportsByPage failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(0).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetOpenReportsByPage(context.Background(), 0)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_ResolveReportsByMessageID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewReportRepo(pg.NewDB(sqlxDB, 0))
+
+	resolvedAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := "UPDATE report SET resolved_at = $1 WHERE message_id = $2 AND resolved_at IS NULL;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "ResolveReportsByMessageID successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(resolvedAt, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			want: true,
+		},
+		{
+			name: "ResolveReportsByMessageID failed with not found reports",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(resolvedAt, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "ResolveReportsByMessageID failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(resolvedAt, 2).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.ResolveReportsByMessageID(context.Background(), 2, resolvedAt)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_CreateModerationAction(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewModerationActionRepo(pg.NewDB(sqlxDB, 0))
+
+	moderatorID := 1
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	input := &model.ModerationAction{
+		ModeratorID: &moderatorID, TargetType: model.ModerationTargetMessage, TargetID: 2,
+		Action: model.ModerationActionHide, CreatedAt: createdAt,
+	}
+
+	query := `INSERT INTO moderation_action(moderator_id, target_type, target_id, action, created_at)
+		VALUES ($1, $2, $3, $4, $5);`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "CreateModerationAction successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "message", 2, "hide", createdAt).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "CreateModerationAction failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "message", 2, "hide", createdAt).
+					WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateModerationAction(context.Background(), input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetModerationActions(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewModerationActionRepo(pg.NewDB(sqlxDB, 0))
+
+	moderatorID := 1
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	columns := []string{"id", "moderator_id", "target_type", "target_id", "action", "created_at", "moderator_email"}
+
+	query := `SELECT a.id, a.moderator_id, a.target_type, a.target_id, a.action, a.created_at,
+		COALESCE(w.email, '') AS moderator_email
+		FROM moderation_action a
+		LEFT JOIN web_user w ON w.id = a.moderator_id
+		ORDER BY a.created_at DESC, a.id DESC LIMIT $1;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.ModerationAction
+		expectedError error
+	}{
+		{
+			name: "GetModerationActions successful",
+			mock: func() {
+				rows := sqlmock.NewRows(columns).
+					AddRow(1, 1, "reply", 3, "hide", createdAt, "moderator@test.com").
+					AddRow(2, nil, "message", 2, "dismiss", createdAt, "")
+
+				mock.ExpectQuery(query).WithArgs(20).WillReturnRows(rows)
+			},
+			want: []model.ModerationAction{
+				{
+					ID: 1, ModeratorID: &moderatorID, TargetType: model.ModerationTargetReply, TargetID: 3,
+					Action: model.ModerationActionHide, CreatedAt: createdAt, ModeratorEmail: "moderator@test.com",
+				},
+				{
+					ID: 2, TargetType: model.ModerationTargetMessage, TargetID: 2,
+					Action: model.ModerationActionDismiss, CreatedAt: createdAt,
+				},
+			},
+		},
+		{
+			name: "GetModerationActions failed with not found actions",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(20).WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name: "GetModerationActions failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(20).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetModerationActions(context.Background(), 20)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
@@ -0,0 +1,122 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+type PasswordResetRepo struct {
+	db *DB
+}
+
+func NewPasswordResetRepo(db *DB) *PasswordResetRepo {
+	return &PasswordResetRepo{db: db}
+}
+
+func (repo PasswordResetRepo) CreatePasswordReset(ctx context.Context, reset *model.PasswordReset) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
+		"INSERT INTO password_reset(user_id, token_hash, expires_at) VALUES ($1, $2, $3);",
+		reset.UserID, reset.TokenHash, reset.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetPasswordReset returns password reset which is neither used nor expired.
+func (repo PasswordResetRepo) GetPasswordReset(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var reset model.PasswordReset
+
+	err := repo.db.GetContext(
+		ctx, &reset,
+		"SELECT * FROM password_reset WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW();",
+		tokenHash,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &reset, nil
+}
+
+// ResetPassword marks password reset as used, sets the new password, ends all sessions and revokes API tokens
+// of the user in one transaction and returns the reset, nil means that reset is already used or expired.
+// The reset is marked first, so the token can't be used twice by concurrent requests.
+func (repo PasswordResetRepo) ResetPassword(
+	ctx context.Context, tokenHash string, password string,
+) (*model.PasswordReset, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	var reset model.PasswordReset
+
+	err = tx.GetContext(
+		ctx, &reset,
+		`UPDATE password_reset SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() RETURNING *;`,
+		tokenHash,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE web_user SET password = $1 WHERE id = $2;", password, reset.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM session WHERE user_id = $1;", reset.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM api_token WHERE user_id = $1;", reset.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &reset, nil
+}
+
+func (repo PasswordResetRepo) DeletePasswordResetsByUserID(ctx context.Context, userID int) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM password_reset WHERE user_id = $1;", userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+var passwordResetColumns = []string{"id", "user_id", "token_hash", "created_at", "expires_at", "used_at"}
+
+func Test_CreatePasswordReset(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewPasswordResetRepo(pg.NewDB(sqlxDB, 0))
+
+	expiresAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         *model.PasswordReset
+		expectedError error
+	}{
+		{
+			name: "CreatePasswordReset successful",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO password_reset(user_id, token_hash, expires_at) VALUES ($1, $2, $3);").
+					WithArgs(1, "hash", expiresAt).WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			input: &model.PasswordReset{UserID: 1, TokenHash: "hash", ExpiresAt: expiresAt},
+		},
+		{
+			name: "CreatePasswordReset failed with some sql error",
+			mock: func() {
+				mock.ExpectExec("INSERT INTO password_reset(user_id, token_hash, expires_at) VALUES ($1, $2, $3);").
+					WithArgs(1, "hash", expiresAt).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         &model.PasswordReset{UserID: 1, TokenHash: "hash", ExpiresAt: expiresAt},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreatePasswordReset(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetPasswordReset(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewPasswordResetRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(time.Hour)
+
+	query := "SELECT * FROM password_reset WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW();"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.PasswordReset
+		expectedError error
+	}{
+		{
+			name: "GetPasswordReset successful",
+			mock: func() {
+				rows := sqlmock.NewRows(passwordResetColumns).AddRow(1, 1, "hash", createdAt, expiresAt, nil)
+
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+			},
+			want: &model.PasswordReset{ID: 1, UserID: 1, TokenHash: "hash", CreatedAt: createdAt, ExpiresAt: expiresAt},
+		},
+		{
+			name: "GetPasswordReset failed with not found reset",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(sqlmock.NewRows(passwordResetColumns))
+			},
+		},
+		{
+			name: "GetPasswordReset failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetPasswordReset(context.Background(), "hash")
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_ResetPassword(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewPasswordResetRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(time.Hour)
+	usedAt := createdAt.Add(time.Minute)
+
+	query := `UPDATE password_reset SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW() RETURNING *;`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.PasswordReset
+		expectedError error
+	}{
+		{
+			name: "ResetPassword successful",
+			mock: func() {
+				rows := sqlmock.NewRows(passwordResetColumns).AddRow(1, 1, "hash", createdAt, expiresAt, usedAt)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+				mock.ExpectExec("UPDATE web_user SET password = $1 WHERE id = $2;").
+					WithArgs("password", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM session WHERE user_id = $1;").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("DELETE FROM api_token WHERE user_id = $1;").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: &model.PasswordReset{
+				ID: 1, UserID: 1, TokenHash: "hash", CreatedAt: createdAt, ExpiresAt: expiresAt, UsedAt: &usedAt,
+			},
+		},
+		{
+			name: "ResetPassword failed with used or expired reset",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(sqlmock.NewRows(passwordResetColumns))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "ResetPassword failed with some sql error and kept the reset unused",
+			mock: func() {
+				rows := sqlmock.NewRows(passwordResetColumns).AddRow(1, 1, "hash", createdAt, expiresAt, usedAt)
+
+				mock.ExpectBegin()
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+				mock.ExpectExec("UPDATE web_user SET password = $1 WHERE id = $2;").
+					WithArgs("password", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("DELETE FROM session WHERE user_id = $1;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+				mock.ExpectRollback()
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.ResetPassword(context.Background(), "hash", "password")
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeletePasswordResetsByUserID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewPasswordResetRepo(pg.NewDB(sqlxDB, 0))
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "DeletePasswordResetsByUserID successful",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM password_reset WHERE user_id = $1;").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "DeletePasswordResetsByUserID failed with some sql error",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM password_reset WHERE user_id = $1;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.DeletePasswordResetsByUserID(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
@@ -1,36 +1,128 @@
 package pg
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/VladPetriv/scanner_backend/pkg/config"
 	"github.com/jmoiron/sqlx"
 )
 
+// DB is a connection pool shared by all repositories.
+// The pool can be replaced on reconnection, so repositories always use the live one.
 type DB struct {
-	*sqlx.DB
+	mu   sync.RWMutex
+	pool *sqlx.DB
+
+	// queryTimeout limits duration of every query, zero value means no limit.
+	queryTimeout time.Duration
+}
+
+func NewDB(pool *sqlx.DB, queryTimeout time.Duration) *DB {
+	return &DB{pool: pool, queryTimeout: queryTimeout}
 }
 
 func Init(cfg *config.Config) (*DB, error) {
-	var connectionString string
-
-	if cfg.DatabaseURL == "" {
-		connectionString = fmt.Sprintf(
-			"postgresql://%s/%s?user=%s&password=%s&sslmode=disable",
-			cfg.PgHost, cfg.PgDB, cfg.PgUser, cfg.PgDB,
-		)
-	} else {
-		connectionString = cfg.DatabaseURL
+	pool, err := connect(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := sqlx.Connect("postgres", connectionString)
+	return NewDB(pool, cfg.DBQueryTimeout), nil
+}
+
+func connect(cfg *config.Config) (*sqlx.DB, error) {
+	db, err := sqlx.Connect("postgres", cfg.PostgresURL())
 	if err != nil {
 		return nil, fmt.Errorf("error while create connection to db: %w", err)
 	}
 	_, err = db.Exec("SELECT 1;")
 	if err != nil {
+		db.Close() //nolint:errcheck // connection error is more relevant
+
 		return nil, fmt.Errorf("error while send request to db: %w", err)
 	}
 
-	return &DB{db}, nil
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
+	return db, nil
+}
+
+// Reconnect opens a new pool and replaces the current one, the previous pool is closed.
+func (db *DB) Reconnect(cfg *config.Config) error {
+	pool, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+
+	db.mu.Lock()
+	previous := db.pool
+	db.pool = pool
+	db.mu.Unlock()
+
+	if previous != nil {
+		// Closing waits for the queries which are still running on the previous pool.
+		previous.Close() //nolint:errcheck // previous pool is broken anyway
+	}
+
+	return nil
+}
+
+func (db *DB) conn() *sqlx.DB {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return db.pool
+}
+
+// withTimeout returns context limited by the configured query timeout.
+func (db *DB) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if db.queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, db.queryTimeout)
+}
+
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.conn().ExecContext(ctx, query, args...)
+}
+
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.conn().QueryContext(ctx, query, args...)
+}
+
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return db.conn().QueryRowContext(ctx, query, args...)
+}
+
+func (db *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return db.conn().GetContext(ctx, dest, query, args...)
+}
+
+func (db *DB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return db.conn().SelectContext(ctx, dest, query, args...)
+}
+
+func (db *DB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error) {
+	return db.conn().BeginTxx(ctx, opts)
+}
+
+func (db *DB) PingContext(ctx context.Context) error {
+	return db.conn().PingContext(ctx)
+}
+
+// Stats returns statistics of the live pool.
+func (db *DB) Stats() sql.DBStats {
+	return db.conn().Stats()
+}
+
+func (db *DB) Close() error {
+	return db.conn().Close()
 }
@@ -0,0 +1,38 @@
+package pg_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+func Test_QueryTimeout(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewChannelRepo(pg.NewDB(sqlxDB, 10*time.Millisecond))
+
+	mock.ExpectQuery("SELECT * FROM channel WHERE name = $1;").
+		WithArgs("test").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "test"))
+
+	got, err := r.GetChannelByName(context.Background(), "test")
+	assert.Nil(t, got)
+	assert.Error(t, err)
+
+	t.Cleanup(func() {
+		defer db.Close()
+	})
+}
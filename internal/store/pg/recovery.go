@@ -0,0 +1,74 @@
+package pg
+
+import (
+	"context"
+)
+
+type RecoveryCodeRepo struct {
+	db *DB
+}
+
+func NewRecoveryCodeRepo(db *DB) *RecoveryCodeRepo {
+	return &RecoveryCodeRepo{db: db}
+}
+
+// ReplaceRecoveryCodes deletes previous recovery codes of the user and stores the new ones.
+func (repo RecoveryCodeRepo) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM recovery_code WHERE user_id = $1;", userID)
+	if err != nil {
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err = tx.ExecContext(ctx, "INSERT INTO recovery_code(user_id, code_hash) VALUES ($1, $2);", userID, codeHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks recovery code as used, false means that code is not found or already used.
+func (repo RecoveryCodeRepo) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(
+		ctx,
+		"UPDATE recovery_code SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;",
+		userID, codeHash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo RecoveryCodeRepo) DeleteRecoveryCodes(ctx context.Context, userID int) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM recovery_code WHERE user_id = $1;", userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+func Test_ReplaceRecoveryCodes(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewRecoveryCodeRepo(pg.NewDB(sqlxDB, 0))
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "ReplaceRecoveryCodes successful",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM recovery_code WHERE user_id = $1;").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO recovery_code(user_id, code_hash) VALUES ($1, $2);").
+					WithArgs(1, "hash1").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO recovery_code(user_id, code_hash) VALUES ($1, $2);").
+					WithArgs(1, "hash2").WillReturnResult(sqlmock.NewResult(2, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ReplaceRecoveryCodes failed with some sql error",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM recovery_code WHERE user_id = $1;").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("INSERT INTO recovery_code(user_id, code_hash) VALUES ($1, $2);").
+					WithArgs(1, "hash1").WillReturnError(fmt.Errorf("some sql error"))
+				mock.ExpectRollback()
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.ReplaceRecoveryCodes(context.Background(), 1, []string{"hash1", "hash2"})
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UseRecoveryCode(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewRecoveryCodeRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE recovery_code SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "UseRecoveryCode successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "UseRecoveryCode failed with used code",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "UseRecoveryCode failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "hash").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UseRecoveryCode(context.Background(), 1, "hash")
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteRecoveryCodes(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewRecoveryCodeRepo(pg.NewDB(sqlxDB, 0))
+
+	mock.ExpectExec("DELETE FROM recovery_code WHERE user_id = $1;").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 10))
+
+	err = r.DeleteRecoveryCodes(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
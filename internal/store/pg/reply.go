@@ -1,6 +1,10 @@
 package pg
 
 import (
+	"context"
+
+	"github.com/lib/pq"
+
 	"github.com/VladPetriv/scanner_backend/internal/model"
 )
 
@@ -12,8 +16,11 @@ func NewReplyRepo(db *DB) *ReplyRepo {
 	return &ReplyRepo{db: db}
 }
 
-func (repo ReplyRepo) CreateReply(reply *model.DBReply) error {
-	_, err := repo.db.Exec(`
+func (repo ReplyRepo) CreateReply(ctx context.Context, reply *model.DBReply) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, `
 		INSERT INTO reply(user_id, message_id, title, image_url) VALUES ($1, $2, $3, $4);`,
 		reply.UserID, reply.MessageID, reply.Title, reply.ImageURL,
 	)
@@ -24,16 +31,52 @@ func (repo ReplyRepo) CreateReply(reply *model.DBReply) error {
 	return nil
 }
 
-func (repo ReplyRepo) GetFullRepliesByMessageID(messageID int) ([]model.FullReply, error) {
+func (repo ReplyRepo) CreateReplies(ctx context.Context, replies []model.DBReply) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	if len(replies) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(replies))
+	messageIDs := make([]int64, 0, len(replies))
+	titles := make([]string, 0, len(replies))
+	imageURLs := make([]string, 0, len(replies))
+
+	for _, reply := range replies {
+		userIDs = append(userIDs, int64(reply.UserID))
+		messageIDs = append(messageIDs, int64(reply.MessageID))
+		titles = append(titles, reply.Title)
+		imageURLs = append(imageURLs, reply.ImageURL)
+	}
+
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO reply(user_id, message_id, title, image_url) 
+		SELECT * FROM UNNEST($1::INT[], $2::INT[], $3::TEXT[], $4::TEXT[]);`,
+		pq.Array(userIDs), pq.Array(messageIDs), pq.Array(titles), pq.Array(imageURLs),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo ReplyRepo) GetFullRepliesByMessageID(ctx context.Context, messageID int) ([]model.FullReply, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var replies []model.FullReply
 
-	err := repo.db.Select(
+	err := repo.db.SelectContext(
+		ctx,
 		&replies,
 		`SELECT r.id, r.title, r.image_url, 
 		 u.id as user_id, u.fullname, u.image_url as user_image_url
 		 FROM reply r 
 		 LEFT JOIN tg_user u ON r.user_id = u.id 
-		 WHERE r.message_id = $1 
+		 WHERE r.message_id = $1 AND r.hidden = FALSE
 		 ORDER BY r.id DESC NULLS LAST;`,
 		messageID,
 	)
@@ -47,3 +90,21 @@ func (repo ReplyRepo) GetFullRepliesByMessageID(messageID int) ([]model.FullRepl
 
 	return replies, nil
 }
+
+// UpdateReplyHidden hides or shows the reply, hidden replies are excluded from the message page.
+func (repo ReplyRepo) UpdateReplyHidden(ctx context.Context, id int, hidden bool) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, "UPDATE reply SET hidden = $1 WHERE id = $2;", hidden, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -1,11 +1,13 @@
 package pg_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
@@ -23,7 +25,7 @@ func Test_CreateReply(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewReplyRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewReplyRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -58,7 +60,7 @@ func Test_CreateReply(t *testing.T) {
 
 			tt.mock()
 
-			err = r.CreateReply(tt.input)
+			err = r.CreateReply(context.Background(), tt.input)
 			if tt.expecterError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expecterError, err)
@@ -74,6 +76,81 @@ func Test_CreateReply(t *testing.T) {
 	})
 }
 
+func Test_CreateReplies(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewReplyRepo(pg.NewDB(sqlxDB, 0))
+
+	query := `
+		INSERT INTO reply(user_id, message_id, title, image_url) 
+		SELECT * FROM UNNEST($1::INT[], $2::INT[], $3::TEXT[], $4::TEXT[]);`
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         []model.DBReply
+		expectedError error
+	}{
+		{
+			name: "CreateReplies successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(
+					pq.Array([]int64{1, 2}),
+					pq.Array([]int64{1, 1}),
+					pq.Array([]string{"test1", "test2"}),
+					pq.Array([]string{"test1.jpg", "test2.jpg"}),
+				).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			input: []model.DBReply{
+				{UserID: 1, MessageID: 1, Title: "test1", ImageURL: "test1.jpg"},
+				{UserID: 2, MessageID: 1, Title: "test2", ImageURL: "test2.jpg"},
+			},
+		},
+		{
+			name:  "CreateReplies successful with empty input",
+			mock:  func() {},
+			input: nil,
+		},
+		{
+			name: "CreateReplies failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(
+					pq.Array([]int64{1}),
+					pq.Array([]int64{1}),
+					pq.Array([]string{"test1"}),
+					pq.Array([]string{"test1.jpg"}),
+				).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         []model.DBReply{{UserID: 1, MessageID: 1, Title: "test1", ImageURL: "test1.jpg"}},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateReplies(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
 func Test_GetFullRepliesByMessageID(t *testing.T) {
 	t.Parallel()
 
@@ -84,7 +161,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewReplyRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewReplyRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -105,7 +182,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 					u.id as user_id, u.fullname, u.image_url as user_image_url
 					FROM reply r 
 					LEFT JOIN tg_user u ON r.user_id = u.id 
-					WHERE r.message_id = $1 
+					WHERE r.message_id = $1 AND r.hidden = FALSE
 					ORDER BY r.id DESC NULLS LAST;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
@@ -125,7 +202,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		 			u.id as user_id, u.fullname, u.image_url as user_image_url
 		 			FROM reply r 
 		 			LEFT JOIN tg_user u ON r.user_id = u.id 
-		 			WHERE r.message_id = $1 
+		 			WHERE r.message_id = $1 AND r.hidden = FALSE
 		 			ORDER BY r.id DESC NULLS LAST;`,
 				).WithArgs(1).WillReturnRows(rows)
 			},
@@ -140,7 +217,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 					u.id as user_id, u.fullname, u.image_url as user_image_url
 					FROM reply r 
 					LEFT JOIN tg_user u ON r.user_id = u.id 
-					WHERE r.message_id = $1 
+					WHERE r.message_id = $1 AND r.hidden = FALSE
 					ORDER BY r.id DESC NULLS LAST;`,
 				).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
 			},
@@ -156,7 +233,7 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 
 			tt.mock()
 
-			got, err := r.GetFullRepliesByMessageID(tt.input)
+			got, err := r.GetFullRepliesByMessageID(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError, err)
@@ -171,3 +248,64 @@ func Test_GetFullRepliesByMessageID(t *testing.T) {
 		db.Close()
 	})
 }
+
+func Test_UpdateReplyHidden(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewReplyRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE reply SET hidden = $1 WHERE id = $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "UpdateReplyHidden successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "UpdateReplyHidden failed with not found reply",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "UpdateReplyHidden failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(true, 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateReplyHidden(context.Background(), 1, true)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
@@ -1,6 +1,7 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 
@@ -15,8 +16,12 @@ func NewSavedRepo(db *DB) *SavedRepo {
 	return &SavedRepo{db: db}
 }
 
-func (repo SavedRepo) CreateSavedMessage(saved *model.Saved) error {
-	_, err := repo.db.Exec(
+func (repo SavedRepo) CreateSavedMessage(ctx context.Context, saved *model.Saved) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
 		"INSERT INTO saved(user_id, message_id) VALUES ($1, $2);",
 		saved.WebUserID, saved.MessageID,
 	)
@@ -27,10 +32,13 @@ func (repo SavedRepo) CreateSavedMessage(saved *model.Saved) error {
 	return nil
 }
 
-func (repo SavedRepo) GetSavedMessages(userID int) ([]model.Saved, error) {
+func (repo SavedRepo) GetSavedMessages(ctx context.Context, userID int) ([]model.Saved, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var savedMessages []model.Saved
 
-	err := repo.db.Select(&savedMessages, "SELECT * FROM saved WHERE user_id = $1;", userID)
+	err := repo.db.SelectContext(ctx, &savedMessages, "SELECT * FROM saved WHERE user_id = $1;", userID)
 	if err != nil {
 		return nil, err
 	}
@@ -42,10 +50,13 @@ func (repo SavedRepo) GetSavedMessages(userID int) ([]model.Saved, error) {
 	return savedMessages, nil
 }
 
-func (repo SavedRepo) GetSavedMessageByID(id int) (*model.Saved, error) {
+func (repo SavedRepo) GetSavedMessageByID(ctx context.Context, id int) (*model.Saved, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var savedMessage model.Saved
 
-	err := repo.db.Get(&savedMessage, "SELECT * FROM saved WHERE message_id = $1;", id)
+	err := repo.db.GetContext(ctx, &savedMessage, "SELECT * FROM saved WHERE message_id = $1;", id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -57,8 +68,11 @@ func (repo SavedRepo) GetSavedMessageByID(id int) (*model.Saved, error) {
 	return &savedMessage, nil
 }
 
-func (repo SavedRepo) DeleteSavedMessage(id int) error {
-	_, err := repo.db.Exec("DELETE FROM saved WHERE id = $1;", id)
+func (repo SavedRepo) DeleteSavedMessage(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM saved WHERE id = $1;", id)
 	if err != nil {
 		return err
 	}
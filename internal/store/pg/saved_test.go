@@ -1,6 +1,7 @@
 package pg_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -21,7 +22,7 @@ func Test_CreateSavedMessage(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewSavedRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -51,7 +52,7 @@ func Test_CreateSavedMessage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := r.CreateSavedMessage(tt.input)
+			err := r.CreateSavedMessage(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -74,7 +75,7 @@ func Test_GetSavedMessages(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewSavedRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -124,7 +125,7 @@ func Test_GetSavedMessages(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetSavedMessages(tt.input)
+			got, err := r.GetSavedMessages(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -150,7 +151,7 @@ func Test_GetSavedMessageByID(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewSavedRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -196,7 +197,7 @@ func Test_GetSavedMessageByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetSavedMessageByID(tt.input)
+			got, err := r.GetSavedMessageByID(context.Background(), tt.input)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -223,7 +224,7 @@ func Test_DeleteSavedMessage(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewSavedRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewSavedRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -253,7 +254,7 @@ func Test_DeleteSavedMessage(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := r.DeleteSavedMessage(tt.input)
+			err := r.DeleteSavedMessage(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -0,0 +1,111 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+type SessionRepo struct {
+	db *DB
+}
+
+func NewSessionRepo(db *DB) *SessionRepo {
+	return &SessionRepo{db: db}
+}
+
+func (repo SessionRepo) CreateSession(ctx context.Context, session *model.Session) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO session(id, user_id, user_agent, ip_address, expires_at) VALUES ($1, $2, $3, $4, $5);`,
+		session.ID, session.UserID, session.UserAgent, session.IPAddress, session.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetSessionByID returns session which is not expired yet.
+func (repo SessionRepo) GetSessionByID(ctx context.Context, id string) (*model.Session, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var session model.Session
+
+	err := repo.db.GetContext(ctx, &session, "SELECT * FROM session WHERE id = $1 AND expires_at > NOW();", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// GetSessionsByUserID returns active sessions of the user, the newest first.
+func (repo SessionRepo) GetSessionsByUserID(ctx context.Context, userID int) ([]model.Session, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var sessions []model.Session
+
+	err := repo.db.SelectContext(
+		ctx,
+		&sessions,
+		"SELECT * FROM session WHERE user_id = $1 AND expires_at > NOW() ORDER BY created_at DESC;",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	return sessions, nil
+}
+
+func (repo SessionRepo) DeleteSession(ctx context.Context, id string) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM session WHERE id = $1;", id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo SessionRepo) DeleteSessionsByUserID(ctx context.Context, userID int) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM session WHERE user_id = $1;", userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo SessionRepo) DeleteExpiredSessions(ctx context.Context) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM session WHERE expires_at <= NOW();")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,360 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+func Test_CreateSession(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(pg.NewDB(sqlxDB, 0))
+
+	expiresAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         *model.Session
+		expectedError error
+	}{
+		{
+			name: "CreateSession successful",
+			mock: func() {
+				mock.ExpectExec(`
+					INSERT INTO session(id, user_id, user_agent, ip_address, expires_at) VALUES ($1, $2, $3, $4, $5);`,
+				).WithArgs("hash", 1, "agent", "127.0.0.1", expiresAt).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: &model.Session{ID: "hash", UserID: 1, UserAgent: "agent", IPAddress: "127.0.0.1", ExpiresAt: expiresAt},
+		},
+		{
+			name: "CreateSession failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(`
+					INSERT INTO session(id, user_id, user_agent, ip_address, expires_at) VALUES ($1, $2, $3, $4, $5);`,
+				).WithArgs("hash", 1, "agent", "127.0.0.1", expiresAt).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         &model.Session{ID: "hash", UserID: 1, UserAgent: "agent", IPAddress: "127.0.0.1", ExpiresAt: expiresAt},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateSession(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetSessionByID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         string
+		want          *model.Session
+		expectedError error
+	}{
+		{
+			name: "GetSessionByID successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "user_agent", "ip_address", "created_at", "expires_at"}).
+					AddRow("hash", 1, "agent", "127.0.0.1", createdAt, expiresAt)
+
+				mock.ExpectQuery("SELECT * FROM session WHERE id = $1 AND expires_at > NOW();").
+					WithArgs("hash").WillReturnRows(rows)
+			},
+			input: "hash",
+			want: &model.Session{
+				ID: "hash", UserID: 1, UserAgent: "agent", IPAddress: "127.0.0.1", CreatedAt: createdAt, ExpiresAt: expiresAt,
+			},
+		},
+		{
+			name: "GetSessionByID failed with not found session",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "user_agent", "ip_address", "created_at", "expires_at"})
+
+				mock.ExpectQuery("SELECT * FROM session WHERE id = $1 AND expires_at > NOW();").
+					WithArgs("hash").WillReturnRows(rows)
+			},
+			input: "hash",
+		},
+		{
+			name: "GetSessionByID failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM session WHERE id = $1 AND expires_at > NOW();").
+					WithArgs("hash").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         "hash",
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetSessionByID(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetSessionsByUserID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         int
+		want          []model.Session
+		expectedError error
+	}{
+		{
+			name: "GetSessionsByUserID successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "user_agent", "ip_address", "created_at", "expires_at"}).
+					AddRow("hash1", 1, "agent", "127.0.0.1", createdAt, expiresAt).
+					AddRow("hash2", 1, "agent", "127.0.0.2", createdAt, expiresAt)
+
+				mock.ExpectQuery("SELECT * FROM session WHERE user_id = $1 AND expires_at > NOW() ORDER BY created_at DESC;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: 1,
+			want: []model.Session{
+				{ID: "hash1", UserID: 1, UserAgent: "agent", IPAddress: "127.0.0.1", CreatedAt: createdAt, ExpiresAt: expiresAt},
+				{ID: "hash2", UserID: 1, UserAgent: "agent", IPAddress: "127.0.0.2", CreatedAt: createdAt, ExpiresAt: expiresAt},
+			},
+		},
+		{
+			name: "GetSessionsByUserID failed with not found sessions",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "user_agent", "ip_address", "created_at", "expires_at"})
+
+				mock.ExpectQuery("SELECT * FROM session WHERE user_id = $1 AND expires_at > NOW() ORDER BY created_at DESC;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: 1,
+		},
+		{
+			name: "GetSessionsByUserID failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM session WHERE user_id = $1 AND expires_at > NOW() ORDER BY created_at DESC;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         1,
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetSessionsByUserID(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteSession(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(pg.NewDB(sqlxDB, 0))
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         string
+		expectedError error
+	}{
+		{
+			name: "DeleteSession successful",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM session WHERE id = $1;").
+					WithArgs("hash").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: "hash",
+		},
+		{
+			name: "DeleteSession failed with some sql error",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM session WHERE id = $1;").
+					WithArgs("hash").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         "hash",
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.DeleteSession(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteSessionsByUserID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(pg.NewDB(sqlxDB, 0))
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         int
+		expectedError error
+	}{
+		{
+			name: "DeleteSessionsByUserID successful",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM session WHERE user_id = $1;").
+					WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+			input: 1,
+		},
+		{
+			name: "DeleteSessionsByUserID failed with some sql error",
+			mock: func() {
+				mock.ExpectExec("DELETE FROM session WHERE user_id = $1;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         1,
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.DeleteSessionsByUserID(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteExpiredSessions(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewSessionRepo(pg.NewDB(sqlxDB, 0))
+
+	mock.ExpectExec("DELETE FROM session WHERE expires_at <= NOW();").WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err = r.DeleteExpiredSessions(context.Background())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
@@ -0,0 +1,120 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
+
+type APITokenRepo struct {
+	db *DB
+}
+
+func NewAPITokenRepo(db *DB) *APITokenRepo {
+	return &APITokenRepo{db: db}
+}
+
+func (repo APITokenRepo) CreateAPIToken(ctx context.Context, apiToken *model.APIToken) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
+		"INSERT INTO api_token(user_id, name, token_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5);",
+		apiToken.UserID, apiToken.Name, apiToken.TokenHash, apiToken.Scopes, apiToken.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAPITokenByHash returns token which is not expired.
+func (repo APITokenRepo) GetAPITokenByHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var apiToken model.APIToken
+
+	err := repo.db.GetContext(
+		ctx, &apiToken,
+		"SELECT * FROM api_token WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > NOW());",
+		tokenHash,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &apiToken, nil
+}
+
+// GetAPITokensByUserID returns all tokens of the user including expired ones, so the user can see and revoke them.
+func (repo APITokenRepo) GetAPITokensByUserID(ctx context.Context, userID int) ([]model.APIToken, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var apiTokens []model.APIToken
+
+	err := repo.db.SelectContext(
+		ctx, &apiTokens, "SELECT * FROM api_token WHERE user_id = $1 ORDER BY created_at DESC;", userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(apiTokens) == 0 {
+		return nil, nil
+	}
+
+	return apiTokens, nil
+}
+
+func (repo APITokenRepo) UpdateAPITokenLastUsed(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "UPDATE api_token SET last_used_at = NOW() WHERE id = $1;", id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteUserAPIToken deletes token only when it belongs to the user, false means that such token is not found.
+func (repo APITokenRepo) DeleteUserAPIToken(ctx context.Context, userID int, id int) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(ctx, "DELETE FROM api_token WHERE id = $1 AND user_id = $2;", id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteAPITokensByUserID revokes all tokens of the user.
+func (repo APITokenRepo) DeleteAPITokensByUserID(ctx context.Context, userID int) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "DELETE FROM api_token WHERE user_id = $1;", userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,382 @@
+package pg_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+	"github.com/VladPetriv/scanner_backend/internal/store/mocks"
+	"github.com/VladPetriv/scanner_backend/internal/store/pg"
+)
+
+var apiTokenColumns = []string{
+	"id", "user_id", "name", "token_hash", "scopes", "created_at", "expires_at", "last_used_at",
+}
+
+func Test_CreateAPIToken(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPITokenRepo(pg.NewDB(sqlxDB, 0))
+
+	expiresAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := "INSERT INTO api_token(user_id, name, token_hash, scopes, expires_at) VALUES ($1, $2, $3, $4, $5);"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         *model.APIToken
+		expectedError error
+	}{
+		{
+			name: "CreateAPIToken successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "ci", "hash", "read", &expiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			input: &model.APIToken{UserID: 1, Name: "ci", TokenHash: "hash", Scopes: "read", ExpiresAt: &expiresAt},
+		},
+		{
+			name: "CreateAPIToken failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "ci", "hash", "read", &expiresAt).
+					WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         &model.APIToken{UserID: 1, Name: "ci", TokenHash: "hash", Scopes: "read", ExpiresAt: &expiresAt},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.CreateAPIToken(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetAPITokenByHash(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPITokenRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	query := "SELECT * FROM api_token WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > NOW());"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          *model.APIToken
+		expectedError error
+	}{
+		{
+			name: "GetAPITokenByHash successful",
+			mock: func() {
+				rows := sqlmock.NewRows(apiTokenColumns).AddRow(1, 1, "ci", "hash", "read write", createdAt, nil, nil)
+
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+			},
+			want: &model.APIToken{
+				ID: 1, UserID: 1, Name: "ci", TokenHash: "hash", Scopes: "read write", CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "GetAPITokenByHash failed with not found token",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(sqlmock.NewRows(apiTokenColumns))
+			},
+		},
+		{
+			name: "GetAPITokenByHash failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs("hash").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetAPITokenByHash(context.Background(), "hash")
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetAPITokensByUserID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPITokenRepo(pg.NewDB(sqlxDB, 0))
+
+	createdAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	lastUsedAt := createdAt.Add(time.Hour)
+
+	query := "SELECT * FROM api_token WHERE user_id = $1 ORDER BY created_at DESC;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.APIToken
+		expectedError error
+	}{
+		{
+			name: "GetAPITokensByUserID successful",
+			mock: func() {
+				rows := sqlmock.NewRows(apiTokenColumns).
+					AddRow(2, 1, "ci", "hash2", "read", createdAt, nil, lastUsedAt).
+					AddRow(1, 1, "script", "hash1", "read write", createdAt, nil, nil)
+
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(rows)
+			},
+			want: []model.APIToken{
+				{ID: 2, UserID: 1, Name: "ci", TokenHash: "hash2", Scopes: "read", CreatedAt: createdAt, LastUsedAt: &lastUsedAt},
+				{ID: 1, UserID: 1, Name: "script", TokenHash: "hash1", Scopes: "read write", CreatedAt: createdAt},
+			},
+		},
+		{
+			name: "GetAPITokensByUserID failed with not found tokens",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnRows(sqlmock.NewRows(apiTokenColumns))
+			},
+		},
+		{
+			name: "GetAPITokensByUserID failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetAPITokensByUserID(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UpdateAPITokenLastUsed(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPITokenRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE api_token SET last_used_at = NOW() WHERE id = $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "UpdateAPITokenLastUsed successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "UpdateAPITokenLastUsed failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.UpdateAPITokenLastUsed(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteUserAPIToken(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPITokenRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "DELETE FROM api_token WHERE id = $1 AND user_id = $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "DeleteUserAPIToken successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "DeleteUserAPIToken failed with token of another user",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "DeleteUserAPIToken failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.DeleteUserAPIToken(context.Background(), 1, 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_DeleteAPITokensByUserID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewAPITokenRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "DELETE FROM api_token WHERE user_id = $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "DeleteAPITokensByUserID successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+			},
+		},
+		{
+			name: "DeleteAPITokensByUserID failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.DeleteAPITokensByUserID(context.Background(), 1)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
@@ -1,8 +1,12 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 )
@@ -15,24 +19,222 @@ func NewUserRepo(db *DB) *UserRepo {
 	return &UserRepo{db: db}
 }
 
-func (repo UserRepo) CreateUser(user *model.User) (int, error) {
-	var id int
-
-	row := repo.db.QueryRow(`
-		INSERT INTO tg_user(username, fullname, image_url) VALUES ($1, $2, $3) RETURNING id;`,
-		user.Username, user.FullName, user.ImageURL,
-	)
-	if err := row.Scan(&id); err != nil {
+// CreateUser creates the user or refreshes profile of the existing one and returns its id.
+// It runs the same queries as CreateUsers, so username collisions are resolved the same way.
+func (repo UserRepo) CreateUser(ctx context.Context, user *model.User) (int, error) {
+	ids, err := repo.CreateUsers(ctx, []model.User{*user})
+	if err != nil {
 		return 0, err
 	}
 
+	id, ok := ids[user.Username]
+	if !ok {
+		return 0, fmt.Errorf("user %q not returned by upsert", user.Username)
+	}
+
 	return id, nil
 }
 
-func (repo UserRepo) GetUserByUsername(username string) (*model.User, error) {
+// usersInput is a common table expression with users passed as arrays, zero Telegram id means unknown id.
+const usersInput = `
+	WITH input AS (
+		SELECT NULLIF(i.tg_id, 0) AS tg_id, i.username, i.fullname, i.image_url 
+		FROM UNNEST($1::BIGINT[], $2::VARCHAR[], $3::VARCHAR[], $4::TEXT[]) AS i(tg_id, username, fullname, image_url)
+	)`
+
+// usersMerge extends usersInput with rows known only by the username that are taken over by the Telegram id.
+const usersMerge = usersInput + `,
+	merged AS (
+		SELECT y.id AS from_id, x.id AS to_id 
+		FROM input i JOIN tg_user x ON x.tg_id = i.tg_id JOIN tg_user y ON y.username = i.username AND y.tg_id IS NULL
+	)`
+
+// CreateUsers upserts users in a constant number of queries.
+// Users are matched by Telegram id first and by username otherwise, previous profile values are kept in history.
+// A row without Telegram id holding the username of a known Telegram id is merged into it, a username held by
+// another Telegram id is freed since Telegram usernames are unique and the holder renamed itself.
+func (repo UserRepo) CreateUsers(ctx context.Context, users []model.User) (map[string]int, error) { //nolint:funlen
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	tgIDs := make([]int64, 0, len(users))
+	usernames := make([]string, 0, len(users))
+	fullnames := make([]string, 0, len(users))
+	imageURLs := make([]string, 0, len(users))
+	byUsername := make(map[string]int, len(users))
+	seenTgIDs := make(map[int64]struct{}, len(users))
+
+	// A single INSERT ... ON CONFLICT can't touch the same row twice, so duplicates by Telegram id or username are skipped.
+	for _, user := range users {
+		if user.TgID != nil {
+			if _, ok := seenTgIDs[*user.TgID]; ok {
+				continue
+			}
+		}
+
+		if i, ok := byUsername[user.Username]; ok {
+			if tgIDs[i] == 0 && user.TgID != nil {
+				tgIDs[i] = *user.TgID
+				seenTgIDs[*user.TgID] = struct{}{}
+			}
+
+			continue
+		}
+
+		var tgID int64
+		if user.TgID != nil {
+			tgID = *user.TgID
+			seenTgIDs[tgID] = struct{}{}
+		}
+
+		byUsername[user.Username] = len(usernames)
+
+		tgIDs = append(tgIDs, tgID)
+		usernames = append(usernames, user.Username)
+		fullnames = append(fullnames, user.FullName)
+		imageURLs = append(imageURLs, user.ImageURL)
+	}
+
+	args := []interface{}{pq.Array(tgIDs), pq.Array(usernames), pq.Array(fullnames), pq.Array(imageURLs)}
+
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	_, err = tx.ExecContext(ctx, usersMerge+`,
+		messages AS (UPDATE message m SET user_id = g.to_id FROM merged g WHERE m.user_id = g.from_id),
+		replies AS (UPDATE reply r SET user_id = g.to_id FROM merged g WHERE r.user_id = g.from_id)
+		UPDATE tg_user_history h SET user_id = g.to_id FROM merged g WHERE h.user_id = g.from_id;`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, usersMerge+`,
+		deleted AS (
+			DELETE FROM tg_user u USING merged g WHERE u.id = g.from_id 
+			RETURNING g.to_id, u.username, u.fullname, u.image_url
+		)
+		INSERT INTO tg_user_history(user_id, username, fullname, image_url) 
+		SELECT to_id, username, fullname, image_url FROM deleted;`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, usersInput+`
+		INSERT INTO tg_user_history(user_id, username, fullname, image_url) 
+		SELECT DISTINCT u.id, u.username, u.fullname, u.image_url 
+		FROM tg_user u JOIN input i ON u.tg_id = i.tg_id OR (u.username = i.username AND (u.tg_id IS NULL OR i.tg_id IS NULL)) 
+		WHERE u.username <> i.username OR u.fullname <> i.fullname OR u.image_url <> i.image_url;`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, usersInput+`,
+		freed AS (
+			INSERT INTO tg_user_history(user_id, username, fullname, image_url) 
+			SELECT DISTINCT u.id, u.username, u.fullname, u.image_url 
+			FROM tg_user u JOIN input i ON u.username = i.username AND u.tg_id <> i.tg_id 
+			WHERE NOT EXISTS (SELECT 1 FROM input j WHERE j.tg_id = u.tg_id)
+		)
+		UPDATE tg_user u SET username = '#' || u.id 
+		FROM input i WHERE u.username = i.username AND u.tg_id <> i.tg_id;`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, usersInput+`
+		UPDATE tg_user u SET username = i.username, fullname = i.fullname, image_url = i.image_url 
+		FROM input i WHERE u.tg_id = i.tg_id AND u.username <> i.username;`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, usersInput+`
+		INSERT INTO tg_user(tg_id, username, fullname, image_url) 
+		SELECT tg_id, username, fullname, image_url FROM input 
+		ON CONFLICT (username) DO UPDATE SET 
+		fullname = EXCLUDED.fullname, image_url = EXCLUDED.image_url, tg_id = COALESCE(tg_user.tg_id, EXCLUDED.tg_id) 
+		RETURNING id, username;`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	ids := make(map[string]int, len(users))
+
+	for rows.Next() {
+		var (
+			id       int
+			username string
+		)
+
+		if err := rows.Scan(&id, &username); err != nil {
+			return nil, err
+		}
+
+		ids[username] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (repo UserRepo) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var user model.User
+
+	err := repo.db.GetContext(ctx, &user, "SELECT * FROM tg_user WHERE username = $1;", username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (repo UserRepo) GetUserByTgID(ctx context.Context, tgID int64) (*model.User, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var user model.User
 
-	err := repo.db.Get(&user, "SELECT * FROM tg_user WHERE username = $1;", username)
+	err := repo.db.GetContext(ctx, &user, "SELECT * FROM tg_user WHERE tg_id = $1;", tgID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -44,10 +246,13 @@ func (repo UserRepo) GetUserByUsername(username string) (*model.User, error) {
 	return &user, nil
 }
 
-func (repo UserRepo) GetUserByID(id int) (*model.User, error) {
+func (repo UserRepo) GetUserByID(ctx context.Context, id int) (*model.User, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var user model.User
 
-	err := repo.db.Get(&user, "SELECT * FROM tg_user WHERE id = $1;", id)
+	err := repo.db.GetContext(ctx, &user, "SELECT * FROM tg_user WHERE id = $1;", id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -58,3 +263,44 @@ func (repo UserRepo) GetUserByID(id int) (*model.User, error) {
 
 	return &user, nil
 }
+
+func (repo UserRepo) GetUserHistory(ctx context.Context, userID int) ([]model.UserHistory, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var history []model.UserHistory
+
+	err := repo.db.SelectContext(
+		ctx,
+		&history,
+		"SELECT * FROM tg_user_history WHERE user_id = $1 ORDER BY changed_at DESC;",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return nil, nil
+	}
+
+	return history, nil
+}
+
+func (repo UserRepo) GetUsersByPage(ctx context.Context, page int) ([]model.User, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var users []model.User
+
+	err := repo.db.SelectContext(ctx, &users, "SELECT * FROM tg_user ORDER BY id LIMIT 10 OFFSET $1;", page)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	return users, nil
+}
@@ -1,11 +1,15 @@
 package pg_test
 
 import (
+	"context"
+	"database/sql/driver"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
@@ -13,6 +17,66 @@ import (
 	"github.com/VladPetriv/scanner_backend/internal/store/pg"
 )
 
+// Queries of UserRepo.CreateUsers, CreateUser runs the same ones for a single user.
+var (
+	usersInput = `
+		WITH input AS (
+			SELECT NULLIF(i.tg_id, 0) AS tg_id, i.username, i.fullname, i.image_url 
+			FROM UNNEST($1::BIGINT[], $2::VARCHAR[], $3::VARCHAR[], $4::TEXT[]) AS i(tg_id, username, fullname, image_url)
+		)`
+	usersMergedInput = usersInput + `,
+		merged AS (
+			SELECT y.id AS from_id, x.id AS to_id 
+			FROM input i JOIN tg_user x ON x.tg_id = i.tg_id JOIN tg_user y ON y.username = i.username AND y.tg_id IS NULL
+		)`
+	usersMergeQuery = usersMergedInput + `,
+		messages AS (UPDATE message m SET user_id = g.to_id FROM merged g WHERE m.user_id = g.from_id),
+		replies AS (UPDATE reply r SET user_id = g.to_id FROM merged g WHERE r.user_id = g.from_id)
+		UPDATE tg_user_history h SET user_id = g.to_id FROM merged g WHERE h.user_id = g.from_id;`
+	usersDeleteMergedQuery = usersMergedInput + `,
+		deleted AS (
+			DELETE FROM tg_user u USING merged g WHERE u.id = g.from_id 
+			RETURNING g.to_id, u.username, u.fullname, u.image_url
+		)
+		INSERT INTO tg_user_history(user_id, username, fullname, image_url) 
+		SELECT to_id, username, fullname, image_url FROM deleted;`
+	usersHistoryQuery = usersInput + `
+		INSERT INTO tg_user_history(user_id, username, fullname, image_url) 
+		SELECT DISTINCT u.id, u.username, u.fullname, u.image_url 
+		FROM tg_user u JOIN input i ON u.tg_id = i.tg_id OR (u.username = i.username AND (u.tg_id IS NULL OR i.tg_id IS NULL)) 
+		WHERE u.username <> i.username OR u.fullname <> i.fullname OR u.image_url <> i.image_url;`
+	usersFreeQuery = usersInput + `,
+		freed AS (
+			INSERT INTO tg_user_history(user_id, username, fullname, image_url) 
+			SELECT DISTINCT u.id, u.username, u.fullname, u.image_url 
+			FROM tg_user u JOIN input i ON u.username = i.username AND u.tg_id <> i.tg_id 
+			WHERE NOT EXISTS (SELECT 1 FROM input j WHERE j.tg_id = u.tg_id)
+		)
+		UPDATE tg_user u SET username = '#' || u.id 
+		FROM input i WHERE u.username = i.username AND u.tg_id <> i.tg_id;`
+	usersRenameQuery = usersInput + `
+		UPDATE tg_user u SET username = i.username, fullname = i.fullname, image_url = i.image_url 
+		FROM input i WHERE u.tg_id = i.tg_id AND u.username <> i.username;`
+	usersUpsertQuery = usersInput + `
+		INSERT INTO tg_user(tg_id, username, fullname, image_url) 
+		SELECT tg_id, username, fullname, image_url FROM input 
+		ON CONFLICT (username) DO UPDATE SET 
+		fullname = EXCLUDED.fullname, image_url = EXCLUDED.image_url, tg_id = COALESCE(tg_user.tg_id, EXCLUDED.tg_id) 
+		RETURNING id, username;`
+)
+
+// expectCreateUsers expects successful queries of CreateUsers with the arrays of users.
+func expectCreateUsers(mock sqlmock.Sqlmock, args []driver.Value, rows *sqlmock.Rows) {
+	mock.ExpectBegin()
+	mock.ExpectExec(usersMergeQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(usersDeleteMergedQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(usersHistoryQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(usersFreeQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(usersRenameQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(usersUpsertQuery).WithArgs(args...).WillReturnRows(rows)
+	mock.ExpectCommit()
+}
+
 func Test_CreateUser(t *testing.T) {
 	db, mock, err := mocks.CreateMock()
 	if err != nil {
@@ -21,7 +85,9 @@ func Test_CreateUser(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewUserRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewUserRepo(pg.NewDB(sqlxDB, 0))
+
+	tgID := int64(100)
 
 	tests := []struct {
 		name          string
@@ -33,20 +99,42 @@ func Test_CreateUser(t *testing.T) {
 		{
 			name: "CreateUser successful",
 			mock: func() {
-				row := sqlmock.NewRows([]string{"id"}).AddRow(1)
-				mock.ExpectQuery(`
-					INSERT INTO tg_user(username, fullname, image_url) VALUES ($1, $2, $3) RETURNING id;`,
-				).WithArgs("test", "test test", "test.jpg").WillReturnRows(row)
+				expectCreateUsers(mock, []driver.Value{
+					pq.Array([]int64{0}), pq.Array([]string{"test"}),
+					pq.Array([]string{"test test"}), pq.Array([]string{"test.jpg"}),
+				}, sqlmock.NewRows([]string{"id", "username"}).AddRow(1, "test"))
 			},
 			input: &model.User{Username: "test", FullName: "test test", ImageURL: "test.jpg"},
 			want:  1,
 		},
+		{
+			name: "CreateUser successful with username reused by another telegram id",
+			mock: func() {
+				expectCreateUsers(mock, []driver.Value{
+					pq.Array([]int64{100}), pq.Array([]string{"test"}),
+					pq.Array([]string{"test test"}), pq.Array([]string{"test.jpg"}),
+				}, sqlmock.NewRows([]string{"id", "username"}).AddRow(3, "test"))
+			},
+			input: &model.User{TgID: &tgID, Username: "test", FullName: "test test", ImageURL: "test.jpg"},
+			want:  3,
+		},
+		{
+			name: "CreateUser successful with rename onto taken username",
+			mock: func() {
+				expectCreateUsers(mock, []driver.Value{
+					pq.Array([]int64{100}), pq.Array([]string{"taken"}),
+					pq.Array([]string{"test test"}), pq.Array([]string{"test.jpg"}),
+				}, sqlmock.NewRows([]string{"id", "username"}).AddRow(3, "taken"))
+			},
+			input: &model.User{TgID: &tgID, Username: "taken", FullName: "test test", ImageURL: "test.jpg"},
+			want:  3,
+		},
 		{
 			name: "CreateUser failed with some sql error",
 			mock: func() {
-				mock.ExpectQuery(`
-					INSERT INTO tg_user(username, fullname, image_url) VALUES ($1, $2, $3) RETURNING id;`,
-				).WithArgs("test", "test test", "test.jpg").WillReturnError(fmt.Errorf("some sql error"))
+				mock.ExpectBegin()
+				mock.ExpectExec(usersMergeQuery).WillReturnError(fmt.Errorf("some sql error"))
+				mock.ExpectRollback()
 			},
 			input:         &model.User{Username: "test", FullName: "test test", ImageURL: "test.jpg"},
 			expectedError: fmt.Errorf("some sql error"),
@@ -56,7 +144,7 @@ func Test_CreateUser(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.CreateUser(tt.input)
+			got, err := r.CreateUser(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -74,6 +162,274 @@ func Test_CreateUser(t *testing.T) {
 	})
 }
 
+func Test_CreateUsers(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewUserRepo(pg.NewDB(sqlxDB, 0))
+
+	tgID := int64(100)
+	otherTgID := int64(200)
+
+	args := []driver.Value{
+		pq.Array([]int64{0, 100}),
+		pq.Array([]string{"test1", "test2"}),
+		pq.Array([]string{"test test1", "test test2"}),
+		pq.Array([]string{"test1.jpg", "test2.jpg"}),
+	}
+	swapArgs := []driver.Value{
+		pq.Array([]int64{100, 200}),
+		pq.Array([]string{"test2", "test1"}),
+		pq.Array([]string{"test test2", "test test1"}),
+		pq.Array([]string{"test2.jpg", "test1.jpg"}),
+	}
+	mixedArgs := []driver.Value{
+		pq.Array([]int64{100, 0}),
+		pq.Array([]string{"test1", "test2"}),
+		pq.Array([]string{"test test1", "test test2"}),
+		pq.Array([]string{"test1.jpg", "test2.jpg"}),
+	}
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         []model.User
+		want          map[string]int
+		expectedError error
+	}{
+		{
+			name: "CreateUsers successful",
+			mock: func() {
+				expectCreateUsers(mock, args, sqlmock.NewRows([]string{"id", "username"}).AddRow(1, "test1").AddRow(2, "test2"))
+			},
+			input: []model.User{
+				{Username: "test1", FullName: "test test1", ImageURL: "test1.jpg"},
+				{TgID: &tgID, Username: "test2", FullName: "test test2", ImageURL: "test2.jpg"},
+				{Username: "test1", FullName: "test test1", ImageURL: "test1.jpg"},
+			},
+			want: map[string]int{"test1": 1, "test2": 2},
+		},
+		{
+			name: "CreateUsers successful with swapped usernames",
+			mock: func() {
+				expectCreateUsers(mock, swapArgs, sqlmock.NewRows([]string{"id", "username"}).AddRow(1, "test2").AddRow(2, "test1"))
+			},
+			input: []model.User{
+				{TgID: &tgID, Username: "test2", FullName: "test test2", ImageURL: "test2.jpg"},
+				{TgID: &otherTgID, Username: "test1", FullName: "test test1", ImageURL: "test1.jpg"},
+			},
+			want: map[string]int{"test2": 1, "test1": 2},
+		},
+		{
+			name: "CreateUsers successful with users matched by telegram id and by username",
+			mock: func() {
+				expectCreateUsers(mock, mixedArgs, sqlmock.NewRows([]string{"id", "username"}).AddRow(1, "test1").AddRow(2, "test2"))
+			},
+			input: []model.User{
+				{Username: "test1", FullName: "test test1", ImageURL: "test1.jpg"},
+				{TgID: &tgID, Username: "test1", FullName: "test test1", ImageURL: "test1.jpg"},
+				{Username: "test2", FullName: "test test2", ImageURL: "test2.jpg"},
+				{TgID: &tgID, Username: "test3", FullName: "test test3", ImageURL: "test3.jpg"},
+			},
+			want: map[string]int{"test1": 1, "test2": 2},
+		},
+		{
+			name:  "CreateUsers successful with empty input",
+			mock:  func() {},
+			input: []model.User{},
+		},
+		{
+			name: "CreateUsers failed with some sql error",
+			mock: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec(usersMergeQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(usersDeleteMergedQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(usersHistoryQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(usersFreeQuery).WithArgs(args...).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(usersRenameQuery).WithArgs(args...).WillReturnError(fmt.Errorf("some sql error"))
+				mock.ExpectRollback()
+			},
+			input: []model.User{
+				{Username: "test1", FullName: "test test1", ImageURL: "test1.jpg"},
+				{TgID: &tgID, Username: "test2", FullName: "test test2", ImageURL: "test2.jpg"},
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CreateUsers(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.EqualValues(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetUserByTgID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewUserRepo(pg.NewDB(sqlxDB, 0))
+
+	tgID := int64(100)
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         int64
+		want          *model.User
+		expectedError error
+	}{
+		{
+			name: "GetUserByTgID successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "tg_id", "username", "fullname", "image_url"}).
+					AddRow(1, 100, "test", "test test", "test.jpg")
+
+				mock.ExpectQuery("SELECT * FROM tg_user WHERE tg_id = $1;").
+					WithArgs(100).WillReturnRows(rows)
+			},
+			input: 100,
+			want:  &model.User{ID: 1, TgID: &tgID, Username: "test", FullName: "test test", ImageURL: "test.jpg"},
+		},
+		{
+			name: "GetUserByTgID failed with not found user",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "tg_id", "username", "fullname", "image_url"})
+
+				mock.ExpectQuery("SELECT * FROM tg_user WHERE tg_id = $1;").
+					WithArgs(100).WillReturnRows(rows)
+			},
+			input: 100,
+		},
+		{
+			name: "GetUserByTgID failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM tg_user WHERE tg_id = $1;").
+					WithArgs(100).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         100,
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetUserByTgID(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetUserHistory(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewUserRepo(pg.NewDB(sqlxDB, 0))
+
+	changedAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         int
+		want          []model.UserHistory
+		expectedError error
+	}{
+		{
+			name: "GetUserHistory successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "username", "fullname", "image_url", "changed_at"}).
+					AddRow(1, 1, "old", "old test", "old.jpg", changedAt)
+
+				mock.ExpectQuery("SELECT * FROM tg_user_history WHERE user_id = $1 ORDER BY changed_at DESC;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: 1,
+			want: []model.UserHistory{
+				{ID: 1, UserID: 1, Username: "old", FullName: "old test", ImageURL: "old.jpg", ChangedAt: changedAt},
+			},
+		},
+		{
+			name: "GetUserHistory failed with not found history",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "user_id", "username", "fullname", "image_url", "changed_at"})
+
+				mock.ExpectQuery("SELECT * FROM tg_user_history WHERE user_id = $1 ORDER BY changed_at DESC;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: 1,
+		},
+		{
+			name: "GetUserHistory failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM tg_user_history WHERE user_id = $1 ORDER BY changed_at DESC;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         1,
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetUserHistory(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
 func Test_GetUserByUsername(t *testing.T) {
 	db, mock, err := mocks.CreateMock()
 	if err != nil {
@@ -82,7 +438,7 @@ func Test_GetUserByUsername(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewUserRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewUserRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -128,7 +484,7 @@ func Test_GetUserByUsername(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetUserByUsername(tt.input)
+			got, err := r.GetUserByUsername(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -154,7 +510,7 @@ func Test_GetUserByID(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewUserRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewUserRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -200,7 +556,73 @@ func Test_GetUserByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetUserByID(tt.input)
+			got, err := r.GetUserByID(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetUsersByPage(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewUserRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "SELECT * FROM tg_user ORDER BY id LIMIT 10 OFFSET $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.User
+		expectedError error
+	}{
+		{
+			name: "GetUsersByPage successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "username", "fullname", "image_url"}).
+					AddRow(1, "test", "test test", "test.jpg")
+
+				mock.ExpectQuery(query).WithArgs(10).WillReturnRows(rows)
+			},
+			want: []model.User{{ID: 1, Username: "test", FullName: "test test", ImageURL: "test.jpg"}},
+		},
+		{
+			name: "GetUsersByPage failed with not found users",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "username", "fullname", "image_url"})
+
+				mock.ExpectQuery(query).WithArgs(10).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "GetUsersByPage failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(10).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetUsersByPage(context.Background(), 10)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -1,8 +1,10 @@
 package pg
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/VladPetriv/scanner_backend/internal/model"
 )
@@ -15,8 +17,12 @@ func NewWebUserRepo(db *DB) *WebUserRepo {
 	return &WebUserRepo{db: db}
 }
 
-func (repo WebUserRepo) CreateWebUser(user *model.WebUser) error {
-	_, err := repo.db.Exec(
+func (repo WebUserRepo) CreateWebUser(ctx context.Context, user *model.WebUser) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx,
 		"INSERT INTO web_user(email, password) VALUES ($1, $2);",
 		user.Email, user.Password,
 	)
@@ -27,10 +33,31 @@ func (repo WebUserRepo) CreateWebUser(user *model.WebUser) error {
 	return nil
 }
 
-func (repo WebUserRepo) GetWebUserByEmail(email string) (*model.WebUser, error) {
+func (repo WebUserRepo) GetWebUserByEmail(ctx context.Context, email string) (*model.WebUser, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var user model.WebUser
+
+	err := repo.db.GetContext(ctx, &user, "SELECT * FROM web_user WHERE email = $1;", email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (repo WebUserRepo) GetWebUserByID(ctx context.Context, id int) (*model.WebUser, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
 	var user model.WebUser
 
-	err := repo.db.Get(&user, "SELECT * FROM web_user WHERE email = $1;", email)
+	err := repo.db.GetContext(ctx, &user, "SELECT * FROM web_user WHERE id = $1;", id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -41,3 +68,127 @@ func (repo WebUserRepo) GetWebUserByEmail(email string) (*model.WebUser, error)
 
 	return &user, nil
 }
+
+func (repo WebUserRepo) UpdateWebUserPassword(ctx context.Context, id int, password string) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "UPDATE web_user SET password = $1 WHERE id = $2;", password, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VerifyWebUserEmail marks email of the user as verified, false means that user with such email is not found.
+func (repo WebUserRepo) VerifyWebUserEmail(ctx context.Context, id int, email string) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(
+		ctx, "UPDATE web_user SET email_verified = TRUE WHERE id = $1 AND email = $2;", id, email,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo WebUserRepo) UpdateWebUserTOTP(ctx context.Context, id int, secret string, enabled bool) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(
+		ctx, "UPDATE web_user SET totp_secret = $1, totp_enabled = $2 WHERE id = $3;", secret, enabled, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateWebUserTOTPCounter saves the time step of the accepted code, false means that the step isn't newer
+// than the last accepted one and the code is replayed.
+func (repo WebUserRepo) UpdateWebUserTOTPCounter(ctx context.Context, id int, counter int64) (bool, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	result, err := repo.db.ExecContext(
+		ctx, "UPDATE web_user SET totp_last_counter = $1 WHERE id = $2 AND totp_last_counter < $1;", counter, id,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (repo WebUserRepo) UpdateWebUserRole(ctx context.Context, id int, role model.Role) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "UPDATE web_user SET role = $1 WHERE id = $2;", role, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo WebUserRepo) CountWebUsersByRole(ctx context.Context, role model.Role) (int, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var count int
+
+	err := repo.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM web_user WHERE role = $1;", role)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (repo WebUserRepo) GetWebUsersByPage(ctx context.Context, page int) ([]model.WebUser, error) {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	var users []model.WebUser
+
+	err := repo.db.SelectContext(ctx, &users, "SELECT * FROM web_user ORDER BY id LIMIT 10 OFFSET $1;", page)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	return users, nil
+}
+
+// UpdateWebUserBannedAt bans the user, nil time lifts the ban.
+func (repo WebUserRepo) UpdateWebUserBannedAt(ctx context.Context, id int, bannedAt *time.Time) error {
+	ctx, cancel := repo.db.withTimeout(ctx)
+	defer cancel()
+
+	_, err := repo.db.ExecContext(ctx, "UPDATE web_user SET banned_at = $1 WHERE id = $2;", bannedAt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
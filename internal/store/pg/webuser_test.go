@@ -1,8 +1,10 @@
 package pg_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -21,7 +23,7 @@ func Test_CreateWebUser(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewWebUserRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -51,7 +53,7 @@ func Test_CreateWebUser(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			err := r.CreateWebUser(tt.input)
+			err := r.CreateWebUser(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError, err)
@@ -76,7 +78,7 @@ func Test_GetWebUserByEmail(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "postgres")
 
-	r := pg.NewWebUserRepo(&pg.DB{DB: sqlxDB})
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
 
 	tests := []struct {
 		name          string
@@ -122,7 +124,7 @@ func Test_GetWebUserByEmail(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
 
-			got, err := r.GetWebUserByEmail(tt.input)
+			got, err := r.GetWebUserByEmail(context.Background(), tt.input)
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.EqualValues(t, tt.expectedError, err)
@@ -139,3 +141,541 @@ func Test_GetWebUserByEmail(t *testing.T) {
 		db.Close()
 	})
 }
+
+func Test_GetWebUserByID(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         int
+		want          *model.WebUser
+		expectedError error
+	}{
+		{
+			name: "GetWebUserByID successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "email", "password"}).
+					AddRow(1, "test@test.com", "test")
+
+				mock.ExpectQuery("SELECT * FROM web_user WHERE id = $1;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: 1,
+			want:  &model.WebUser{ID: 1, Email: "test@test.com", Password: "test"},
+		},
+		{
+			name: "GetWebUserByID failed with not found user",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "email", "password"})
+
+				mock.ExpectQuery("SELECT * FROM web_user WHERE id = $1;").
+					WithArgs(1).WillReturnRows(rows)
+			},
+			input: 1,
+		},
+		{
+			name: "GetWebUserByID failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery("SELECT * FROM web_user WHERE id = $1;").
+					WithArgs(1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         1,
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetWebUserByID(context.Background(), tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UpdateWebUserPassword(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "UpdateWebUserPassword successful",
+			mock: func() {
+				mock.ExpectExec("UPDATE web_user SET password = $1 WHERE id = $2;").
+					WithArgs("hash", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "UpdateWebUserPassword failed with some sql error",
+			mock: func() {
+				mock.ExpectExec("UPDATE web_user SET password = $1 WHERE id = $2;").
+					WithArgs("hash", 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.UpdateWebUserPassword(context.Background(), 1, "hash")
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_VerifyWebUserEmail(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE web_user SET email_verified = TRUE WHERE id = $1 AND email = $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "VerifyWebUserEmail successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "test@test.com").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "VerifyWebUserEmail failed with not found user",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "test@test.com").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "VerifyWebUserEmail failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(1, "test@test.com").WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.VerifyWebUserEmail(context.Background(), 1, "test@test.com")
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UpdateWebUserTOTP(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE web_user SET totp_secret = $1, totp_enabled = $2 WHERE id = $3;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "UpdateWebUserTOTP successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("SECRET", true, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "UpdateWebUserTOTP failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs("SECRET", true, 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.UpdateWebUserTOTP(context.Background(), 1, "SECRET", true)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UpdateWebUserTOTPCounter(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE web_user SET totp_last_counter = $1 WHERE id = $2 AND totp_last_counter < $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          bool
+		expectedError error
+	}{
+		{
+			name: "UpdateWebUserTOTPCounter successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(int64(100), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			want: true,
+		},
+		{
+			name: "UpdateWebUserTOTPCounter failed with already used counter",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(int64(100), 1).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name: "UpdateWebUserTOTPCounter failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(int64(100), 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.UpdateWebUserTOTPCounter(context.Background(), 1, 100)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+func Test_UpdateWebUserRole(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE web_user SET role = $1 WHERE id = $2;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		expectedError error
+	}{
+		{
+			name: "UpdateWebUserRole successful",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(model.RoleAdmin, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "UpdateWebUserRole failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(model.RoleAdmin, 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.UpdateWebUserRole(context.Background(), 1, model.RoleAdmin)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_CountWebUsersByRole(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "SELECT COUNT(*) FROM web_user WHERE role = $1;"
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          int
+		expectedError error
+	}{
+		{
+			name: "CountWebUsersByRole successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(2)
+
+				mock.ExpectQuery(query).WithArgs(model.RoleAdmin).WillReturnRows(rows)
+			},
+			want: 2,
+		},
+		{
+			name: "CountWebUsersByRole failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(model.RoleAdmin).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.CountWebUsersByRole(context.Background(), model.RoleAdmin)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_GetWebUsersByPage(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "SELECT * FROM web_user ORDER BY id LIMIT 10 OFFSET $1;"
+	bannedAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mock          func()
+		want          []model.WebUser
+		expectedError error
+	}{
+		{
+			name: "GetWebUsersByPage successful",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "banned_at"}).
+					AddRow(1, "test@test.com", "test", model.RoleAdmin, nil).
+					AddRow(2, "spam@test.com", "test", model.RoleMember, bannedAt)
+
+				mock.ExpectQuery(query).WithArgs(0).WillReturnRows(rows)
+			},
+			want: []model.WebUser{
+				{ID: 1, Email: "test@test.com", Password: "test", Role: model.RoleAdmin},
+				{ID: 2, Email: "spam@test.com", Password: "test", Role: model.RoleMember, BannedAt: &bannedAt},
+			},
+		},
+		{
+			name: "GetWebUsersByPage failed with not found users",
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "email", "password", "role", "banned_at"})
+
+				mock.ExpectQuery(query).WithArgs(0).WillReturnRows(rows)
+			},
+		},
+		{
+			name: "GetWebUsersByPage failed with some sql error",
+			mock: func() {
+				mock.ExpectQuery(query).WithArgs(0).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			got, err := r.GetWebUsersByPage(context.Background(), 0)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
+
+func Test_UpdateWebUserBannedAt(t *testing.T) {
+	db, mock, err := mocks.CreateMock()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	sqlxDB := sqlx.NewDb(db, "postgres")
+
+	r := pg.NewWebUserRepo(pg.NewDB(sqlxDB, 0))
+
+	query := "UPDATE web_user SET banned_at = $1 WHERE id = $2;"
+	bannedAt := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		mock          func()
+		input         *time.Time
+		expectedError error
+	}{
+		{
+			name: "UpdateWebUserBannedAt successful with ban",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(&bannedAt, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			input: &bannedAt,
+		},
+		{
+			name: "UpdateWebUserBannedAt successful with lifted ban",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "UpdateWebUserBannedAt failed with some sql error",
+			mock: func() {
+				mock.ExpectExec(query).WithArgs(&bannedAt, 1).WillReturnError(fmt.Errorf("some sql error"))
+			},
+			input:         &bannedAt,
+			expectedError: fmt.Errorf("some sql error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+
+			err := r.UpdateWebUserBannedAt(context.Background(), 1, tt.input)
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.EqualValues(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+}
@@ -1,51 +1,165 @@
 package store
 
-import "github.com/VladPetriv/scanner_backend/internal/model"
+import (
+	"context"
+	"time"
+
+	"github.com/VladPetriv/scanner_backend/internal/model"
+)
 
 //go:generate mockery --dir . --name ChannelRepo --output ./mocks
 type ChannelRepo interface {
-	CreateChannel(channel *model.DBChannel) error
-	GetChannels() ([]model.Channel, error)
-	GetChannelsByPage(page int) ([]model.Channel, error)
-	GetChannelByName(name string) (*model.Channel, error)
-	GetChannelStats(channelID int) (*model.Stat, error)
+	CreateChannel(ctx context.Context, channel *model.DBChannel) error
+	UpdateChannel(ctx context.Context, channel *model.DBChannel) error
+	GetChannels(ctx context.Context) ([]model.Channel, error)
+	GetAllChannels(ctx context.Context) ([]model.Channel, error)
+	GetChannelsByPage(ctx context.Context, page int) ([]model.Channel, error)
+	GetChannelByName(ctx context.Context, name string) (*model.Channel, error)
+	GetChannelStats(ctx context.Context, channelID int) (*model.Stat, error)
+	UpdateChannelEnabled(ctx context.Context, id int, enabled bool) (bool, error)
+	UpdateChannelHidden(ctx context.Context, id int, hidden bool) (bool, error)
 }
 
 //go:generate mockery --dir . --name MessageRepo --output ./mocks
 type MessageRepo interface {
-	CreateMessage(message *model.DBMessage) (int, error)
-	GetMessagesCount() (int, error)
-	GetMessagesCountByChannelID(id int) (int, error)
-	GetMessageByTitle(title string) (*model.DBMessage, error)
-	GetFullMessagesByPage(page int) ([]model.FullMessage, error)
-	GetFullMessagesByChannelIDAndPage(id, page int) ([]model.FullMessage, error)
-	GetFullMessagesByUserID(id int) ([]model.FullMessage, error)
-	GetFullMessageByID(id int) (*model.FullMessage, error)
+	CreateMessage(ctx context.Context, message *model.DBMessage) (int, error)
+	GetMessagesCount(ctx context.Context) (int, error)
+	GetMessagesCountByChannelID(ctx context.Context, id int) (int, error)
+	GetMessagesCountByKind(ctx context.Context, kind model.MessageKind) (int, error)
+	GetMessageByTitle(ctx context.Context, title string) (*model.DBMessage, error)
+	GetFullMessagesByPage(ctx context.Context, page int) ([]model.FullMessage, error)
+	GetFullMessagesByKindAndPage(ctx context.Context, kind model.MessageKind, page int) ([]model.FullMessage, error)
+	GetFullMessagesByChannelIDAndPage(ctx context.Context, id, page int) ([]model.FullMessage, error)
+	GetFullMessagesByUserID(ctx context.Context, id int) ([]model.FullMessage, error)
+	GetFullMessageByID(ctx context.Context, id int) (*model.FullMessage, error)
+	UpdateMessageHidden(ctx context.Context, id int, hidden bool) (bool, error)
+	GetMessagesAfterID(ctx context.Context, afterID int, limit int) ([]model.DBMessage, error)
+	UpdateMessageKind(ctx context.Context, id int, kind model.MessageKind) (bool, error)
 }
 
 //go:generate mockery --dir . --name ReplyRepo --output ./mocks
 type ReplyRepo interface {
-	CreateReply(reply *model.DBReply) error
-	GetFullRepliesByMessageID(id int) ([]model.FullReply, error)
+	CreateReply(ctx context.Context, reply *model.DBReply) error
+	CreateReplies(ctx context.Context, replies []model.DBReply) error
+	GetFullRepliesByMessageID(ctx context.Context, id int) ([]model.FullReply, error)
+	UpdateReplyHidden(ctx context.Context, id int, hidden bool) (bool, error)
 }
 
 //go:generate mockery --dir . --name UserRepo --output ./mocks
 type UserRepo interface {
-	CreateUser(user *model.User) (int, error)
-	GetUserByUsername(username string) (*model.User, error)
-	GetUserByID(id int) (*model.User, error)
+	CreateUser(ctx context.Context, user *model.User) (int, error)
+	CreateUsers(ctx context.Context, users []model.User) (map[string]int, error)
+	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
+	GetUserByTgID(ctx context.Context, tgID int64) (*model.User, error)
+	GetUserByID(ctx context.Context, id int) (*model.User, error)
+	GetUserHistory(ctx context.Context, userID int) ([]model.UserHistory, error)
+	GetUsersByPage(ctx context.Context, page int) ([]model.User, error)
 }
 
 //go:generate mockery --dir . --name WebUserRepo --output ./mocks
 type WebUserRepo interface {
-	GetWebUserByEmail(email string) (*model.WebUser, error)
-	CreateWebUser(user *model.WebUser) error
+	GetWebUserByEmail(ctx context.Context, email string) (*model.WebUser, error)
+	GetWebUserByID(ctx context.Context, id int) (*model.WebUser, error)
+	CreateWebUser(ctx context.Context, user *model.WebUser) error
+	UpdateWebUserPassword(ctx context.Context, id int, password string) error
+	VerifyWebUserEmail(ctx context.Context, id int, email string) (bool, error)
+	UpdateWebUserTOTP(ctx context.Context, id int, secret string, enabled bool) error
+	UpdateWebUserTOTPCounter(ctx context.Context, id int, counter int64) (bool, error)
+	UpdateWebUserRole(ctx context.Context, id int, role model.Role) error
+	CountWebUsersByRole(ctx context.Context, role model.Role) (int, error)
+	GetWebUsersByPage(ctx context.Context, page int) ([]model.WebUser, error)
+	UpdateWebUserBannedAt(ctx context.Context, id int, bannedAt *time.Time) error
 }
 
 //go:generate mockery --dir . --name SavedRepo --output ./mocks
 type SavedRepo interface {
-	CreateSavedMessage(saved *model.Saved) error
-	GetSavedMessages(userID int) ([]model.Saved, error)
-	GetSavedMessageByID(id int) (*model.Saved, error)
-	DeleteSavedMessage(id int) error
+	CreateSavedMessage(ctx context.Context, saved *model.Saved) error
+	GetSavedMessages(ctx context.Context, userID int) ([]model.Saved, error)
+	GetSavedMessageByID(ctx context.Context, id int) (*model.Saved, error)
+	DeleteSavedMessage(ctx context.Context, id int) error
+}
+
+//go:generate mockery --dir . --name SessionRepo --output ./mocks
+type SessionRepo interface {
+	CreateSession(ctx context.Context, session *model.Session) error
+	GetSessionByID(ctx context.Context, id string) (*model.Session, error)
+	GetSessionsByUserID(ctx context.Context, userID int) ([]model.Session, error)
+	DeleteSession(ctx context.Context, id string) error
+	DeleteSessionsByUserID(ctx context.Context, userID int) error
+	DeleteExpiredSessions(ctx context.Context) error
+}
+
+//go:generate mockery --dir . --name PasswordResetRepo --output ./mocks
+type PasswordResetRepo interface {
+	CreatePasswordReset(ctx context.Context, reset *model.PasswordReset) error
+	GetPasswordReset(ctx context.Context, tokenHash string) (*model.PasswordReset, error)
+	ResetPassword(ctx context.Context, tokenHash string, password string) (*model.PasswordReset, error)
+	DeletePasswordResetsByUserID(ctx context.Context, userID int) error
+}
+
+//go:generate mockery --dir . --name RecoveryCodeRepo --output ./mocks
+type RecoveryCodeRepo interface {
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	DeleteRecoveryCodes(ctx context.Context, userID int) error
+}
+
+//go:generate mockery --dir . --name APITokenRepo --output ./mocks
+type APITokenRepo interface {
+	CreateAPIToken(ctx context.Context, apiToken *model.APIToken) error
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (*model.APIToken, error)
+	GetAPITokensByUserID(ctx context.Context, userID int) ([]model.APIToken, error)
+	UpdateAPITokenLastUsed(ctx context.Context, id int) error
+	DeleteUserAPIToken(ctx context.Context, userID int, id int) (bool, error)
+	DeleteAPITokensByUserID(ctx context.Context, userID int) error
+}
+
+//go:generate mockery --dir . --name LoginFailureRepo --output ./mocks
+type LoginFailureRepo interface {
+	CreateLoginFailure(ctx context.Context, failure *model.LoginFailure) error
+	GetLoginFailuresByEmail(ctx context.Context, email string, since time.Time) (*model.LoginFailureStats, error)
+	GetLoginFailuresByIP(ctx context.Context, ipAddress string, since time.Time) (*model.LoginFailureStats, error)
+	DeleteLoginFailuresByEmail(ctx context.Context, email string) error
+	DeleteLoginFailuresBefore(ctx context.Context, before time.Time) error
+}
+
+//go:generate mockery --dir . --name HealthRepo --output ./mocks
+type HealthRepo interface {
+	Ping(ctx context.Context) error
+	GetMigrationVersion(ctx context.Context) (*model.MigrationVersion, error)
+}
+
+//go:generate mockery --dir . --name IngestionErrorRepo --output ./mocks
+type IngestionErrorRepo interface {
+	CreateIngestionError(ctx context.Context, ingestionError *model.IngestionError) error
+	GetIngestionErrors(ctx context.Context, limit int) ([]model.IngestionError, error)
+	GetIngestionErrorByID(ctx context.Context, id int) (*model.IngestionError, error)
+	DeleteIngestionError(ctx context.Context, id int) (bool, error)
+}
+
+//go:generate mockery --dir . --name BufferedRecordRepo --output ./mocks
+type BufferedRecordRepo interface {
+	CreateBufferedRecord(ctx context.Context, record *model.BufferedRecord) error
+	ClaimBufferedRecord(ctx context.Context, channelID int) (*model.BufferedRecord, error)
+}
+
+//go:generate mockery --dir . --name QuarantineRepo --output ./mocks
+type QuarantineRepo interface {
+	CreateQuarantinedMessage(ctx context.Context, message *model.QuarantinedMessage) error
+	GetQuarantinedMessagesByPage(ctx context.Context, page int) ([]model.QuarantinedMessage, error)
+	GetQuarantinedMessageByID(ctx context.Context, id int) (*model.QuarantinedMessage, error)
+	DeleteQuarantinedMessage(ctx context.Context, id int) (bool, error)
+}
+
+//go:generate mockery --dir . --name ReportRepo --output ./mocks
+type ReportRepo interface {
+	CreateReport(ctx context.Context, report *model.Report) error
+	GetOpenReportsByPage(ctx context.Context, page int) ([]model.ReportedMessage, error)
+	ResolveReportsByMessageID(ctx context.Context, messageID int, resolvedAt time.Time) (bool, error)
+}
+
+//go:generate mockery --dir . --name ModerationActionRepo --output ./mocks
+type ModerationActionRepo interface {
+	CreateModerationAction(ctx context.Context, action *model.ModerationAction) error
+	GetModerationActions(ctx context.Context, limit int) ([]model.ModerationAction, error)
 }
@@ -1,85 +1,171 @@
 package store
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/VladPetriv/scanner_backend/internal/store/pg"
 	"github.com/VladPetriv/scanner_backend/pkg/config"
 	"github.com/VladPetriv/scanner_backend/pkg/logger"
 )
 
 type Store struct {
-	pg     *pg.DB
-	logger *logger.Logger
-
-	Channel ChannelRepo
-	Message MessageRepo
-	Reply   ReplyRepo
-	User    UserRepo
-	WebUser WebUserRepo
-	Saved   SavedRepo
+	pg      *pg.DB
+	logger  *logger.Logger
+	metrics *storeMetrics
+	done    chan struct{}
+
+	Channel          ChannelRepo
+	Message          MessageRepo
+	Reply            ReplyRepo
+	User             UserRepo
+	WebUser          WebUserRepo
+	Saved            SavedRepo
+	Session          SessionRepo
+	PasswordReset    PasswordResetRepo
+	RecoveryCode     RecoveryCodeRepo
+	LoginFailure     LoginFailureRepo
+	APIToken         APITokenRepo
+	IngestionError   IngestionErrorRepo
+	BufferedRecord   BufferedRecordRepo
+	Quarantine       QuarantineRepo
+	Report           ReportRepo
+	ModerationAction ModerationActionRepo
+	Health           HealthRepo
 }
 
-func New(cfg *config.Config, log *logger.Logger) (*Store, error) {
+func New(cfg *config.Config, log *logger.Logger, registerer prometheus.Registerer) (*Store, error) {
 	pgDB, err := pg.Init(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("init postgresql: %w", err)
 	}
 
-	if pgDB != nil {
+	if cfg.AutoMigrate {
 		log.Info().Msg("Running migrations...")
 		if err := runMigrations(cfg); err != nil {
+			if closeErr := pgDB.Close(); closeErr != nil {
+				log.Error().Err(closeErr).Msg("close postgresql after failed migrations")
+			}
+
 			return nil, fmt.Errorf("run migrations: %w", err)
 		}
+	} else {
+		log.Info().Msg("Auto migration is disabled, skip running migrations")
 	}
 
-	var store Store
-	store.logger = log
-
-	if pgDB != nil {
-		store.pg = pgDB
-		store.Channel = pg.NewChannelRepo(pgDB)
-		store.Message = pg.NewMessageRepo(pgDB)
-		store.Reply = pg.NewReplyRepo(pgDB)
-		store.User = pg.NewUserRepo(pgDB)
-		store.WebUser = pg.NewWebUserRepo(pgDB)
-		store.Saved = pg.NewSavedRepo(pgDB)
-
-		go store.KeepAliveDB(cfg)
+	store := &Store{
+		pg:      pgDB,
+		logger:  log,
+		metrics: newStoreMetrics(registerer, pgDB),
+		done:    make(chan struct{}),
+
+		Channel:          pg.NewChannelRepo(pgDB),
+		Message:          pg.NewMessageRepo(pgDB),
+		Reply:            pg.NewReplyRepo(pgDB),
+		User:             pg.NewUserRepo(pgDB),
+		WebUser:          pg.NewWebUserRepo(pgDB),
+		Saved:            pg.NewSavedRepo(pgDB),
+		Session:          pg.NewSessionRepo(pgDB),
+		PasswordReset:    pg.NewPasswordResetRepo(pgDB),
+		RecoveryCode:     pg.NewRecoveryCodeRepo(pgDB),
+		LoginFailure:     pg.NewLoginFailureRepo(pgDB),
+		APIToken:         pg.NewAPITokenRepo(pgDB),
+		IngestionError:   pg.NewIngestionErrorRepo(pgDB),
+		BufferedRecord:   pg.NewBufferedRecordRepo(pgDB),
+		Quarantine:       pg.NewQuarantineRepo(pgDB),
+		Report:           pg.NewReportRepo(pgDB),
+		ModerationAction: pg.NewModerationActionRepo(pgDB),
+		Health:           pg.NewHealthRepo(pgDB),
 	}
 
-	return &store, nil
+	go store.KeepAliveDB(cfg)
+
+	return store, nil
 }
 
-const aliveTimeout = 5
+const (
+	// aliveTimeout is an interval between connection checks.
+	aliveTimeout = 5 * time.Second
+
+	// Reconnection attempts are delayed with exponential backoff between these values.
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = time.Minute
+)
 
+// KeepAliveDB checks the connection periodically and reconnects when it's lost.
+// Repositories share the pool, so they use the new connection right after reconnection.
 func (s *Store) KeepAliveDB(cfg *config.Config) {
-	var err error
+	ticker := time.NewTicker(aliveTimeout)
+	defer ticker.Stop()
 
 	for {
-		time.Sleep(aliveTimeout * time.Second)
-
-		lostConnection := false
-		if s.pg == nil {
-			lostConnection = true
-		} else if _, err := s.pg.Exec("SELECT 1;"); err != nil {
-			lostConnection = true
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
 		}
 
-		if !lostConnection {
+		ctx, cancel := context.WithTimeout(context.Background(), aliveTimeout)
+		err := s.pg.PingContext(ctx)
+		cancel()
+
+		if err == nil {
+			s.metrics.up.Set(1)
+
 			continue
 		}
 
-		s.logger.Debug().Msg("[store.KeepAliveDB] Lost db connection. Restoring...")
+		s.metrics.up.Set(0)
+		s.logger.Warn().Err(err).Msg("lost db connection, reconnecting")
 
-		s.pg, err = pg.Init(cfg)
-		if err != nil {
-			s.logger.Error().Err(err).Msg("init postgresql")
+		s.reconnect(cfg)
+	}
+}
 
-			continue
+// reconnect replaces the pool, failed attempts are retried with exponential backoff until the store is closed.
+func (s *Store) reconnect(cfg *config.Config) {
+	backoff := minReconnectBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := s.pg.Reconnect(cfg)
+		if err == nil {
+			s.metrics.reconnects.WithLabelValues(reconnectSucceeded).Inc()
+			s.metrics.up.Set(1)
+			s.logger.Info().Int("attempt", attempt).Msg("db reconnected")
+
+			return
 		}
 
-		s.logger.Debug().Msg("[store.KeepAliveDB] DB reconnected")
+		s.metrics.reconnects.WithLabelValues(reconnectFailed).Inc()
+		s.logger.Error().Err(err).Int("attempt", attempt).Dur("retry_in", backoff).Msg("reconnect to db")
+
+		select {
+		case <-s.done:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
 	}
 }
+
+// Close stops the connection keeper and closes the database connection.
+func (s *Store) Close() error {
+	close(s.done)
+
+	if s.pg == nil {
+		return nil
+	}
+
+	if err := s.pg.Close(); err != nil {
+		return fmt.Errorf("close postgresql: %w", err)
+	}
+
+	return nil
+}
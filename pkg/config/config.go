@@ -3,40 +3,318 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	PgUser         string
-	PgPassword     string
-	PgDB           string
-	PgHost         string
-	MigrationsPath string
-	Port           string
-	DatabaseURL    string
-	LogLevel       string
-	LogFilename    string
-	KafkaAddr      string
-	CookieSecret   string
+	PgUser            string
+	PgPassword        string
+	PgDB              string
+	PgHost            string
+	AutoMigrate       bool
+	Port              string
+	DatabaseURL       string
+	LogLevel          string
+	LogFilename       string
+	KafkaAddr         string
+	KafkaErrTopic     string
+	KafkaGroup        string
+	ChannelsFormat    string
+	MessagesFormat    string
+	DBQueryTimeout    time.Duration
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+	ShutdownTimeout   time.Duration
+	SessionTTL        time.Duration
+	CookieSecure      bool
+	CookieSameSite    string
+	BaseURL           string
+	PasswordResetTTL  time.Duration
+	Mailer            string
+	MailerDir         string
+	MailFrom          string
+	SMTPAddr          string
+	SMTPUsername      string
+	SMTPPassword      string
+	SigningKey        string
+	VerificationTTL   time.Duration
+	// Limits of failed logins, LOGIN_LOCKOUT is doubled with every failure over the limit.
+	LoginMaxFailures      int
+	LoginMaxFailuresPerIP int
+	LoginFailuresWindow   time.Duration
+	LoginLockout          time.Duration
+	// KafkaBufferDisabled keeps messages of disabled channels to replay them when the channel is enabled.
+	KafkaBufferDisabled bool
+	// FilterRulesFile is a JSON file with rules of the message filter, FilterQuarantine keeps filtered messages.
+	FilterRulesFile  string
+	FilterQuarantine bool
 }
 
+const (
+	// defaultDBQueryTimeout is used when DB_QUERY_TIMEOUT is not set.
+	defaultDBQueryTimeout = 10 * time.Second
+
+	// Defaults of the database connection pool.
+	defaultDBMaxOpenConns    = 25
+	defaultDBMaxIdleConns    = 5
+	defaultDBConnMaxLifetime = 30 * time.Minute
+	defaultDBConnMaxIdleTime = 5 * time.Minute
+
+	// defaultShutdownTimeout is used when SHUTDOWN_TIMEOUT is not set.
+	defaultShutdownTimeout = 15 * time.Second
+
+	// defaultKafkaGroup is used when KAFKA_CONSUMER_GROUP is not set.
+	defaultKafkaGroup = "scanner_backend"
+
+	// defaultSessionTTL is used when SESSION_TTL is not set.
+	defaultSessionTTL = 7 * 24 * time.Hour
+
+	// defaultCookieSameSite is used when COOKIE_SAME_SITE is not set.
+	defaultCookieSameSite = "lax"
+
+	// defaultPasswordResetTTL is used when PASSWORD_RESET_TTL is not set.
+	defaultPasswordResetTTL = time.Hour
+
+	// defaultVerificationTTL is used when EMAIL_VERIFICATION_TTL is not set.
+	defaultVerificationTTL = 48 * time.Hour
+
+	// Defaults of the failed logins limits.
+	defaultLoginMaxFailures      = 5
+	defaultLoginMaxFailuresPerIP = 20
+	defaultLoginFailuresWindow   = time.Hour
+	defaultLoginLockout          = time.Minute
+
+	// Defaults of the mailer which writes emails to the log.
+	defaultMailer    = "log"
+	defaultMailerDir = "mails"
+	defaultMailFrom  = "no-reply@localhost"
+)
+
 func Get() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		return nil, fmt.Errorf("get .env file error: %w", err)
 	}
 
+	dbQueryTimeout, err := getDuration("DB_QUERY_TIMEOUT", defaultDBQueryTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	autoMigrate, err := getBool("AUTO_MIGRATE", true)
+	if err != nil {
+		return nil, err
+	}
+
+	dbMaxOpenConns, err := getInt("DB_MAX_OPEN_CONNS", defaultDBMaxOpenConns)
+	if err != nil {
+		return nil, err
+	}
+
+	dbMaxIdleConns, err := getInt("DB_MAX_IDLE_CONNS", defaultDBMaxIdleConns)
+	if err != nil {
+		return nil, err
+	}
+
+	dbConnMaxLifetime, err := getDuration("DB_CONN_MAX_LIFETIME", defaultDBConnMaxLifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	dbConnMaxIdleTime, err := getDuration("DB_CONN_MAX_IDLE_TIME", defaultDBConnMaxIdleTime)
+	if err != nil {
+		return nil, err
+	}
+
+	shutdownTimeout, err := getDuration("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionTTL, err := getDuration("SESSION_TTL", defaultSessionTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	kafkaBufferDisabled, err := getBool("KAFKA_BUFFER_DISABLED_CHANNELS", false)
+	if err != nil {
+		return nil, err
+	}
+
+	filterQuarantine, err := getBool("MESSAGE_FILTER_QUARANTINE", false)
+	if err != nil {
+		return nil, err
+	}
+
+	cookieSecure, err := getBool("COOKIE_SECURE", true)
+	if err != nil {
+		return nil, err
+	}
+
+	cookieSameSite := strings.ToLower(os.Getenv("COOKIE_SAME_SITE"))
+	switch cookieSameSite {
+	case "":
+		cookieSameSite = defaultCookieSameSite
+	case "lax", "strict", "none":
+	default:
+		return nil, fmt.Errorf("parse COOKIE_SAME_SITE: unknown value %q", cookieSameSite)
+	}
+
+	passwordResetTTL, err := getDuration("PASSWORD_RESET_TTL", defaultPasswordResetTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationTTL, err := getDuration("EMAIL_VERIFICATION_TTL", defaultVerificationTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	loginMaxFailures, err := getInt("LOGIN_MAX_FAILURES", defaultLoginMaxFailures)
+	if err != nil {
+		return nil, err
+	}
+
+	loginMaxFailuresPerIP, err := getInt("LOGIN_MAX_FAILURES_PER_IP", defaultLoginMaxFailuresPerIP)
+	if err != nil {
+		return nil, err
+	}
+
+	loginFailuresWindow, err := getDuration("LOGIN_FAILURES_WINDOW", defaultLoginFailuresWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	loginLockout, err := getDuration("LOGIN_LOCKOUT", defaultLoginLockout)
+	if err != nil {
+		return nil, err
+	}
+
+	port := os.Getenv("PORT")
+
+	kafkaGroup := os.Getenv("KAFKA_CONSUMER_GROUP")
+	if kafkaGroup == "" {
+		kafkaGroup = defaultKafkaGroup
+	}
+
+	// EMAIL_VERIFICATION_KEY is the name of the signing key before it was renamed.
+	signingKey := getString("SIGNING_KEY", os.Getenv("EMAIL_VERIFICATION_KEY"))
+
 	return &Config{
-		PgUser:         os.Getenv("POSTGRES_USER"),
-		PgPassword:     os.Getenv("POSTGRES_PASSWORD"),
-		PgDB:           os.Getenv("POSTGRES_DB"),
-		PgHost:         os.Getenv("POSTGRES_HOST"),
-		MigrationsPath: os.Getenv("MIGRATIONS_PATH"),
-		Port:           os.Getenv("PORT"),
-		DatabaseURL:    os.Getenv("DATABASE_URL"),
-		LogLevel:       os.Getenv("LOG_LEVEL"),
-		LogFilename:    os.Getenv("LOG_FILENAME"),
-		KafkaAddr:      os.Getenv("KAFKA_ADDR"),
-		CookieSecret:   os.Getenv("COOKIE_SECRET"),
+		PgUser:            os.Getenv("POSTGRES_USER"),
+		PgPassword:        os.Getenv("POSTGRES_PASSWORD"),
+		PgDB:              os.Getenv("POSTGRES_DB"),
+		PgHost:            os.Getenv("POSTGRES_HOST"),
+		AutoMigrate:       autoMigrate,
+		Port:              port,
+		DatabaseURL:       os.Getenv("DATABASE_URL"),
+		LogLevel:          os.Getenv("LOG_LEVEL"),
+		LogFilename:       os.Getenv("LOG_FILENAME"),
+		KafkaAddr:         os.Getenv("KAFKA_ADDR"),
+		KafkaErrTopic:     os.Getenv("KAFKA_ERRORS_TOPIC"),
+		KafkaGroup:        kafkaGroup,
+		ChannelsFormat:    os.Getenv("KAFKA_CHANNELS_FORMAT"),
+		MessagesFormat:    os.Getenv("KAFKA_MESSAGES_FORMAT"),
+		DBQueryTimeout:    dbQueryTimeout,
+		DBMaxOpenConns:    dbMaxOpenConns,
+		DBMaxIdleConns:    dbMaxIdleConns,
+		DBConnMaxLifetime: dbConnMaxLifetime,
+		DBConnMaxIdleTime: dbConnMaxIdleTime,
+		ShutdownTimeout:   shutdownTimeout,
+		SessionTTL:        sessionTTL,
+		CookieSecure:      cookieSecure,
+		CookieSameSite:    cookieSameSite,
+		BaseURL:           getString("BASE_URL", "http://localhost:"+port),
+		PasswordResetTTL:  passwordResetTTL,
+		Mailer:            getString("MAILER", defaultMailer),
+		MailerDir:         getString("MAILER_DIR", defaultMailerDir),
+		MailFrom:          getString("MAIL_FROM", defaultMailFrom),
+		SMTPAddr:          os.Getenv("SMTP_ADDR"),
+		SMTPUsername:      os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:      os.Getenv("SMTP_PASSWORD"),
+		SigningKey:        signingKey,
+		VerificationTTL:   verificationTTL,
+
+		LoginMaxFailures:      loginMaxFailures,
+		LoginMaxFailuresPerIP: loginMaxFailuresPerIP,
+		LoginFailuresWindow:   loginFailuresWindow,
+		LoginLockout:          loginLockout,
+
+		KafkaBufferDisabled: kafkaBufferDisabled,
+
+		FilterRulesFile:  os.Getenv("MESSAGE_FILTER_RULES"),
+		FilterQuarantine: filterQuarantine,
 	}, nil
 }
+
+// getString returns value of the environment variable or the fallback when variable is empty.
+func getString(key string, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// getDuration parses duration from the environment variable or returns the fallback when variable is empty.
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	return duration, nil
+}
+
+// getInt parses integer from the environment variable or returns the fallback when variable is empty.
+func getInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	return number, nil
+}
+
+// getBool parses boolean from the environment variable or returns the fallback when variable is empty.
+func getBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("parse %s: %w", key, err)
+	}
+
+	return result, nil
+}
+
+// PostgresURL returns DATABASE_URL or the connection string built from the PostgreSQL fields.
+func (c *Config) PostgresURL() string {
+	if c.DatabaseURL != "" {
+		return c.DatabaseURL
+	}
+
+	return fmt.Sprintf(
+		"postgresql://%s/%s?user=%s&password=%s&sslmode=disable",
+		c.PgHost, c.PgDB, c.PgUser, c.PgPassword,
+	)
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"os"
 	"sync"
@@ -13,9 +14,12 @@ import (
 
 type Logger struct {
 	*zerolog.Logger
+
+	// file is set when records are written to the log file as well.
+	file io.Closer
 }
 
-func newFileWriter(filename string) io.Writer {
+func newFileWriter(filename string) *lumberjack.Logger {
 	return &lumberjack.Logger{
 		Filename: filename,
 	}
@@ -32,7 +36,10 @@ func Get(cfg *config.Config) *Logger {
 		writers := []io.Writer{zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.Stamp}}
 
 		if cfg.LogFilename != "" {
-			writers = append(writers, newFileWriter(cfg.LogFilename))
+			file := newFileWriter(cfg.LogFilename)
+
+			writers = append(writers, file)
+			logger.file = file
 		}
 
 		if cfg.LogLevel != "" {
@@ -48,8 +55,43 @@ func Get(cfg *config.Config) *Logger {
 
 		zeroLogger := zerolog.New(multiWriters).With().Timestamp().Logger()
 
-		logger = Logger{&zeroLogger}
+		logger.Logger = &zeroLogger
 	})
 
 	return &logger
 }
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of the context which carries request id.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns request id stored in the context or empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+
+	return requestID
+}
+
+// ForContext returns logger which adds request id from the context to every record.
+func (l *Logger) ForContext(ctx context.Context) *Logger {
+	requestID := RequestIDFromContext(ctx)
+	if requestID == "" {
+		return l
+	}
+
+	zeroLogger := l.With().Str("request_id", requestID).Logger()
+
+	return &Logger{Logger: &zeroLogger}
+}
+
+// Close flushes and closes the log file, it should be called once before exit.
+func (l *Logger) Close() error {
+	if l.file == nil {
+		return nil
+	}
+
+	return l.file.Close()
+}
@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+const filePermissions = 0o600
+
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9@._-]`)
+
+// FileMailer stores every email as a separate .eml file in the directory, so they can be opened by a mail client.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+var _ Mailer = (*FileMailer)(nil)
+
+func NewFileMailer(dir string, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+func (m FileMailer) Send(_ context.Context, message Message) error {
+	if err := os.MkdirAll(m.dir, os.ModePerm); err != nil {
+		return fmt.Errorf("create mails directory: %w", err)
+	}
+
+	sentAt := time.Now()
+	filename := fmt.Sprintf("%d_%s.eml", sentAt.UnixNano(), unsafeFilenameChars.ReplaceAllString(message.To, "_"))
+
+	err := os.WriteFile(filepath.Join(m.dir, filename), buildMessage(m.from, message, sentAt), filePermissions)
+	if err != nil {
+		return fmt.Errorf("write mail file: %w", err)
+	}
+
+	return nil
+}
+
+// buildMessage returns RFC 5322 message with plain text body.
+func buildMessage(from string, message Message, sentAt time.Time) []byte {
+	return []byte(fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		from, message.To, message.Subject, sentAt.Format(time.RFC1123Z), message.Body,
+	))
+}
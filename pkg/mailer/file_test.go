@@ -0,0 +1,36 @@
+package mailer_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VladPetriv/scanner_backend/pkg/mailer"
+)
+
+func Test_FileMailerSend(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "mails")
+
+	m := mailer.NewFileMailer(dir, "no-reply@test.com")
+
+	err := m.Send(context.Background(), mailer.Message{To: "test@test.com", Subject: "Hello", Body: "Hello, world!"})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Contains(t, files[0].Name(), "test@test.com")
+
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "From: no-reply@test.com\r\n")
+	assert.Contains(t, string(data), "To: test@test.com\r\n")
+	assert.Contains(t, string(data), "Subject: Hello\r\n")
+	assert.Contains(t, string(data), "\r\n\r\nHello, world!\r\n")
+}
@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+// LogMailer writes emails to the log instead of sending them.
+type LogMailer struct {
+	log *logger.Logger
+}
+
+var _ Mailer = (*LogMailer)(nil)
+
+func NewLogMailer(log *logger.Logger) *LogMailer {
+	return &LogMailer{log: log}
+}
+
+func (m LogMailer) Send(ctx context.Context, message Message) error {
+	m.log.ForContext(ctx).Info().
+		Str("to", message.To).
+		Str("subject", message.Subject).
+		Str("body", message.Body).
+		Msg("send email")
+
+	return nil
+}
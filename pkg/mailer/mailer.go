@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VladPetriv/scanner_backend/pkg/config"
+	"github.com/VladPetriv/scanner_backend/pkg/logger"
+)
+
+// Message is a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails to the web users.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// New returns mailer selected by the MAILER setting.
+// "log" and "file" mailers don't deliver anything and are meant for development.
+func New(cfg *config.Config, log *logger.Logger) (Mailer, error) {
+	switch cfg.Mailer {
+	case "", "log":
+		return NewLogMailer(log), nil
+	case "file":
+		return NewFileMailer(cfg.MailerDir, cfg.MailFrom), nil
+	case "smtp":
+		return NewSMTPMailer(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer: %q", cfg.Mailer)
+	}
+}
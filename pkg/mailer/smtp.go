@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer sends emails through the SMTP server, PLAIN authentication is used when username is set.
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+var _ Mailer = (*SMTPMailer)(nil)
+
+func NewSMTPMailer(addr string, username string, password string, from string) *SMTPMailer {
+	m := &SMTPMailer{addr: addr, from: from}
+
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return m
+}
+
+func (m SMTPMailer) Send(_ context.Context, message Message) error {
+	err := smtp.SendMail(m.addr, m.auth, m.from, []string{message.To}, buildMessage(m.from, message, time.Now()))
+	if err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+
+	return nil
+}
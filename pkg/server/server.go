@@ -1,8 +1,11 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -10,10 +13,12 @@ import (
 const operationTimeout = 10
 
 type Server struct {
+	mu         sync.Mutex
 	httpServer *http.Server
 }
 
 func (s *Server) Run(port string, handler http.Handler) error {
+	s.mu.Lock()
 	s.httpServer = &http.Server{
 		Addr:         ":" + port,
 		Handler:      handler,
@@ -21,10 +26,30 @@ func (s *Server) Run(port string, handler http.Handler) error {
 		WriteTimeout: operationTimeout * time.Second,
 	}
 
-	err := s.httpServer.ListenAndServe()
-	if err != nil {
+	httpServer := s.httpServer
+	s.mu.Unlock()
+
+	err := httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("start server: %w", err)
 	}
 
 	return nil
 }
+
+// Shutdown stops accepting new connections and waits for in-flight requests until the context is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	httpServer := s.httpServer
+	s.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	if err := httpServer.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown server: %w", err)
+	}
+
+	return nil
+}
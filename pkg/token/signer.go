@@ -0,0 +1,73 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token is expired")
+)
+
+// Signer issues tokens which carry data together with the expiration time, so they can be verified without db.
+type Signer struct {
+	key []byte
+}
+
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign returns URL safe token in the "<data>.<expiration>.<signature>" format, data is base64 encoded.
+func (s Signer) Sign(data string, expiresAt time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(data)) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return payload + "." + s.signature(payload)
+}
+
+// Verify checks signature and expiration of the token and returns its data.
+func (s Signer) Verify(token string, now time.Time) (string, error) {
+	separator := strings.LastIndex(token, ".")
+	if separator == -1 {
+		return "", ErrInvalidSignature
+	}
+
+	payload, signature := token[:separator], token[separator+1:]
+	if !hmac.Equal([]byte(signature), []byte(s.signature(payload))) {
+		return "", ErrInvalidSignature
+	}
+
+	encoded, expiration, found := strings.Cut(payload, ".")
+	if !found {
+		return "", ErrInvalidSignature
+	}
+
+	expiresAt, err := strconv.ParseInt(expiration, 10, 64)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	if now.Unix() >= expiresAt {
+		return "", ErrTokenExpired
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	return string(data), nil
+}
+
+func (s Signer) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
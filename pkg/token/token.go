@@ -0,0 +1,30 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// DefaultLength is a length of generated tokens in bytes.
+const DefaultLength = 32
+
+// Generate returns URL safe random token which has the given number of random bytes.
+func Generate(length int) (string, error) {
+	data := make([]byte, length)
+
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("read random bytes: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Hash returns SHA-256 hash of the token, only hashes of the tokens are stored in db.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+package token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/VladPetriv/scanner_backend/pkg/token"
+)
+
+func Test_Generate(t *testing.T) {
+	t.Parallel()
+
+	first, err := token.Generate(token.DefaultLength)
+	assert.NoError(t, err)
+
+	second, err := token.Generate(token.DefaultLength)
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+}
+
+func Test_Hash(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", token.Hash("test"))
+	assert.Len(t, token.Hash("another"), 64)
+}
+
+func Test_Signer(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 10, 10, 10, 0, 0, 0, time.UTC)
+	signer := token.NewSigner([]byte("secret"))
+
+	signed := signer.Sign("1:test@test.com", now.Add(time.Hour))
+
+	tests := []struct {
+		name          string
+		token         string
+		now           time.Time
+		want          string
+		expectedError error
+	}{
+		{
+			name:  "Verify successful",
+			token: signed,
+			now:   now,
+			want:  "1:test@test.com",
+		},
+		{
+			name:          "Verify failed with expired token",
+			token:         signed,
+			now:           now.Add(time.Hour),
+			expectedError: token.ErrTokenExpired,
+		},
+		{
+			name:          "Verify failed with changed data",
+			token:         signer.Sign("2:test@test.com", now.Add(time.Hour))[:10] + signed[10:],
+			now:           now,
+			expectedError: token.ErrInvalidSignature,
+		},
+		{
+			name:          "Verify failed with another key",
+			token:         token.NewSigner([]byte("another")).Sign("1:test@test.com", now.Add(time.Hour)),
+			now:           now,
+			expectedError: token.ErrInvalidSignature,
+		},
+		{
+			name:          "Verify failed with malformed token",
+			token:         "malformed",
+			now:           now,
+			expectedError: token.ErrInvalidSignature,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := signer.Verify(tt.token, tt.now)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Package totp implements time-based one-time passwords(RFC 6238) compatible with authenticator apps.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // SHA-1 is the default algorithm of RFC 6238 supported by all authenticator apps
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// secretLength is a length of generated secret in bytes, RFC 4226 recommends 160 bits.
+	secretLength = 20
+
+	// Digits is a number of digits in the code.
+	Digits = 6
+
+	// Period is a time step of the code.
+	Period = 30 * time.Second
+
+	// skew is a number of time steps before and after the current one which codes are accepted,
+	// it compensates clock drift of the device.
+	skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	data := make([]byte, secretLength)
+
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("read random bytes: %w", err)
+	}
+
+	return encoding.EncodeToString(data), nil
+}
+
+// Code returns code of the time step which the given time belongs to.
+func Code(secret string, at time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("decode secret: %w", err)
+	}
+
+	return code(key, uint64(at.Unix())/uint64(Period.Seconds())), nil
+}
+
+// Validate checks the code against the current time step and its neighbours.
+func Validate(secret string, passcode string, at time.Time) bool {
+	_, ok := ValidateCounter(secret, passcode, at)
+
+	return ok
+}
+
+// ValidateCounter checks the code like Validate and returns the time step it belongs to,
+// callers keep the last accepted step to reject replays of the same code.
+func ValidateCounter(secret string, passcode string, at time.Time) (uint64, bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(passcode) != Digits {
+		return 0, false
+	}
+
+	counter := uint64(at.Unix()) / uint64(Period.Seconds())
+
+	for offset := -skew; offset <= skew; offset++ {
+		expected := code(key, counter+uint64(offset))
+
+		if hmac.Equal([]byte(expected), []byte(passcode)) {
+			return counter + uint64(offset), true
+		}
+	}
+
+	return 0, false
+}
+
+// ProvisioningURI returns otpauth URI which authenticator apps import from the QR code.
+func ProvisioningURI(issuer string, account string, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(Period.Seconds()))
+
+	label := url.PathEscape(issuer + ":" + account)
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// code implements HOTP(RFC 4226) for the counter.
+func code(key []byte, counter uint64) string {
+	message := make([]byte, 8) //nolint:gomnd // counter is 8 bytes
+	binary.BigEndian.PutUint64(message, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < Digits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%modulo)
+}
@@ -0,0 +1,98 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/VladPetriv/scanner_backend/pkg/totp"
+)
+
+// rfcSecret is the SHA-1 secret from RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func Test_Code(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		at   int64
+		want string
+	}{
+		{at: 59, want: "287082"},
+		{at: 1111111109, want: "081804"},
+		{at: 1111111111, want: "050471"},
+		{at: 1234567890, want: "005924"},
+		{at: 2000000000, want: "279037"},
+	}
+	for _, tt := range tests {
+		got, err := totp.Code(rfcSecret, time.Unix(tt.at, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func Test_Validate(t *testing.T) {
+	t.Parallel()
+
+	at := time.Unix(1111111111, 0)
+
+	assert.True(t, totp.Validate(rfcSecret, "050471", at))
+	assert.True(t, totp.Validate(rfcSecret, "050471", at.Add(totp.Period)))
+	assert.False(t, totp.Validate(rfcSecret, "050471", at.Add(3*totp.Period)))
+	assert.False(t, totp.Validate(rfcSecret, "000000", at))
+	assert.False(t, totp.Validate(rfcSecret, "50471", at))
+	assert.False(t, totp.Validate("not base32!", "050471", at))
+}
+
+func Test_ValidateCounter(t *testing.T) {
+	t.Parallel()
+
+	at := time.Unix(1111111111, 0)
+
+	counter, ok := totp.ValidateCounter(rfcSecret, "050471", at)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1111111111/30), counter)
+
+	counter, ok = totp.ValidateCounter(rfcSecret, "050471", at.Add(totp.Period))
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1111111111/30), counter)
+
+	_, ok = totp.ValidateCounter(rfcSecret, "000000", at)
+	assert.False(t, ok)
+}
+
+func Test_GenerateSecret(t *testing.T) {
+	t.Parallel()
+
+	first, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	second, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second)
+
+	code, err := totp.Code(first, time.Now())
+	require.NoError(t, err)
+	assert.True(t, totp.Validate(first, code, time.Now()))
+}
+
+func Test_ProvisioningURI(t *testing.T) {
+	t.Parallel()
+
+	uri, err := url.Parse(totp.ProvisioningURI("Telegram Overflow", "test@test.com", "SECRET"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Telegram Overflow:test@test.com", uri.Path)
+	assert.Equal(t, "SECRET", uri.Query().Get("secret"))
+	assert.Equal(t, "Telegram Overflow", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}